@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/language"
+)
+
+// languageBatch is how many bottles `language backfill` detects at a time.
+const languageBatch = 500
+
+func runLanguage(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 1 || args[0] != "backfill" {
+		return errUsage
+	}
+
+	detector := language.NewNGram()
+	detected := 0
+	for {
+		bottles, err := env.repo.Bottle.GetUndetectedBottles(ctx, languageBatch)
+		if err != nil {
+			return err
+		}
+		if len(bottles) == 0 {
+			break
+		}
+
+		for _, bottle := range bottles {
+			read := detector.Detect(bottle.Content)
+			if err := env.repo.Bottle.SetBottleLanguage(ctx, bottle.ID, read.Language); err != nil {
+				return err
+			}
+		}
+		detected += len(bottles)
+	}
+
+	fmt.Printf("Detected the language of %d bottle(s)\n", detected)
+	return nil
+}
@@ -0,0 +1,136 @@
+// Command admin runs operator tasks against the same database and configuration as the server,
+// so that nobody has to hand-write SQL against production.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/config"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"hackmit/internal/storage/postgres"
+	"log"
+	"os"
+	osuser "os/user"
+)
+
+const usage = `usage: admin <command> [arguments]
+
+commands:
+  migrate up|down [n]|status          apply, roll back or list schema migrations
+  seed                                insert the system tags and default ocean if missing
+  moderation list [-status s]         list bottles awaiting review (or in status s)
+  moderation approve [-reason r] <bottle-id>
+                                      set a bottle afloat
+  moderation reject [-reason r] <bottle-id>
+                                      reject a pending bottle or remove an afloat one
+  user delete [-yes] [-keep-bottles] [-reason r] <user-id>
+                                      delete a user's account and data now
+  user anonymize [-reason r] <user-id>
+                                      strip a user's name from them and their bottles
+  user role [-reason r] <user-id> <role>
+                                      make a user a user, moderator or admin
+  ocean export [-o file] <ocean-id>   write an ocean, its tags and bottles as JSON
+  stats recompute                     rebuild catch statistics from catch history
+  mood backfill                       read the mood of bottles thrown before moods were read
+  language backfill                   detect the language of bottles thrown before languages were
+  tag train [-holdout n] [-dry-run]   train the tag classifier on tagged bottles and report how it does
+  tag report                          show how the tag model in use did when it was trained`
+
+// errUsage means the arguments didn't form a valid command; the usage text is shown.
+var errUsage = errors.New("invalid arguments")
+
+// command is a subcommand handler. args exclude the command's own name.
+type command func(ctx context.Context, env *environment, args []string) error
+
+// environment is what every subcommand runs against.
+type environment struct {
+	config   config.Config
+	repo     *storage.Repository
+	identity auth.IdentityProvider
+}
+
+// audit records an operator's action in the audit log. Operators have no user ID, so the
+// system account running the command stands in for who made it.
+func (env *environment) audit(ctx context.Context, repo *storage.Repository, entry models.AuditEntry) error {
+	if entry.Details == nil {
+		entry.Details = map[string]any{}
+	}
+	if operator, err := osuser.Current(); err == nil {
+		entry.Details["operator"] = operator.Username
+	}
+	_, err := repo.Audit.RecordAudit(ctx, entry)
+	return err
+}
+
+// reasonFlag adds the -reason flag operators explain their actions with.
+func reasonFlag(flags *flag.FlagSet) *string {
+	return flags.String("reason", "", "why, for the audit log")
+}
+
+// optional is nil for an empty string.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+var commands = map[string]command{
+	"migrate":    runMigrate,
+	"seed":       runSeed,
+	"moderation": runModeration,
+	"user":       runUser,
+	"ocean":      runOcean,
+	"stats":      runStats,
+	"mood":       runMood,
+	"language":   runLanguage,
+	"tag":        runTag,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	cfg, err := config.Load(ctx, true)
+	if err != nil {
+		log.Fatalln("Error processing .env file: ", err)
+	}
+
+	repo := postgres.NewRepository(ctx, cfg.DB)
+	identity, err := auth.NewProvider(cfg, repo.Identity)
+	if err != nil {
+		log.Fatalln("Error setting up the identity provider: ", err)
+	}
+
+	env := &environment{
+		config:   cfg,
+		repo:     repo,
+		identity: identity,
+	}
+
+	err = run(ctx, env, os.Args[2:])
+	env.repo.Close()
+
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "admin:", err)
+		os.Exit(1)
+	}
+}
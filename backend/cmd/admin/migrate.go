@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hackmit/internal/migrate"
+	"hackmit/internal/models"
+	"hackmit/internal/supabase"
+	"os"
+)
+
+func runMigrate(ctx context.Context, env *environment, args []string) error {
+	migrator, err := migrate.New(env.repo.GetDB(), supabase.Files)
+	if err != nil {
+		return fmt.Errorf("error loading migrations: %w", err)
+	}
+
+	err = migrate.Run(ctx, migrator, args, os.Stdout)
+	if errors.Is(err, migrate.ErrUsage) {
+		return errUsage
+	}
+	return err
+}
+
+func runSeed(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	migrator, err := migrate.New(env.repo.GetDB(), supabase.Files)
+	if err != nil {
+		return fmt.Errorf("error loading migrations: %w", err)
+	}
+
+	if err := migrator.Seed(ctx); err != nil {
+		return err
+	}
+
+	// Seeding only inserts what is missing, so there is no telling which tags were new
+	err = env.audit(ctx, env.repo, models.AuditEntry{
+		Action:     models.AuditTagsSeeded,
+		TargetType: models.AuditTargetTag,
+	})
+	if err != nil {
+		return fmt.Errorf("seeded but could not record it: %w", err)
+	}
+
+	fmt.Println("Seeded system tags and default ocean")
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hackmit/internal/models"
+	"hackmit/internal/notify"
+	"hackmit/internal/storage"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runModeration(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return listModeration(ctx, env, args[1:])
+	case "approve":
+		return moderate(ctx, env, args[1:], true)
+	case "reject":
+		return moderate(ctx, env, args[1:], false)
+	default:
+		return errUsage
+	}
+}
+
+func listModeration(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("moderation list", flag.ContinueOnError)
+	status := flags.String("status", string(models.BottleStatusPending), "only list bottles in this status")
+	limit := flags.Int("limit", models.DefaultPageLimit, "maximum number of bottles to list")
+	offset := flags.Int("offset", 0, "number of bottles to skip")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	bottleStatus := models.BottleStatus(*status)
+	page := models.PaginationRequest{Limit: *limit, Offset: *offset}
+	page.Normalize()
+
+	bottles, err := env.repo.Bottle.ListBottles(ctx, models.ListBottlesRequest{Status: &bottleStatus}, page)
+	if err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tSTATUS\tCREATED AT\tUSER\tCONTENT")
+	for _, bottle := range bottles {
+		user := "-"
+		if bottle.UserID != nil {
+			user = bottle.UserID.String()
+		}
+		content := strings.Join(strings.Fields(bottle.Content), " ")
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", bottle.ID, bottle.Status, bottle.CreatedAt.Format(time.RFC3339), user, content)
+	}
+	return table.Flush()
+}
+
+// moderate approves or rejects a bottle and tells its author. Approving sets any bottle afloat;
+// rejecting turns down a pending bottle or pulls an afloat one out of the ocean.
+func moderate(ctx context.Context, env *environment, args []string, approve bool) error {
+	flags := flag.NewFlagSet("moderation", flag.ContinueOnError)
+	reason := reasonFlag(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	bottleId, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid bottle id: %s", flags.Arg(0))
+	}
+
+	bottle, err := env.repo.Bottle.GetBottleByID(ctx, bottleId)
+	if err != nil {
+		return err
+	}
+
+	var status models.BottleStatus
+	switch {
+	case approve:
+		status = models.BottleStatusAfloat
+	case bottle.Status == models.BottleStatusPending:
+		status = models.BottleStatusRejected
+	case bottle.Status == models.BottleStatusAfloat:
+		status = models.BottleStatusRemoved
+	default:
+		return fmt.Errorf("bottle %d is already %s", bottle.ID, bottle.Status)
+	}
+
+	if bottle.Status == status {
+		return fmt.Errorf("bottle %d is already %s", bottle.ID, bottle.Status)
+	}
+
+	previous := bottle.Status
+	err = env.repo.WithTx(ctx, func(tx *storage.Repository) error {
+		if bottle, err = tx.Bottle.SetBottleStatus(ctx, bottle.ID, status); err != nil {
+			return err
+		}
+		target := strconv.Itoa(bottle.ID)
+		return env.audit(ctx, tx, models.AuditEntry{
+			Action:     models.AuditBottleModerated,
+			TargetType: models.AuditTargetBottle,
+			TargetID:   &target,
+			Reason:     optional(*reason),
+			Details:    map[string]any{"previous_status": previous, "status": bottle.Status},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	notify.NewNotifier(env.repo.Notification, env.repo.Block).BottleModerated(ctx, *bottle)
+
+	fmt.Printf("Bottle %d is now %s\n", bottle.ID, bottle.Status)
+	return nil
+}
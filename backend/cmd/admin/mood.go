@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/mood"
+)
+
+// moodBatch is how many bottles `mood backfill` reads at a time.
+const moodBatch = 500
+
+func runMood(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 1 || args[0] != "backfill" {
+		return errUsage
+	}
+
+	classifier := mood.NewLexicon()
+	classified := 0
+	for {
+		bottles, err := env.repo.Bottle.GetUnclassifiedBottles(ctx, moodBatch)
+		if err != nil {
+			return err
+		}
+		if len(bottles) == 0 {
+			break
+		}
+
+		for _, bottle := range bottles {
+			read := classifier.Classify(bottle.Content)
+			if err := env.repo.Bottle.SetBottleMood(ctx, bottle.ID, read.Mood, read.Score); err != nil {
+				return err
+			}
+		}
+		classified += len(bottles)
+	}
+
+	fmt.Printf("Read the mood of %d bottle(s)\n", classified)
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hackmit/internal/models"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// oceanExport is the JSON document written by `ocean export`.
+type oceanExport struct {
+	ExportedAt time.Time                `json:"exported_at"`
+	Ocean      models.Ocean             `json:"ocean"`
+	Tags       []models.Tag             `json:"tags"`
+	Bottles    []models.ModeratedBottle `json:"bottles"`
+}
+
+func runOcean(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("ocean export", flag.ContinueOnError)
+	output := flags.String("o", "", "file to write the export to (default stdout)")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	oceanId, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid ocean id: %s", flags.Arg(0))
+	}
+
+	ocean, err := env.repo.Ocean.GetOceanById(ctx, oceanId)
+	if err != nil {
+		return err
+	}
+
+	tags, err := env.repo.Tag.GetTagsByOcean(ctx, oceanId)
+	if err != nil {
+		return err
+	}
+
+	export := oceanExport{
+		ExportedAt: time.Now().UTC(),
+		Ocean:      *ocean,
+		Tags:       tags,
+		Bottles:    []models.ModeratedBottle{},
+	}
+
+	// Every bottle, whatever its status, a page at a time
+	page := models.PaginationRequest{Limit: models.MaxPageLimit}
+	for {
+		bottles, err := env.repo.Bottle.ListBottles(ctx, models.ListBottlesRequest{OceanID: &oceanId}, page)
+		if err != nil {
+			return err
+		}
+		for _, bottle := range bottles {
+			export.Bottles = append(export.Bottles, bottle.Moderated())
+		}
+		if len(bottles) < page.Limit {
+			break
+		}
+		page.Offset += page.Limit
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("error writing export: %w", err)
+	}
+
+	if *output != "" {
+		fmt.Printf("Exported ocean %d with %d bottle(s) to %s\n", oceanId, len(export.Bottles), *output)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func runStats(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 1 || args[0] != "recompute" {
+		return errUsage
+	}
+
+	updated, err := env.repo.Bottle.RecomputeStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Recomputed catch statistics, %d bottle(s) corrected\n", updated)
+	return nil
+}
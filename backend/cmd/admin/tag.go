@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hackmit/internal/models"
+	"hackmit/internal/tagging"
+	"os"
+	"slices"
+)
+
+// trainingBottles is how many of the newest tagged bottles `tag train` learns from.
+const trainingBottles = 50000
+
+func runTag(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "train":
+		return trainTags(ctx, env, args[1:])
+	case "report":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return reportTags(ctx, env)
+	default:
+		return errUsage
+	}
+}
+
+// trainTags scores a model trained on all but a share of the tagged bottles on that share,
+// then trains the model the server will use on all of them.
+func trainTags(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("tag train", flag.ContinueOnError)
+	holdout := flags.Int("holdout", 5, "test on every nth tagged bottle")
+	dryRun := flags.Bool("dry-run", false, "print the report without saving the model")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	bottles, err := env.repo.Bottle.GetTaggedBottles(ctx, trainingBottles)
+	if err != nil {
+		return err
+	}
+	// Oldest first, so that the same bottles are held out from one run to the next
+	slices.Reverse(bottles)
+	examples := make([]tagging.Example, len(bottles))
+	for i, bottle := range bottles {
+		examples[i] = tagging.Example{Text: bottle.Content, TagID: bottle.TagID}
+	}
+
+	report, err := tagging.Evaluate(examples, *holdout)
+	if err != nil {
+		return err
+	}
+	model, err := tagging.Train(examples)
+	if err != nil {
+		return err
+	}
+
+	names, err := tagNames(ctx, env)
+	if err != nil {
+		return err
+	}
+	report.Write(os.Stdout, names)
+	if *dryRun {
+		return nil
+	}
+
+	encodedModel, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	encodedReport, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	saved, err := env.repo.Tag.SaveTagModel(ctx, models.TagModel{
+		Model:    encodedModel,
+		Report:   encodedReport,
+		Examples: len(examples),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSaved tag model %d, learnt from %d bottle(s)\n", saved.ID, saved.Examples)
+	return nil
+}
+
+func reportTags(ctx context.Context, env *environment) error {
+	latest, err := env.repo.Tag.GetLatestTagModel(ctx)
+	if err != nil {
+		return err
+	}
+	var report tagging.Report
+	if err := json.Unmarshal(latest.Report, &report); err != nil {
+		return fmt.Errorf("error reading the report of tag model %d: %w", latest.ID, err)
+	}
+
+	names, err := tagNames(ctx, env)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tag model %d, trained %s on %d bottle(s)\n", latest.ID, latest.TrainedAt.Format("2006-01-02 15:04"), latest.Examples)
+	report.Write(os.Stdout, names)
+	return nil
+}
+
+func tagNames(ctx context.Context, env *environment) (map[int]string, error) {
+	includeDefault := true
+	tags, err := env.repo.Tag.GetTags(ctx, models.GetTagsRequest{IncludeDefault: &includeDefault})
+	if err != nil {
+		return nil, err
+	}
+	names := map[int]string{}
+	for _, tag := range tags {
+		if tag.Name != nil {
+			names[tag.ID] = *tag.Name
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hackmit/internal/account"
+	"hackmit/internal/avatar"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+func runUser(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "delete":
+		return deleteUser(ctx, env, args[1:])
+	case "anonymize":
+		return anonymizeUser(ctx, env, args[1:])
+	case "role":
+		return setUserRole(ctx, env, args[1:])
+	default:
+		return errUsage
+	}
+}
+
+// deleteUser deletes a user's account at once, skipping the grace period: their login account,
+// profile, personal ocean and history, and their bottles unless -keep-bottles leaves them
+// afloat anonymized.
+func deleteUser(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("user delete", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "actually delete the user")
+	keepBottles := flags.Bool("keep-bottles", false, "keep the user's bottles afloat anonymized")
+	reason := reasonFlag(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	userId, err := uuid.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid user id: %s", flags.Arg(0))
+	}
+
+	user, err := env.repo.User.GetUserProfile(ctx, userId.String())
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Printf("Would delete user %s (%s). Re-run with -yes to delete.\n", user.ID, user.Email)
+		return nil
+	}
+
+	deleter := account.NewDeleter(env.repo.User, env.identity, avatar.NewStore(env.config.Uploads.Dir, env.config.Uploads.MaxAvatarSize), 0)
+	if err := deleter.Delete(ctx, models.AccountDeletion{UserID: userId, KeepBottles: *keepBottles}); err != nil {
+		return err
+	}
+
+	target := userId.String()
+	err = env.audit(ctx, env.repo, models.AuditEntry{
+		Action:     models.AuditUserDeleted,
+		TargetType: models.AuditTargetUser,
+		TargetID:   &target,
+		Reason:     optional(*reason),
+		Details:    map[string]any{"keep_bottles": *keepBottles},
+	})
+	if err != nil {
+		return fmt.Errorf("deleted user %s but could not record it: %w", userId, err)
+	}
+
+	fmt.Printf("Deleted user %s\n", userId)
+	return nil
+}
+
+func anonymizeUser(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("user anonymize", flag.ContinueOnError)
+	reason := reasonFlag(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	userId, err := uuid.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid user id: %s", flags.Arg(0))
+	}
+
+	var detached int64
+	err = env.repo.WithTx(ctx, func(tx *storage.Repository) error {
+		if detached, err = tx.User.AnonymizeUser(ctx, userId); err != nil {
+			return err
+		}
+		target := userId.String()
+		return env.audit(ctx, tx, models.AuditEntry{
+			Action:     models.AuditUserAnonymized,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &target,
+			Reason:     optional(*reason),
+			Details:    map[string]any{"bottles": detached},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Anonymized user %s and %d bottle(s)\n", userId, detached)
+	return nil
+}
+
+// setUserRole changes a user's role. It is how the first admin is made; after that, admins can
+// change roles through the API. The change is recorded as made by nobody.
+func setUserRole(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("user role", flag.ContinueOnError)
+	reason := reasonFlag(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+
+	userId, err := uuid.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid user id: %s", flags.Arg(0))
+	}
+	role := models.Role(flags.Arg(1))
+	if !role.Valid() {
+		return fmt.Errorf("invalid role %q, want one of %v", flags.Arg(1), models.Roles)
+	}
+
+	var change *models.RoleChange
+	err = env.repo.WithTx(ctx, func(tx *storage.Repository) error {
+		if change, err = tx.User.SetUserRole(ctx, userId, role, nil); err != nil {
+			return err
+		}
+		target := userId.String()
+		return env.audit(ctx, tx, models.AuditEntry{
+			Action:     models.AuditUserRoleChanged,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &target,
+			Reason:     optional(*reason),
+			Details:    map[string]any{"old_role": change.OldRole, "new_role": change.NewRole},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Changed the role of user %s from %s to %s\n", userId, change.OldRole, change.NewRole)
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"hackmit/internal/config"
 	"hackmit/internal/service"
 	"log"
@@ -9,17 +10,33 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-
-	"github.com/sethvargo/go-envconfig"
 )
 
 func main() {
-	var config config.Config
-	if err := envconfig.Process(context.Background(), &config); err != nil {
+	inMemory := flag.Bool("memory", false, "run on an in-memory store instead of Postgres; all data is lost on exit")
+	flag.Parse()
+
+	// No database settings are needed without a database
+	config, err := config.Load(context.Background(), !*inMemory)
+	if err != nil {
 		log.Fatalln("Error processing .env file: ", err)
 	}
 
-	app := service.InitApp(config)
+	if flag.Arg(0) == "migrate" {
+		if *inMemory {
+			log.Fatalln("There is nothing to migrate in memory")
+		}
+		runMigrate(config, flag.Args()[1:])
+		return
+	}
+
+	var app *service.App
+	if *inMemory {
+		slog.Warn("Running on an in-memory store; all data will be lost on exit")
+		app = service.InitMemoryApp(config)
+	} else {
+		app = service.InitApp(config)
+	}
 
 	// Pushing the closing of the database connection onto a
 	// stack of statements to be executed when this function returns.
@@ -41,8 +58,13 @@ func main() {
 	// Wait for the termination signal:
 	<-quit
 
-	// Then shutdown server gracefully.
+	// Then shutdown server gracefully, ending live streams first so they don't hold it open.
 	slog.Info("Shutting down server")
+	app.Hub.Close()
+	app.Deleter.Close()
+	app.Exporter.Close()
+	app.AuditPruner.Close()
+	app.Tags.Close()
 	if err := app.Server.Shutdown(); err != nil {
 		slog.Error("failed to shutdown server", "error", err)
 	}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hackmit/internal/config"
+	"hackmit/internal/migrate"
+	"hackmit/internal/storage/postgres"
+	"hackmit/internal/supabase"
+	"log"
+	"os"
+)
+
+// runMigrate handles `main migrate ...` so schema changes can be run without starting the server.
+func runMigrate(cfg config.Config, args []string) {
+	ctx := context.Background()
+	db, err := postgres.ConnectDatabase(ctx, cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, supabase.Files)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	err = migrate.Run(ctx, migrator, args, os.Stdout)
+	if errors.Is(err, migrate.ErrUsage) {
+		fmt.Println("usage: main migrate <command>\n\n" + migrate.Usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+// Package account deletes accounts once the grace period their owners had to change their
+// minds is over.
+package account
+
+import (
+	"context"
+	"errors"
+	"hackmit/internal/auth"
+	"hackmit/internal/avatar"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sweepInterval is how often the deleter looks for deletions that are due.
+const sweepInterval = 10 * time.Minute
+
+// Deleter schedules account deletions and carries them out once they are due.
+type Deleter struct {
+	users    storage.UserRepository
+	identity auth.IdentityProvider
+	avatars  *avatar.Store
+	grace    time.Duration
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func NewDeleter(users storage.UserRepository, identity auth.IdentityProvider, avatars *avatar.Store, grace time.Duration) *Deleter {
+	return &Deleter{
+		users:    users,
+		identity: identity,
+		avatars:  avatars,
+		grace:    grace,
+	}
+}
+
+// Schedule deletes the user's account once the grace period is over, unless they cancel first.
+func (d *Deleter) Schedule(ctx context.Context, userID uuid.UUID, keepBottles bool) (*models.AccountDeletion, error) {
+	return d.users.ScheduleDeletion(ctx, userID, keepBottles, time.Now().Add(d.grace))
+}
+
+// Delete removes the user's login account, then their data and avatar. A deletion that fails
+// part way is left scheduled, so the next sweep picks up where it stopped.
+func (d *Deleter) Delete(ctx context.Context, deletion models.AccountDeletion) error {
+	if err := d.identity.DeleteUser(ctx, deletion.UserID); err != nil && !errs.IsNotFound(err) {
+		return err
+	}
+
+	profile, err := d.users.GetUserProfile(ctx, deletion.UserID.String())
+	if err != nil {
+		return err
+	}
+
+	bottles, err := d.users.PurgeUser(ctx, deletion.UserID, deletion.KeepBottles)
+	if err != nil {
+		return err
+	}
+
+	// The user is gone either way, so files that can't be removed are only logged
+	if profile.AvatarKey != nil {
+		if err := d.avatars.Delete(*profile.AvatarKey); err != nil {
+			slog.Error("failed to delete avatar", "user_id", deletion.UserID, "error", err)
+		}
+	}
+
+	slog.Info("deleted account", "user_id", deletion.UserID, "bottles", bottles, "kept_bottles", deletion.KeepBottles)
+	return nil
+}
+
+// DeleteDue carries out every deletion whose grace period is over, returning how many were.
+func (d *Deleter) DeleteDue(ctx context.Context) (int, error) {
+	due, err := d.users.GetDueDeletions(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	var failed error
+	for _, deletion := range due {
+		if err := d.Delete(ctx, deletion); err != nil {
+			slog.Error("failed to delete account", "user_id", deletion.UserID, "error", err)
+			failed = errors.Join(failed, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, failed
+}
+
+// Start sweeps for due deletions in the background until Close.
+func (d *Deleter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.done.Add(1)
+	go d.sweep(ctx)
+}
+
+// Close stops sweeping and waits for a sweep in progress to finish.
+func (d *Deleter) Close() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.done.Wait()
+}
+
+func (d *Deleter) sweep(ctx context.Context) {
+	defer d.done.Done()
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are logged by DeleteDue and retried on the next sweep
+		d.DeleteDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package account_test
+
+import (
+	"bytes"
+	"context"
+	"hackmit/internal/account"
+	"hackmit/internal/auth"
+	"hackmit/internal/avatar"
+	"hackmit/internal/config"
+	"hackmit/internal/storage/memory"
+	"image"
+	"image/png"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeleter(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository(nil)
+	uploads := t.TempDir()
+	avatars := avatar.NewStore(uploads, 1<<20)
+	provider, err := auth.NewLocalProvider(
+		config.Auth{JWTSecret: "test-secret", AccessTokenTTL: time.Hour, RefreshTokenTTL: time.Hour},
+		config.Application{Environment: "test"},
+		repo.Identity,
+	)
+	if err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+
+	signUp := func(email string) *auth.Session {
+		t.Helper()
+		session, err := provider.SignUp(ctx, email, "hunter22")
+		if err != nil {
+			t.Fatalf("SignUp: %v", err)
+		}
+		if _, err := repo.User.AddUser(ctx, session.User.ID.String(), nil, nil, email); err != nil {
+			t.Fatalf("AddUser: %v", err)
+		}
+		return session
+	}
+	leaving, waiting := signUp("leaving@example.com"), signUp("waiting@example.com")
+
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, image.NewGray(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	key, err := avatars.Save(leaving.User.ID, &upload)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := repo.User.SetUserAvatar(ctx, leaving.User.ID, &key); err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+
+	if _, err := account.NewDeleter(repo.User, provider, avatars, 0).Schedule(ctx, leaving.User.ID, false); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	deleter := account.NewDeleter(repo.User, provider, avatars, time.Hour)
+	if _, err := deleter.Schedule(ctx, waiting.User.ID, false); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	deleted, err := deleter.DeleteDue(ctx)
+	if err != nil {
+		t.Fatalf("DeleteDue: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteDue deleted %d accounts, want 1", deleted)
+	}
+
+	// The account due is gone, login and all; the one still in its grace period is untouched
+	if _, err := repo.User.GetUserProfile(ctx, leaving.User.ID.String()); err == nil {
+		t.Error("GetUserProfile found a deleted user")
+	}
+	if _, err := provider.Login(ctx, "leaving@example.com", "hunter22"); err == nil {
+		t.Error("Login to a deleted account succeeded")
+	}
+	if files, _ := filepath.Glob(filepath.Join(uploads, "avatars", leaving.User.ID.String(), "*")); len(files) != 0 {
+		t.Errorf("deleted account left avatar files %v", files)
+	}
+	if _, err := repo.User.GetDeletion(ctx, waiting.User.ID); err != nil {
+		t.Errorf("GetDeletion of an account in its grace period: %v", err)
+	}
+	if _, err := provider.Login(ctx, "waiting@example.com", "hunter22"); err != nil {
+		t.Errorf("Login to an account in its grace period: %v", err)
+	}
+}
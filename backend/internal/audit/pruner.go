@@ -0,0 +1,76 @@
+// Package audit keeps the audit log within its retention period.
+package audit
+
+import (
+	"context"
+	"hackmit/internal/storage"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the pruner deletes entries that outlived retention.
+const pruneInterval = time.Hour
+
+// Pruner deletes audit entries older than the retention period. With no retention period,
+// entries are kept forever.
+type Pruner struct {
+	entries   storage.AuditRepository
+	retention time.Duration
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func NewPruner(entries storage.AuditRepository, retention time.Duration) *Pruner {
+	return &Pruner{
+		entries:   entries,
+		retention: retention,
+	}
+}
+
+// Prune deletes the entries that outlived retention, returning how many were.
+func (p *Pruner) Prune(ctx context.Context) (int64, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+	return p.entries.DeleteAuditBefore(ctx, time.Now().Add(-p.retention))
+}
+
+// Start prunes in the background until Close.
+func (p *Pruner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.done.Add(1)
+	go p.prune(ctx)
+}
+
+// Close stops pruning and waits for a prune in progress to finish.
+func (p *Pruner) Close() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.done.Wait()
+}
+
+func (p *Pruner) prune(ctx context.Context) {
+	defer p.done.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := p.Prune(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to prune the audit log", "error", err)
+		} else if deleted > 0 {
+			slog.Info("pruned the audit log", "entries", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
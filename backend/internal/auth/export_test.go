@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetReuseGrace overrides reuseGrace for a test, returning a func that restores it.
+func SetReuseGrace(grace time.Duration) func() {
+	previous := reuseGrace
+	reuseGrace = grace
+	return func() { reuseGrace = previous }
+}
+
+// IssueRecoveryToken issues the recovery token RecoverPassword would log for the user.
+func IssueRecoveryToken(ctx context.Context, p *LocalProvider, userID uuid.UUID) (string, error) {
+	return p.issueRecovery(ctx, userID)
+}
@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hackmit/internal/config"
+	"hackmit/internal/errs"
+	"hackmit/internal/storage"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// audienceAccess marks access tokens, with the audience Supabase gives them.
+	audienceAccess = "authenticated"
+	// audienceRecovery marks the tokens password recovery hands out, which only reset passwords.
+	audienceRecovery = "recovery"
+
+	recoveryTokenTTL  = time.Hour
+	minPasswordLength = 6
+)
+
+// LocalProvider is an IdentityProvider that keeps accounts itself, so the app runs without
+// Supabase. Passwords are stored as bcrypt hashes and tokens are HS256 JWTs. It sends no email:
+// development servers log password recovery tokens instead.
+type LocalProvider struct {
+	identities storage.IdentityRepository
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	// development servers log recovery tokens, which anywhere else would hand accounts to
+	// whoever reads the logs.
+	development bool
+}
+
+// NewLocalProvider returns a local provider keeping its accounts in identities. Without a
+// configured secret, development servers sign with a random key that dies with the process.
+func NewLocalProvider(cfg config.Auth, app config.Application, identities storage.IdentityRepository) (*LocalProvider, error) {
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		if app.Environment != "development" {
+			return nil, errors.New("AUTH_JWT_SECRET is required by the local identity provider")
+		}
+		secret = make([]byte, 32)
+		rand.Read(secret)
+		slog.Warn("AUTH_JWT_SECRET is not set; tokens are signed with a random key and won't survive a restart")
+	}
+
+	return &LocalProvider{
+		identities,
+		secret,
+		cfg.AccessTokenTTL,
+		cfg.RefreshTokenTTL,
+		app.Environment == "development",
+	}, nil
+}
+
+func (p *LocalProvider) SignUp(ctx context.Context, email string, password string) (*Session, error) {
+	email = normalizeEmail(email)
+	if !strings.Contains(email, "@") {
+		return nil, errs.BadRequest("a valid email is required")
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := p.identities.CreateIdentity(ctx, email, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.startSession(ctx, identity.UserID)
+}
+
+func (p *LocalProvider) Login(ctx context.Context, email string, password string) (*Session, error) {
+	identity, err := p.identities.GetIdentityByEmail(ctx, normalizeEmail(email))
+	if errs.IsNotFound(err) {
+		return nil, errs.BadRequest("failed to login: invalid login credentials")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(identity.PasswordHash), []byte(password)); err != nil {
+		return nil, errs.BadRequest("failed to login: invalid login credentials")
+	}
+
+	return p.startSession(ctx, identity.UserID)
+}
+
+// Refresh rotates the session's refresh token, so each one can be used only once.
+func (p *LocalProvider) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	newRefreshToken := randomToken()
+	session, err := p.identities.RotateSession(ctx, hashToken(refreshToken), hashToken(newRefreshToken), time.Now().Add(p.refreshTTL))
+	if errs.IsNotFound(err) {
+		return nil, errs.Unauthorized("invalid or expired refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p.issue(session.ID, session.UserID, newRefreshToken)
+}
+
+func (p *LocalProvider) Logout(ctx context.Context, accessToken string) error {
+	claims, err := p.parse(accessToken)
+	if err != nil || !slices.Contains(claims.Audience, audienceAccess) {
+		return ErrInvalidToken
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	err = p.identities.RevokeSession(ctx, sessionID)
+	if errs.IsNotFound(err) {
+		return ErrInvalidToken
+	}
+	return err
+}
+
+// Verify checks the token's signature and expiry, and that its session hasn't ended.
+func (p *LocalProvider) Verify(ctx context.Context, accessToken string) (*Token, error) {
+	claims, err := p.parse(accessToken)
+	if err != nil || !slices.Contains(claims.Audience, audienceAccess) {
+		return nil, ErrInvalidToken
+	}
+	return p.verifySession(ctx, claims)
+}
+
+// RecoverPassword issues a recovery token for the account, if there is one, and logs it in
+// place of an email on development servers.
+func (p *LocalProvider) RecoverPassword(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	identity, err := p.identities.GetIdentityByEmail(ctx, email)
+	if errs.IsNotFound(err) {
+		// Don't reveal which emails have accounts
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := p.issueRecovery(ctx, identity.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !p.development {
+		slog.Warn("password recovery requested; the local identity provider sends no email", "email", email)
+		return nil
+	}
+	slog.Info("password recovery requested; the local identity provider sends no email", "email", email, "token", token)
+	return nil
+}
+
+// issueRecovery signs a recovery token for the user. Only the latest one issued works, and
+// only once: its nonce is kept until the password is reset with it.
+func (p *LocalProvider) issueRecovery(ctx context.Context, userID uuid.UUID) (string, error) {
+	nonce := randomToken()
+	if err := p.identities.SetRecoveryTokenHash(ctx, userID, hashToken(nonce)); err != nil {
+		return "", err
+	}
+
+	return p.sign(sessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        nonce,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{audienceRecovery},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(recoveryTokenTTL)),
+		},
+	})
+}
+
+// UpdatePassword accepts a recovery token, which is then used up, or the access token of a live
+// session. Every other session of the account is ended, so that whoever knew the old password
+// is signed out.
+func (p *LocalProvider) UpdatePassword(ctx context.Context, token string, newPassword string) error {
+	claims, err := p.parse(token)
+	if err != nil {
+		return errs.Unauthorized(ErrInvalidToken.Error())
+	}
+
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	var userID, current uuid.UUID
+	switch {
+	case slices.Contains(claims.Audience, audienceRecovery):
+		if userID, err = uuid.Parse(claims.Subject); err != nil {
+			err = ErrInvalidToken
+		} else if err = p.identities.ResetPassword(ctx, userID, hashToken(claims.ID), hash); errs.IsNotFound(err) {
+			err = ErrInvalidToken
+		}
+	case slices.Contains(claims.Audience, audienceAccess):
+		var token *Token
+		if token, err = p.verifySession(ctx, claims); err == nil {
+			userID, current = token.UserID, token.SessionID
+			err = p.identities.UpdatePasswordHash(ctx, userID, hash)
+		}
+	default:
+		err = ErrInvalidToken
+	}
+	if errors.Is(err, ErrInvalidToken) {
+		return errs.Unauthorized(ErrInvalidToken.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = p.identities.RevokeSessions(ctx, userID, current)
+	return err
+}
+
+func (p *LocalProvider) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	return p.identities.DeleteIdentity(ctx, userID)
+}
+
+func (p *LocalProvider) startSession(ctx context.Context, userID uuid.UUID) (*Session, error) {
+	refreshToken := randomToken()
+	session, err := p.identities.CreateSession(ctx, userID, hashToken(refreshToken), time.Now().Add(p.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+	return p.issue(session.ID, userID, refreshToken)
+}
+
+// issue signs an access token for the session and pairs it with its refresh token.
+func (p *LocalProvider) issue(sessionID uuid.UUID, userID uuid.UUID, refreshToken string) (*Session, error) {
+	issued := time.Now()
+	accessToken, err := p.sign(sessionClaims{
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{audienceAccess},
+			IssuedAt:  jwt.NewNumericDate(issued),
+			ExpiresAt: jwt.NewNumericDate(issued.Add(p.accessTTL)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		AccessToken:  accessToken,
+		TokenType:    "bearer",
+		ExpiresIn:    int(p.accessTTL.Seconds()),
+		RefreshToken: refreshToken,
+		User:         SessionUser{ID: userID},
+		SessionID:    sessionID,
+	}, nil
+}
+
+func (p *LocalProvider) verifySession(ctx context.Context, claims *sessionClaims) (*Token, error) {
+	token, err := claims.token()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := p.identities.GetSession(ctx, token.SessionID)
+	if errs.IsNotFound(err) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != token.UserID || !session.Live(time.Now()) {
+		return nil, ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+func (p *LocalProvider) sign(claims sessionClaims) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(p.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
+// parse checks a token's signature and expiry. Callers check its audience.
+func (p *LocalProvider) parse(token string) (*sessionClaims, error) {
+	var claims sessionClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return p.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", errs.BadRequest(fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", errs.BadRequest("password is too long")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// randomToken returns an opaque refresh token.
+func randomToken() string {
+	token := make([]byte, 32)
+	rand.Read(token)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,133 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"hackmit/internal/auth"
+	"hackmit/internal/config"
+	"hackmit/internal/storage/memory"
+	"testing"
+	"time"
+)
+
+func TestLocalProvider(t *testing.T) {
+	ctx := context.Background()
+	provider, err := auth.NewLocalProvider(
+		config.Auth{JWTSecret: "test-secret", AccessTokenTTL: time.Hour, RefreshTokenTTL: time.Hour},
+		config.Application{Environment: "test"},
+		memory.NewRepository(nil).Identity,
+	)
+	if err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+
+	signedUp, err := provider.SignUp(ctx, " Diver@Example.com", "hunter22")
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if _, err := provider.SignUp(ctx, "diver@example.com", "hunter22"); err == nil {
+		t.Error("SignUp with a taken email succeeded")
+	}
+	if _, err := provider.SignUp(ctx, "short@example.com", "abc"); err == nil {
+		t.Error("SignUp with a short password succeeded")
+	}
+
+	if _, err := provider.Login(ctx, "diver@example.com", "wrong-password"); err == nil {
+		t.Error("Login with the wrong password succeeded")
+	}
+	session, err := provider.Login(ctx, "DIVER@example.com", "hunter22")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.User.ID != signedUp.User.ID {
+		t.Errorf("Login user = %s, want %s", session.User.ID, signedUp.User.ID)
+	}
+
+	token, err := provider.Verify(ctx, session.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if token.UserID != signedUp.User.ID || token.SessionID != session.SessionID {
+		t.Errorf("Verify = %+v, want user %s in session %s", token, signedUp.User.ID, session.SessionID)
+	}
+	if _, err := provider.Verify(ctx, session.AccessToken+"x"); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Verify of a tampered token = %v, want ErrInvalidToken", err)
+	}
+
+	// Refresh tokens are single use
+	refreshed, err := provider.Refresh(ctx, session.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := provider.Refresh(ctx, session.RefreshToken); err == nil {
+		t.Error("Refresh with a used refresh token succeeded")
+	}
+
+	// Changing the password signs out every other device
+	elsewhere, err := provider.Login(ctx, "diver@example.com", "hunter22")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := provider.UpdatePassword(ctx, refreshed.AccessToken, "new-password"); err != nil {
+		t.Fatalf("UpdatePassword: %v", err)
+	}
+	if _, err := provider.Login(ctx, "diver@example.com", "hunter22"); err == nil {
+		t.Error("Login with the old password succeeded")
+	}
+	if _, err := provider.Verify(ctx, elsewhere.AccessToken); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Verify of another session after a password change = %v, want ErrInvalidToken", err)
+	}
+	if _, err := provider.Verify(ctx, refreshed.AccessToken); err != nil {
+		t.Errorf("Verify of the session that changed the password: %v", err)
+	}
+
+	// Logging out ends the session for every token issued to it
+	if err := provider.Logout(ctx, refreshed.AccessToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := provider.Verify(ctx, refreshed.AccessToken); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Verify after logout = %v, want ErrInvalidToken", err)
+	}
+	if _, err := provider.Verify(ctx, session.AccessToken); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Verify of an earlier token after logout = %v, want ErrInvalidToken", err)
+	}
+	if _, err := provider.Refresh(ctx, refreshed.RefreshToken); err == nil {
+		t.Error("Refresh after logout succeeded")
+	}
+
+	// Only the latest recovery token works, and only once
+	replaced, err := auth.IssueRecoveryToken(ctx, provider, signedUp.User.ID)
+	if err != nil {
+		t.Fatalf("IssueRecoveryToken: %v", err)
+	}
+	recovery, err := auth.IssueRecoveryToken(ctx, provider, signedUp.User.ID)
+	if err != nil {
+		t.Fatalf("IssueRecoveryToken: %v", err)
+	}
+	if err := provider.UpdatePassword(ctx, replaced, "replaced-password"); err == nil {
+		t.Error("UpdatePassword with a replaced recovery token succeeded")
+	}
+	relogged, err := provider.Login(ctx, "diver@example.com", "new-password")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := provider.UpdatePassword(ctx, recovery, "recovered-password"); err != nil {
+		t.Fatalf("UpdatePassword with a recovery token: %v", err)
+	}
+	if err := provider.UpdatePassword(ctx, recovery, "stolen-password"); err == nil {
+		t.Error("UpdatePassword with a used recovery token succeeded")
+	}
+	if _, err := provider.Verify(ctx, relogged.AccessToken); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Verify after a password reset = %v, want ErrInvalidToken", err)
+	}
+	if _, err := provider.Login(ctx, "diver@example.com", "recovered-password"); err != nil {
+		t.Errorf("Login with the recovered password: %v", err)
+	}
+
+	if err := provider.DeleteUser(ctx, signedUp.User.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := provider.Login(ctx, "diver@example.com", "recovered-password"); err == nil {
+		t.Error("Login after DeleteUser succeeded")
+	}
+}
@@ -1,44 +1,182 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
-	"hackmit/internal/config"
-	"net/http"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
-// Middleware validates the JWT using Supabase's auth API
-func Middleware(cfg *config.Supabase) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		token := c.Cookies("jwt", "")
+// userIDKey is the fiber.Ctx locals key the middleware stores the authenticated user's ID under.
+const userIDKey = "userID"
+
+// sessionIDKey is the fiber.Ctx locals key the middleware stores the authenticated session's ID under.
+const sessionIDKey = "sessionID"
+
+// roleKey is the fiber.Ctx locals key the middleware stores the authenticated user's role under.
+const roleKey = "role"
+
+// errNoToken means the request carries neither an access nor a refresh token.
+var errNoToken = errors.New("no token")
 
-		if token == "" {
+// Middleware validates the JWT with the identity provider, refreshing sessions whose access
+// token has expired or is about to and turning away those that have been revoked. The user's
+// role is looked up in users.
+func Middleware(provider IdentityProvider, sessions storage.SessionRepository, users storage.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, err := authenticate(c, provider, sessions)
+		if errors.Is(err, errNoToken) {
 			fmt.Println("JWT not found in middleware")
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token not found"})
 		}
-
-		// Validate token with Supabase
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/auth/v1/user", cfg.URL), nil)
+		if errors.Is(err, ErrInvalidToken) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired token"})
+		}
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create request"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		// Set headers
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("apikey", cfg.AnonKey)
+		// Remember who the token belongs to so handlers don't have to trust client-supplied IDs
+		if err := identify(c, users, token); err != nil {
+			return err
+		}
 
-		resp, err := Client.Do(req)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to validate token"})
+		// If validation is successful, proceed to the next middleware
+		return c.Next()
+	}
+}
+
+// OptionalMiddleware identifies the user when a valid JWT is present but lets anonymous requests through.
+func OptionalMiddleware(provider IdentityProvider, sessions storage.SessionRepository, users storage.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token, err := authenticate(c, provider, sessions); err == nil {
+			if err := identify(c, users, token); err != nil {
+				return err
+			}
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired token"})
+		return c.Next()
+	}
+}
+
+// authenticate returns what the request's access token says about its bearer. When the token
+// has expired or is about to, the session is refreshed with the refresh token cookie and the new
+// tokens are handed back with the response.
+func authenticate(c *fiber.Ctx, provider IdentityProvider, sessions storage.SessionRepository) (*Token, error) {
+	accessToken := c.Cookies(AccessTokenCookie)
+	refreshToken := c.Cookies(RefreshTokenCookie)
+	if accessToken == "" && refreshToken == "" {
+		return nil, errNoToken
+	}
+
+	var token *Token
+	err := ErrInvalidToken
+	if accessToken != "" {
+		token, err = provider.Verify(c.Context(), accessToken)
+	}
+	if err == nil {
+		err = checkSession(c, sessions, token)
+	}
+	if err != nil && !errors.Is(err, ErrInvalidToken) {
+		return nil, err
+	}
+
+	if refreshToken != "" && (err != nil || time.Until(token.ExpiresAt) < refreshWithin) {
+		session, record, refreshErr := RefreshSession(c.Context(), provider, sessions, refreshToken)
+		if refreshErr == nil {
+			SetSessionCookies(c, session, CookieExpiry(record.RememberMe))
+			return &Token{session.User.ID, record.ID, time.Now().Add(time.Duration(session.ExpiresIn) * time.Second)}, nil
 		}
+		// A token that is still good outlives a failed refresh
+	}
 
-		// If validation is successful, proceed to the next middleware
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// identify stores who the token belongs to, and their role, in the request's locals. Users
+// without a profile yet, in the middle of signing up, have the least privileged role.
+func identify(c *fiber.Ctx, users storage.UserRepository, token *Token) error {
+	role, err := users.GetUserRole(c.Context(), token.UserID)
+	if errs.IsNotFound(err) {
+		role = models.RoleUser
+	} else if err != nil {
+		return err
+	}
+
+	c.Locals(userIDKey, token.UserID)
+	c.Locals(sessionIDKey, token.SessionID)
+	c.Locals(roleKey, role)
+	return nil
+}
+
+// RequireRole turns away users whose role doesn't include minimum. It goes after Middleware.
+func RequireRole(minimum models.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, err := Role(c)
+		if err != nil {
+			return err
+		}
+		if !role.Includes(minimum) {
+			return errs.Forbidden(fmt.Sprintf("Requires the %s role", minimum))
+		}
 		return c.Next()
 	}
 }
+
+// checkSession turns away tokens of revoked sessions and notes that the session is in use.
+// Tokens of sessions that were never recorded, or whose record is gone, are turned away too,
+// since there is no telling whether they were revoked.
+func checkSession(c *fiber.Ctx, sessions storage.SessionRepository, token *Token) error {
+	record, err := sessions.GetSession(c.Context(), token.SessionID)
+	if errs.IsNotFound(err) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if record.RevokedAt != nil || record.UserID != token.UserID {
+		return ErrInvalidToken
+	}
+
+	if time.Since(record.LastSeenAt) > touchEvery {
+		if err := sessions.TouchSession(c.Context(), record.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionID returns the ID of the session authenticated by Middleware.
+func SessionID(c *fiber.Ctx) (uuid.UUID, error) {
+	sessionID, ok := c.Locals(sessionIDKey).(uuid.UUID)
+	if !ok || sessionID == uuid.Nil {
+		return uuid.Nil, errs.Unauthorized("Not authenticated")
+	}
+	return sessionID, nil
+}
+
+// UserID returns the ID of the user authenticated by Middleware.
+func UserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userID, ok := c.Locals(userIDKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, errs.Unauthorized("Not authenticated")
+	}
+	return userID, nil
+}
+
+// Role returns the role of the user authenticated by Middleware.
+func Role(c *fiber.Ctx) (models.Role, error) {
+	role, ok := c.Locals(roleKey).(models.Role)
+	if !ok || role == "" {
+		return "", errs.Unauthorized("Not authenticated")
+	}
+	return role, nil
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	AccessTokenCookie  = "jwt"
+	RefreshTokenCookie = "refresh_token"
+
+	// rememberFor is how long the cookies of a remembered session last.
+	rememberFor = 7 * 24 * time.Hour
+	// refreshWithin is how close to expiry an access token has to be for the middleware to
+	// refresh it.
+	refreshWithin = 5 * time.Minute
+	// touchEvery is how stale a session's last-seen time gets before the middleware updates it.
+	touchEvery = time.Minute
+)
+
+// reuseGrace is how long after a refresh token is exchanged presenting it again is taken for
+// concurrent requests racing to refresh rather than for theft.
+var reuseGrace = 10 * time.Second
+
+// Device is where a session was signed in from. Empty fields are unknown.
+type Device struct {
+	UserAgent string
+	IPAddress string
+}
+
+// DeviceOf describes the device a request came from. The values are copied, since fiber reuses
+// its request buffers once the handler returns.
+func DeviceOf(c *fiber.Ctx) Device {
+	return Device{strings.Clone(c.Get(fiber.HeaderUserAgent)), strings.Clone(c.IP())}
+}
+
+// RecordSession starts tracking a session the identity provider has just handed out, so that it
+// can be refreshed, listed and revoked.
+func RecordSession(ctx context.Context, sessions storage.SessionRepository, session *Session, rememberMe bool, device Device) error {
+	// Signing up with email confirmation on hands out no session
+	if session.RefreshToken == "" {
+		return nil
+	}
+	_, err := sessions.CreateSession(ctx, models.CreateSessionRequest{
+		ID:               session.SessionID,
+		UserID:           session.User.ID,
+		RememberMe:       rememberMe,
+		RefreshTokenHash: hashToken(session.RefreshToken),
+		UserAgent:        nonEmpty(device.UserAgent),
+		IPAddress:        nonEmpty(device.IPAddress),
+	})
+	return err
+}
+
+// RefreshSession exchanges a refresh token for a new session, along with the record of the
+// sign-in it continues. Each refresh token is good for one exchange: presenting one again means
+// it was stolen, and the whole session is revoked.
+func RefreshSession(ctx context.Context, provider IdentityProvider, sessions storage.SessionRepository, refreshToken string) (*Session, *models.Session, error) {
+	tokenHash := hashToken(refreshToken)
+
+	token, err := sessions.GetRefreshToken(ctx, tokenHash)
+	if errs.IsNotFound(err) {
+		return nil, nil, errs.Unauthorized("invalid refresh token")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if token.UsedAt != nil {
+		if time.Since(*token.UsedAt) < reuseGrace {
+			return nil, nil, errs.Unauthorized("refresh token has just been used")
+		}
+		if err := sessions.RevokeSession(ctx, token.SessionID); err != nil {
+			return nil, nil, err
+		}
+		slog.Warn("refresh token reused; session revoked", "session_id", token.SessionID)
+		return nil, nil, errs.Unauthorized("refresh token has already been used")
+	}
+
+	record, err := sessions.GetSession(ctx, token.SessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.RevokedAt != nil {
+		return nil, nil, errs.Unauthorized("session has been revoked")
+	}
+
+	session, err := provider.Refresh(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, err = sessions.RotateRefreshToken(ctx, tokenHash, hashToken(session.RefreshToken))
+	var httpErr errs.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == http.StatusConflict {
+		// Another request exchanged the token or the session was revoked in the meantime
+		return nil, nil, errs.Unauthorized("refresh token has already been used")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, record, nil
+}
+
+// CookieExpiry is when the cookies of a session expire; the zero time makes them last as long
+// as the browser session.
+func CookieExpiry(rememberMe bool) time.Time {
+	if rememberMe {
+		return time.Now().Add(rememberFor)
+	}
+	return time.Time{}
+}
+
+// SetSessionCookies hands the session's tokens to the browser. The refresh token is kept out of
+// reach of scripts.
+func SetSessionCookies(c *fiber.Ctx, session *Session, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     AccessTokenCookie,
+		Value:    session.AccessToken,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   true,
+		SameSite: "Lax",
+	})
+
+	c.Cookie(&fiber.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    session.RefreshToken,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "Lax",
+	})
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ClearSessionCookies takes the session's tokens back from the browser.
+func ClearSessionCookies(c *fiber.Ctx) {
+	c.ClearCookie(AccessTokenCookie, RefreshTokenCookie)
+}
+
+// hashToken is what is stored of a refresh token, so a leaked table can't be used to sign in.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
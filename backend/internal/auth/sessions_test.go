@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"hackmit/internal/auth"
+	"hackmit/internal/config"
+	"hackmit/internal/errs"
+	"hackmit/internal/storage/memory"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRefreshSession(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository(nil)
+	provider, err := auth.NewLocalProvider(
+		config.Auth{JWTSecret: "test-secret", AccessTokenTTL: time.Hour, RefreshTokenTTL: time.Hour},
+		config.Application{Environment: "test"},
+		repo.Identity,
+	)
+	if err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+
+	session, err := provider.SignUp(ctx, "diver@example.com", "hunter22")
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if _, err := repo.User.AddUser(ctx, session.User.ID.String(), nil, nil, "diver@example.com"); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if err := auth.RecordSession(ctx, repo.Session, session, true, auth.Device{UserAgent: "test"}); err != nil {
+		t.Fatalf("RecordSession: %v", err)
+	}
+
+	refreshed, record, err := auth.RefreshSession(ctx, provider, repo.Session, session.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession: %v", err)
+	}
+	if record.ID != session.SessionID || !record.RememberMe || refreshed.RefreshToken == session.RefreshToken {
+		t.Errorf("RefreshSession = %+v, %+v; want a rotated token for session %s", refreshed, record, session.SessionID)
+	}
+
+	// Racing the first exchange is turned away without ending the session
+	_, _, err = auth.RefreshSession(ctx, provider, repo.Session, session.RefreshToken)
+	wantUnauthorized(t, err)
+	if _, _, err := auth.RefreshSession(ctx, provider, repo.Session, "not-a-refresh-token"); err == nil {
+		t.Error("RefreshSession with an unknown token succeeded")
+	}
+	current, err := repo.Session.GetSession(ctx, session.SessionID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if current.RevokedAt != nil {
+		t.Fatal("session revoked by a refresh within the grace period")
+	}
+
+	// Presenting a used token later means it leaked: the whole session ends
+	defer auth.SetReuseGrace(0)()
+	_, _, err = auth.RefreshSession(ctx, provider, repo.Session, session.RefreshToken)
+	wantUnauthorized(t, err)
+	_, _, err = auth.RefreshSession(ctx, provider, repo.Session, refreshed.RefreshToken)
+	wantUnauthorized(t, err)
+	current, err = repo.Session.GetSession(ctx, session.SessionID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if current.RevokedAt == nil {
+		t.Error("session not revoked after its refresh token was reused")
+	}
+}
+
+func wantUnauthorized(t *testing.T, err error) {
+	t.Helper()
+	var httpErr errs.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnauthorized {
+		t.Fatalf("got error %v, want HTTP 401", err)
+	}
+}
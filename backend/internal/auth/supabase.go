@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hackmit/internal/config"
+	"hackmit/internal/errs"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupabaseProvider is an IdentityProvider backed by the Supabase Auth REST API.
+type SupabaseProvider struct {
+	config config.Supabase
+	client *http.Client
+}
+
+func NewSupabaseProvider(cfg config.Supabase) *SupabaseProvider {
+	return &SupabaseProvider{
+		cfg,
+		&http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type credentialsPayload struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// apiError is a response from Supabase with an unexpected status.
+type apiError struct {
+	status int
+	body   []byte
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d, %s", e.status, e.body)
+}
+
+// request sends a request to the auth API at path with the given apikey and bearer token,
+// encoding payload as JSON unless it is nil and decoding a successful response into out
+// unless it is nil.
+func (p *SupabaseProvider) request(ctx context.Context, method string, path string, apiKey string, bearer string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.config.URL+"/auth/v1"+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Supabase wants the key in a lowercase apikey header as well as the bearer token
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", apiKey)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Some endpoints answer 204 No Content on success
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return &apiError{resp.StatusCode, respBody}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response body: %w", err)
+		}
+	}
+	return nil
+}
+
+// withSessionID fills in the session's ID from its access token. Signing up while email
+// confirmation is on hands out no token, and so no ID.
+func withSessionID(session *Session) (*Session, error) {
+	if session.AccessToken == "" {
+		return session, nil
+	}
+	token, err := readToken(session.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access token: %w", err)
+	}
+	session.SessionID = token.SessionID
+	return session, nil
+}
+
+func (p *SupabaseProvider) SignUp(ctx context.Context, email string, password string) (*Session, error) {
+	var session Session
+	err := p.request(ctx, http.MethodPost, "/signup", p.config.AnonKey, p.config.AnonKey, credentialsPayload{email, password}, &session)
+	if err != nil {
+		return nil, errs.BadRequest(fmt.Sprintf("failed to sign up: %v", err))
+	}
+	return withSessionID(&session)
+}
+
+func (p *SupabaseProvider) Login(ctx context.Context, email string, password string) (*Session, error) {
+	var session Session
+	err := p.request(ctx, http.MethodPost, "/token?grant_type=password", p.config.AnonKey, p.config.AnonKey, credentialsPayload{email, password}, &session)
+	if err != nil {
+		return nil, errs.BadRequest(fmt.Sprintf("failed to login %v", err))
+	}
+	return withSessionID(&session)
+}
+
+func (p *SupabaseProvider) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	payload := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{refreshToken}
+
+	var session Session
+	err := p.request(ctx, http.MethodPost, "/token?grant_type=refresh_token", p.config.AnonKey, p.config.AnonKey, payload, &session)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return nil, errs.Unauthorized(fmt.Sprintf("failed to refresh session %v", err))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withSessionID(&session)
+}
+
+func (p *SupabaseProvider) Logout(ctx context.Context, accessToken string) error {
+	if err := p.request(ctx, http.MethodPost, "/logout", p.config.AnonKey, accessToken, nil, nil); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// Verify asks Supabase whether the token is still good, then reads it.
+func (p *SupabaseProvider) Verify(ctx context.Context, accessToken string) (*Token, error) {
+	var user SessionUser
+	err := p.request(ctx, http.MethodGet, "/user", p.config.AnonKey, accessToken, nil, &user)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	token, err := readToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if token.UserID != user.ID {
+		return nil, ErrInvalidToken
+	}
+	return token, nil
+}
+
+func (p *SupabaseProvider) RecoverPassword(ctx context.Context, email string) error {
+	payload := struct {
+		Email string `json:"email"`
+	}{email}
+
+	if err := p.request(ctx, http.MethodPost, "/recover", p.config.AnonKey, p.config.AnonKey, payload, nil); err != nil {
+		return errs.BadRequest(fmt.Sprintf("failed to initiate password reset %v", err))
+	}
+	return nil
+}
+
+// UpdatePassword sets the password of the user the token from a recovery link belongs to.
+func (p *SupabaseProvider) UpdatePassword(ctx context.Context, token string, newPassword string) error {
+	payload := struct {
+		Password string `json:"password"`
+	}{newPassword}
+
+	if err := p.request(ctx, http.MethodPut, "/user", p.config.AnonKey, token, payload, nil); err != nil {
+		return errs.BadRequest(fmt.Sprintf("failed to update password %v", err))
+	}
+	return nil
+}
+
+// DeleteUser removes the account through the admin API, which needs the service role key.
+func (p *SupabaseProvider) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	key := p.config.ServiceRoleKey
+	err := p.request(ctx, http.MethodDelete, "/admin/users/"+userID.String(), key, key, nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		return errs.NotFound("user", "id", userID.String())
+	}
+	if err != nil {
+		return errs.BadRequest(fmt.Sprintf("Failed to delete account: %v", err))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package avatar
+
+import (
+	"image"
+	"image/color"
+)
+
+// crop returns the largest centered square of img.
+func crop(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	square := image.Rect(x, y, x+side, y+side)
+
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(square)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	for dy := range side {
+		for dx := range side {
+			dst.Set(dx, dy, img.At(x+dx, y+dy))
+		}
+	}
+	return dst
+}
+
+// resize scales a square image to size×size, averaging the source pixels that fall into each
+// destination pixel. Transparent areas are flattened onto white, since avatars are stored as
+// JPEG.
+func resize(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := range size {
+		y0 := b.Min.Y + y*b.Dy()/size
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/size, y0+1)
+		for x := range size {
+			x0 := b.Min.X + x*b.Dx()/size
+			x1 := max(b.Min.X+(x+1)*b.Dx()/size, x0+1)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					// RGBA is premultiplied, so adding the missing alpha composites onto white
+					white := uint64(0xffff - ca)
+					r += uint64(cr) + white
+					g += uint64(cg) + white
+					bl += uint64(cb) + white
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
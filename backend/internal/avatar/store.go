@@ -0,0 +1,145 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// URLPrefix is where the upload directory is served.
+const URLPrefix = "/uploads/"
+
+const (
+	// maxDimension bounds the width and height of an upload, so that a small file can't decode
+	// into an enormous image.
+	maxDimension = 4096
+	fullSize     = 256
+	thumbSize    = 64
+)
+
+var acceptedTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// Store keeps avatars on local disk. Each upload is saved as square JPEGs of two sizes under a
+// key that's new for every upload, so a replaced avatar is never served from a stale cache.
+type Store struct {
+	dir     string
+	maxSize int64
+}
+
+func NewStore(dir string, maxSize int64) *Store {
+	return &Store{dir, maxSize}
+}
+
+// Save validates and resizes an uploaded image, returning the key it was stored under.
+func (s *Store) Save(userID uuid.UUID, r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading avatar: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return "", errs.NewHTTPError(http.StatusRequestEntityTooLarge,
+			fmt.Errorf("Avatars can be at most %d KB", s.maxSize/1024))
+	}
+
+	contentType := http.DetectContentType(data)
+	if !accepted(contentType) {
+		return "", errs.UnprocessableEntity("Avatars must be JPEG, PNG or GIF images")
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", errs.UnprocessableEntity("The avatar could not be read as an image")
+	}
+	if cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return "", errs.UnprocessableEntity(fmt.Sprintf("Avatars can be at most %dx%d pixels", maxDimension, maxDimension))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", errs.UnprocessableEntity("The avatar could not be read as an image")
+	}
+
+	full := resize(crop(img), fullSize)
+	thumb := resize(full, thumbSize)
+
+	key := path.Join("avatars", userID.String(), randomName())
+	if err := os.MkdirAll(filepath.Dir(s.file(key, fullSize)), 0o755); err != nil {
+		return "", fmt.Errorf("error storing avatar: %w", err)
+	}
+	if err := errors.Join(write(s.file(key, fullSize), full), write(s.file(key, thumbSize), thumb)); err != nil {
+		s.Delete(key)
+		return "", fmt.Errorf("error storing avatar: %w", err)
+	}
+
+	return key, nil
+}
+
+// Delete removes the files stored under key. Files that are already gone are not an error.
+func (s *Store) Delete(key string) error {
+	var err error
+	for _, size := range []int{fullSize, thumbSize} {
+		if e := os.Remove(s.file(key, size)); e != nil && !errors.Is(e, os.ErrNotExist) {
+			err = errors.Join(err, e)
+		}
+	}
+	return err
+}
+
+// URLs returns where the avatar stored under key is served.
+func URLs(key string) *models.Avatar {
+	return &models.Avatar{
+		URL:          URLPrefix + name(key, fullSize),
+		ThumbnailURL: URLPrefix + name(key, thumbSize),
+	}
+}
+
+func (s *Store) file(key string, size int) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name(key, size)))
+}
+
+func name(key string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", key, size)
+}
+
+func write(file string, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(f, img, &jpeg.Options{Quality: 85}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func accepted(contentType string) bool {
+	for _, t := range acceptedTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func randomName() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,95 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"hackmit/internal/errs"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 1<<20)
+	userID := uuid.New()
+
+	// A transparent, landscape PNG becomes a white square of each size
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	src.Set(150, 100, color.NRGBA{R: 255, A: 255})
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, src); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := store.Save(userID, &upload)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	for _, size := range []int{fullSize, thumbSize} {
+		f, err := os.Open(store.file(key, size))
+		if err != nil {
+			t.Fatalf("opening the %d avatar: %v", size, err)
+		}
+		img, err := jpeg.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("decoding the %d avatar: %v", size, err)
+		}
+		if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+			t.Errorf("avatar is %dx%d, want %dx%d", b.Dx(), b.Dy(), size, size)
+		}
+		if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 < 0xf0 || g>>8 < 0xf0 || b>>8 < 0xf0 {
+			t.Errorf("transparent corner of the %d avatar = %d,%d,%d, want white", size, r>>8, g>>8, b>>8)
+		}
+	}
+
+	urls := URLs(key)
+	if want := URLPrefix + "avatars/" + userID.String() + "/"; len(urls.URL) <= len(want) || urls.URL[:len(want)] != want {
+		t.Errorf("URL = %q, want it under %q", urls.URL, want)
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(store.file(key, thumbSize)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("thumbnail survived Delete: %v", err)
+	}
+	if err := store.Delete(key); err != nil {
+		t.Errorf("Delete of a deleted avatar: %v", err)
+	}
+
+	_, err = store.Save(userID, bytes.NewReader([]byte("not an image at all")))
+	wantStatus(t, err, http.StatusUnprocessableEntity)
+
+	_, err = store.Save(userID, bytes.NewReader(make([]byte, 1<<20+1)))
+	wantStatus(t, err, http.StatusRequestEntityTooLarge)
+
+	// Oversized dimensions are refused before decoding
+	var huge bytes.Buffer
+	if err := png.Encode(&huge, image.NewGray(image.Rect(0, 0, maxDimension+1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Save(userID, &huge)
+	wantStatus(t, err, http.StatusUnprocessableEntity)
+
+	if entries, _ := os.ReadDir(filepath.Join(dir, "avatars", userID.String())); len(entries) != 0 {
+		t.Errorf("%d files left behind after refused uploads", len(entries))
+	}
+}
+
+func wantStatus(t *testing.T, err error, code int) {
+	t.Helper()
+	var httpErr errs.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != code {
+		t.Errorf("err = %v, want status %d", err, code)
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+type Audit struct {
+	Retention time.Duration `env:"AUDIT_RETENTION, default=8760h"` // how long audit entries are kept; 0 keeps them forever.
+}
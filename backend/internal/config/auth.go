@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+const (
+	ProviderSupabase = "supabase"
+	ProviderLocal    = "local"
+)
+
+type Auth struct {
+	Provider        string        `env:"AUTH_PROVIDER, default=supabase"`      // who signs users in: "supabase" or "local".
+	JWTSecret       string        `env:"AUTH_JWT_SECRET"`                      // the HS256 key the local provider signs tokens with.
+	AccessTokenTTL  time.Duration `env:"AUTH_ACCESS_TOKEN_TTL, default=1h"`    // how long a local access token is valid.
+	RefreshTokenTTL time.Duration `env:"AUTH_REFRESH_TOKEN_TTL, default=720h"` // how long a local session lasts without being refreshed.
+	DeletionGrace   time.Duration `env:"AUTH_DELETION_GRACE, default=720h"`    // how long a deleted account can still be restored.
+}
@@ -1,7 +1,56 @@
 package config
 
+import (
+	"context"
+	"errors"
+
+	"github.com/sethvargo/go-envconfig"
+)
+
 type Config struct {
 	Application Application
+	Audit       Audit
 	DB          DB
+	Auth        Auth
+	Export      Export
+	Moderation  Moderation
 	Supabase    Supabase
+	Tagging     Tagging
+	Uploads     Uploads
+}
+
+// Load reads the configuration from the environment. Database settings are only required
+// when withDB is set, and Supabase settings only when Supabase is the identity provider.
+func Load(ctx context.Context, withDB bool) (Config, error) {
+	var config Config
+	err := errors.Join(
+		envconfig.Process(ctx, &config.Application),
+		envconfig.Process(ctx, &config.Audit),
+		envconfig.Process(ctx, &config.Auth),
+		envconfig.Process(ctx, &config.Export),
+		envconfig.Process(ctx, &config.Moderation),
+		envconfig.Process(ctx, &config.Tagging),
+		envconfig.Process(ctx, &config.Uploads),
+	)
+	if err != nil {
+		return config, err
+	}
+
+	if withDB {
+		if err := envconfig.Process(ctx, &config.DB); err != nil {
+			return config, err
+		}
+	}
+
+	switch config.Auth.Provider {
+	case ProviderSupabase:
+		if err := envconfig.Process(ctx, &config.Supabase); err != nil {
+			return config, err
+		}
+	case ProviderLocal:
+	default:
+		return config, errors.New("AUTH_PROVIDER must be supabase or local")
+	}
+
+	return config, nil
 }
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type Export struct {
+	SigningSecret string        `env:"EXPORT_SIGNING_SECRET"`         // the HMAC key download links are signed with.
+	LinkTTL       time.Duration `env:"EXPORT_LINK_TTL, default=15m"`  // how long a download link works.
+	Retention     time.Duration `env:"EXPORT_RETENTION, default=72h"` // how long a finished archive is kept.
+}
@@ -0,0 +1,6 @@
+package config
+
+type Moderation struct {
+	ReportThreshold int `env:"MODERATION_REPORT_THRESHOLD, default=3"` // how many readers' reports hide a bottle until it is reviewed.
+	CheckLimit      int `env:"MODERATION_CHECK_LIMIT, default=30"`     // how many drafts a writer can check a minute.
+}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type Tagging struct {
+	AutoApply    float64       `env:"TAGGING_AUTO_APPLY, default=0.5"`   // how high a suggested tag must score to be applied to a bottle thrown without one; above 1 never applies one.
+	Refresh      time.Duration `env:"TAGGING_REFRESH, default=5m"`       // how often the server looks for a retrained tag model.
+	SuggestLimit int           `env:"TAGGING_SUGGEST_LIMIT, default=30"` // how many drafts a writer can get tags suggested for a minute.
+}
@@ -0,0 +1,6 @@
+package config
+
+type Uploads struct {
+	Dir           string `env:"UPLOAD_DIR, default=uploads"`             // where uploaded files are kept and served from.
+	MaxAvatarSize int64  `env:"UPLOAD_MAX_AVATAR_SIZE, default=2097152"` // the largest avatar accepted, in bytes.
+}
@@ -64,6 +64,12 @@ func NotFound(msg ...string) HTTPError {
 	return NewHTTPError(http.StatusNotFound, fmt.Errorf("%v", msg))
 }
 
+// IsNotFound reports whether err is, or wraps, a not found error.
+func IsNotFound(err error) bool {
+	var httpErr HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound
+}
+
 // Conflict with flexible parameters
 func Conflict(msg ...string) HTTPError {
 	if len(msg) == 0 {
@@ -72,7 +78,7 @@ func Conflict(msg ...string) HTTPError {
 
 	// If only one argument, use it as the message
 	if len(msg) == 1 {
-		return NewHTTPError(http.StatusConflict, fmt.Errorf(msg[0]))
+		return NewHTTPError(http.StatusConflict, errors.New(msg[0]))
 	}
 
 	// If three arguments, format as: "title with key='value' already exists"
@@ -120,6 +126,15 @@ func Forbidden(msg ...string) HTTPError {
 	return NewHTTPError(http.StatusForbidden, errors.New(message))
 }
 
+// TooManyRequests accepts optional custom message
+func TooManyRequests(msg ...string) HTTPError {
+	message := "too many requests"
+	if len(msg) > 0 && msg[0] != "" {
+		message = msg[0]
+	}
+	return NewHTTPError(http.StatusTooManyRequests, errors.New(message))
+}
+
 // UnprocessableEntity for single message (not validation errors)
 func UnprocessableEntity(msg ...string) HTTPError {
 	message := "unprocessable entity"
@@ -129,12 +144,11 @@ func UnprocessableEntity(msg ...string) HTTPError {
 	return NewHTTPError(http.StatusUnprocessableEntity, errors.New(message))
 }
 
-// ErrorHandler remains the same
+// ErrorHandler answers with the HTTP error err is or wraps, and with a bare 500 for anything
+// else, so that internal details never reach the client.
 func ErrorHandler(c *fiber.Ctx, err error) error {
 	var httpErr HTTPError
-	if castedErr, ok := err.(HTTPError); ok {
-		httpErr = castedErr
-	} else {
+	if !errors.As(err, &httpErr) {
 		httpErr = InternalServerError()
 	}
 
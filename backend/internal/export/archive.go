@@ -0,0 +1,187 @@
+// Package export builds archives of everything the app keeps about a user, for them to download.
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data is everything kept about a user, as it appears in the archive's data.json.
+type Data struct {
+	ExportedAt    time.Time               `json:"exported_at"`
+	Profile       models.User             `json:"profile"`
+	Bottles       []models.AuthoredBottle `json:"bottles"`
+	Catches       []models.Catch          `json:"catches"`
+	Replies       []models.Reply          `json:"replies"`
+	Bookmarks     []models.Catch          `json:"bookmarks"`
+	Notifications []models.Notification   `json:"notifications"`
+}
+
+// Collect gathers the user's data from the repositories.
+func Collect(ctx context.Context, repo *storage.Repository, userID uuid.UUID) (*Data, error) {
+	profile, err := repo.User.GetUserProfile(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Data{ExportedAt: time.Now().UTC(), Profile: *profile}
+	if data.Bottles, err = all(func(page models.PaginationRequest) ([]models.AuthoredBottle, error) {
+		return repo.Bottle.GetBottlesByUser(ctx, userID, page)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to collect bottles: %w", err)
+	}
+	if data.Catches, err = all(func(page models.PaginationRequest) ([]models.Catch, error) {
+		return repo.Bottle.GetCatches(ctx, userID, page)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to collect catches: %w", err)
+	}
+	if data.Replies, err = all(func(page models.PaginationRequest) ([]models.Reply, error) {
+		return repo.Bottle.GetRepliesByUser(ctx, userID, page)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to collect replies: %w", err)
+	}
+	if data.Bookmarks, err = all(func(page models.PaginationRequest) ([]models.Catch, error) {
+		return repo.Bottle.GetBookmarks(ctx, userID, page)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to collect bookmarks: %w", err)
+	}
+	if data.Notifications, err = all(func(page models.PaginationRequest) ([]models.Notification, error) {
+		return repo.Notification.GetNotifications(ctx, userID, false, page)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to collect notifications: %w", err)
+	}
+	for i := range data.Notifications {
+		data.Notifications[i].Describe()
+	}
+
+	return data, nil
+}
+
+// all pages through fetch until it runs out.
+func all[T any](fetch func(page models.PaginationRequest) ([]T, error)) ([]T, error) {
+	items := []T{}
+	page := models.PaginationRequest{Limit: models.MaxPageLimit}
+	for {
+		batch, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) < page.Limit {
+			return items, nil
+		}
+		page.Offset += page.Limit
+	}
+}
+
+// Archive writes the data as a zip of data.json, holding all of it, and a CSV file per table
+// for spreadsheets.
+func (d *Data) Archive() ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := d.create(zw, "data.json")
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+
+	profile := [][]string{{"id", "email", "first_name", "last_name", "display_name", "bio", "role"}}
+	profile = append(profile, []string{d.Profile.ID.String(), d.Profile.Email, text(d.Profile.FirstName), text(d.Profile.LastName), text(d.Profile.DisplayName), text(d.Profile.Bio), string(d.Profile.Role)})
+
+	bottles := [][]string{{"id", "created_at", "status", "tag_id", "author", "location_from", "content", "catch_count", "reply_count"}}
+	for _, b := range d.Bottles {
+		bottles = append(bottles, []string{
+			strconv.Itoa(b.ID), timestamp(&b.CreatedAt), string(b.Status), strconv.Itoa(b.TagID),
+			text(b.Author), text(b.LocationFrom), b.Content, strconv.Itoa(b.CatchCount), strconv.Itoa(b.ReplyCount),
+		})
+	}
+
+	catches := [][]string{{"bottle_id", "seen_at", "author", "content", "bookmarked"}}
+	for _, c := range d.Catches {
+		catches = append(catches, []string{
+			strconv.Itoa(c.ID), timestamp(&c.SeenAt), text(c.Author), c.Content, strconv.FormatBool(c.Bookmarked),
+		})
+	}
+
+	replies := [][]string{{"id", "bottle_id", "created_at", "content"}}
+	for _, r := range d.Replies {
+		replies = append(replies, []string{strconv.Itoa(r.ID), strconv.Itoa(r.BottleID), timestamp(&r.CreatedAt), r.Content})
+	}
+
+	bookmarks := [][]string{{"bottle_id", "seen_at", "author", "content", "note"}}
+	for _, b := range d.Bookmarks {
+		bookmarks = append(bookmarks, []string{strconv.Itoa(b.ID), timestamp(&b.SeenAt), text(b.Author), b.Content, text(b.Note)})
+	}
+
+	notifications := [][]string{{"id", "type", "bottle_id", "message", "count", "created_at", "read_at"}}
+	for _, n := range d.Notifications {
+		bottleID := ""
+		if n.BottleID != nil {
+			bottleID = strconv.Itoa(*n.BottleID)
+		}
+		notifications = append(notifications, []string{
+			strconv.Itoa(n.ID), string(n.Type), bottleID, n.Message, strconv.Itoa(n.Count), timestamp(&n.CreatedAt), timestamp(n.ReadAt),
+		})
+	}
+
+	tables := []struct {
+		name    string
+		records [][]string
+	}{
+		{"profile.csv", profile},
+		{"bottles.csv", bottles},
+		{"catches.csv", catches},
+		{"replies.csv", replies},
+		{"bookmarks.csv", bookmarks},
+		{"notifications.csv", notifications},
+	}
+	for _, table := range tables {
+		w, err := d.create(zw, table.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := csv.NewWriter(w).WriteAll(table.records); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", table.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// create adds a compressed file to the archive, dated when the data was exported.
+func (d *Data) create(zw *zip.Writer, name string) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: d.ExportedAt})
+}
+
+func text(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,113 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"hackmit/internal/config"
+	"hackmit/internal/export"
+	"hackmit/internal/models"
+	"hackmit/internal/storage/memory"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository(nil)
+
+	userID := uuid.New()
+	if _, err := repo.User.AddUser(ctx, userID.String(), nil, nil, "sailor@example.com"); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	tag, err := repo.Tag.GetDefaultTag(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultTag: %v", err)
+	}
+	if _, err := repo.Bottle.CreateBottle(ctx, models.CreateBottleRequest{Content: "message, with a comma", TagID: &tag.ID, UserID: &userID}); err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+
+	exporter := export.NewExporter(repo, time.Hour)
+	job, err := exporter.Request(ctx, userID)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	built, err := exporter.RunPending(ctx)
+	if err != nil {
+		t.Fatalf("RunPending: %v", err)
+	}
+	if built != 1 {
+		t.Errorf("RunPending built %d exports, want 1", built)
+	}
+
+	archive, err := repo.Export.GetExportArchive(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetExportArchive: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"data.json", "profile.csv", "bottles.csv", "catches.csv", "replies.csv", "bookmarks.csv", "notifications.csv"} {
+		if files[name] == nil {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+
+	f, err := files["data.json"].Open()
+	if err != nil {
+		t.Fatalf("opening data.json: %v", err)
+	}
+	defer f.Close()
+	var data export.Data
+	if err := json.NewDecoder(f).Decode(&data); err != nil {
+		t.Fatalf("decoding data.json: %v", err)
+	}
+	if data.Profile.Email != "sailor@example.com" || len(data.Bottles) != 1 || data.Bottles[0].Content != "message, with a comma" {
+		t.Errorf("data.json = %+v, want the profile and its bottle", data)
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := export.NewSigner(config.Export{SigningSecret: "test-secret", LinkTTL: time.Minute}, config.Application{Environment: "test"})
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	if _, err := export.NewSigner(config.Export{}, config.Application{Environment: "production"}); err == nil {
+		t.Error("NewSigner without a secret outside development succeeded")
+	}
+
+	jobID := uuid.New()
+	link, err := url.Parse(signer.URL(jobID))
+	if err != nil {
+		t.Fatalf("parsing link: %v", err)
+	}
+	if !strings.Contains(link.Path, jobID.String()) {
+		t.Errorf("link %s doesn't name job %s", link, jobID)
+	}
+	expires, signature := link.Query().Get("expires"), link.Query().Get("signature")
+
+	if err := signer.Verify(jobID, expires, signature); err != nil {
+		t.Errorf("Verify of a fresh link: %v", err)
+	}
+	if err := signer.Verify(uuid.New(), expires, signature); err == nil {
+		t.Error("Verify accepted the signature for another export")
+	}
+	if err := signer.Verify(jobID, expires+"0", signature); err == nil {
+		t.Error("Verify accepted an extended expiry")
+	}
+	if err := signer.Verify(jobID, "1", signature); err == nil {
+		t.Error("Verify accepted an expired link")
+	}
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// pollInterval is how often the exporter looks for jobs queued by other instances.
+	pollInterval = 30 * time.Second
+	// staleAfter is how long a job may run before it is taken to be abandoned and built again.
+	staleAfter = 10 * time.Minute
+)
+
+// Exporter queues data exports and builds them in the background.
+type Exporter struct {
+	repo      *storage.Repository
+	retention time.Duration
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func NewExporter(repo *storage.Repository, retention time.Duration) *Exporter {
+	return &Exporter{
+		repo:      repo,
+		retention: retention,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Request queues an export of the user's data and wakes the worker to build it.
+func (e *Exporter) Request(ctx context.Context, userID uuid.UUID) (*models.ExportJob, error) {
+	job, err := e.repo.Export.CreateExportJob(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// RunPending builds queued exports until there are none left and deletes expired ones,
+// returning how many were built.
+func (e *Exporter) RunPending(ctx context.Context) (int, error) {
+	if _, err := e.repo.Export.DeleteExpiredExports(ctx, time.Now()); err != nil {
+		return 0, err
+	}
+
+	built := 0
+	for {
+		job, err := e.repo.Export.ClaimExportJob(ctx, time.Now().Add(-staleAfter))
+		if errs.IsNotFound(err) {
+			return built, nil
+		}
+		if err != nil {
+			return built, err
+		}
+
+		if err := e.build(ctx, job); err != nil {
+			slog.Error("failed to build data export", "job_id", job.ID, "user_id", job.UserID, "error", err)
+			if err := e.repo.Export.FailExportJob(ctx, job.ID, "Your data could not be exported; please try again"); err != nil {
+				return built, err
+			}
+			continue
+		}
+		built++
+	}
+}
+
+func (e *Exporter) build(ctx context.Context, job *models.ExportJob) error {
+	data, err := Collect(ctx, e.repo, job.UserID)
+	if err != nil {
+		return err
+	}
+	archive, err := data.Archive()
+	if err != nil {
+		return err
+	}
+	return e.repo.Export.CompleteExportJob(ctx, job.ID, archive, time.Now().Add(e.retention))
+}
+
+// Start builds exports in the background until Close.
+func (e *Exporter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.done.Add(1)
+	go e.work(ctx)
+}
+
+// Close stops the exporter and waits for an export in progress to finish.
+func (e *Exporter) Close() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.done.Wait()
+}
+
+func (e *Exporter) work(ctx context.Context) {
+	defer e.done.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.RunPending(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to run data exports", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.wake:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hackmit/internal/config"
+	"hackmit/internal/errs"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signer hands out download links that work without signing in, for a short while, so that
+// they can be opened outside the app.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner returns a signer for the configured key. Without one, development servers sign with
+// a random key that dies with the process.
+func NewSigner(cfg config.Export, app config.Application) (*Signer, error) {
+	secret := []byte(cfg.SigningSecret)
+	if len(secret) == 0 {
+		if app.Environment != "development" {
+			return nil, errors.New("EXPORT_SIGNING_SECRET is required")
+		}
+		secret = make([]byte, 32)
+		rand.Read(secret)
+		slog.Warn("EXPORT_SIGNING_SECRET is not set; export links are signed with a random key and won't survive a restart")
+	}
+
+	return &Signer{
+		secret,
+		cfg.LinkTTL,
+	}, nil
+}
+
+// URL returns a signed link to download the export's archive.
+func (s *Signer) URL(jobID uuid.UUID) string {
+	expires := strconv.FormatInt(time.Now().Add(s.ttl).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.sign(jobID, expires)},
+	}
+	return fmt.Sprintf("/api/v1/exports/%s/download?%s", jobID, query.Encode())
+}
+
+// Verify checks that a link to the export was signed here and hasn't expired.
+func (s *Signer) Verify(jobID uuid.UUID, expires string, signature string) error {
+	invalid := errs.Forbidden("Invalid or expired download link")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return invalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(jobID, expires))) {
+		return invalid
+	}
+	return nil
+}
+
+func (s *Signer) sign(jobID uuid.UUID, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(jobID.String() + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
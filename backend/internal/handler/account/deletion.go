@@ -0,0 +1,63 @@
+package account
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeleteAccount handles DELETE /api/v1/me/account. The account is deleted once the grace
+// period is over; until then the user can sign in and cancel.
+func (h *Handler) DeleteAccount(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	// The body is optional; without one the user's bottles are deleted with them
+	var req models.DeleteAccountRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+		}
+	}
+
+	deletion, err := h.deleter.Schedule(c.Context(), userID, req.KeepBottles)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(deletion)
+}
+
+// GetDeletion handles GET /api/v1/me/account/deletion
+func (h *Handler) GetDeletion(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	deletion, err := h.userRepository.GetDeletion(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deletion)
+}
+
+// CancelDeletion handles DELETE /api/v1/me/account/deletion
+func (h *Handler) CancelDeletion(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.userRepository.CancelDeletion(c.Context(), userID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+package account
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequestExport handles POST /api/v1/me/export. The archive is built in the background; poll
+// the returned job for a download link.
+func (h *Handler) RequestExport(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	job, err := h.exporter.Request(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetExport handles GET /api/v1/me/export/:id
+func (h *Handler) GetExport(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid export ID")
+	}
+
+	// Other users' exports are as good as missing
+	job, err := h.exportRepository.GetExportJob(c.Context(), jobID)
+	if err != nil {
+		return err
+	}
+	if job.UserID != userID {
+		return errs.NotFound("export", "id", jobID.String())
+	}
+
+	if job.Status == models.ExportStatusReady {
+		job.DownloadURL = h.signer.URL(job.ID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// DownloadExport handles GET /api/v1/exports/:id/download. The signed link stands in for
+// signing in.
+func (h *Handler) DownloadExport(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid export ID")
+	}
+	if err := h.signer.Verify(jobID, c.Query("expires"), c.Query("signature")); err != nil {
+		return err
+	}
+
+	archive, err := h.exportRepository.GetExportArchive(c.Context(), jobID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, jobID))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).Send(archive)
+}
@@ -0,0 +1,25 @@
+package account
+
+import (
+	"hackmit/internal/account"
+	"hackmit/internal/export"
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	deleter          *account.Deleter
+	exporter         *export.Exporter
+	signer           *export.Signer
+	userRepository   storage.UserRepository
+	exportRepository storage.ExportRepository
+}
+
+func NewHandler(deleter *account.Deleter, exporter *export.Exporter, signer *export.Signer, userRepository storage.UserRepository, exportRepository storage.ExportRepository) *Handler {
+	return &Handler{
+		deleter,
+		exporter,
+		signer,
+		userRepository,
+		exportRepository,
+	}
+}
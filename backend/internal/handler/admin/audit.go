@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxReasonLength bounds the explanation an admin gives for an action.
+const maxReasonLength = 500
+
+// GetAuditLog handles GET /api/v1/admin/audit, most recent entries first. Entries can be
+// filtered by action, actor_id, target_type and target_id, and to those written in
+// [since, until), both RFC 3339 times.
+func (h *Handler) GetAuditLog(c *fiber.Ctx) error {
+	var filterParams models.ListAuditRequest
+	if err := c.QueryParser(&filterParams); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+
+	invalid := map[string]string{}
+	if actor := c.Query("actor_id"); actor != "" {
+		actorID, err := uuid.Parse(actor)
+		if err != nil {
+			invalid["actor_id"] = "must be a user ID"
+		}
+		filterParams.ActorID = &actorID
+	}
+	for name, bound := range map[string]**time.Time{"since": &filterParams.Since, "until": &filterParams.Until} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalid[name] = "must be an RFC 3339 time"
+			continue
+		}
+		at = at.UTC()
+		*bound = &at
+	}
+	if len(invalid) > 0 {
+		return errs.InvalidRequestData(invalid)
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	entries, err := h.auditRepository.ListAudit(c.Context(), filterParams, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"entries": entries,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
+
+// parseReason trims an admin's explanation, dropping it if it is blank.
+func parseReason(reason *string) (*string, error) {
+	if reason == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*reason)
+	if utf8.RuneCountInString(trimmed) > maxReasonLength {
+		return nil, errs.InvalidRequestData(map[string]string{"reason": fmt.Sprintf("must be at most %d characters", maxReasonLength)})
+	}
+	if trimmed == "" {
+		return nil, nil
+	}
+	return &trimmed, nil
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetBottle handles GET /api/v1/admin/bottles/:id. Along with the bottle as readers see it,
+// moderators get what its author wrote if it was masked.
+func (h *Handler) GetBottle(c *fiber.Ctx) error {
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	bottle, err := h.bottleRepository.GetBottleByID(c.Context(), bottleID)
+	if err != nil {
+		return err
+	}
+
+	original, err := h.bottleRepository.GetBottleOriginal(c.Context(), bottleID)
+	if errs.IsNotFound(err) {
+		original, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"bottle":   bottle.Moderated(),
+		"original": original,
+	})
+}
+
+// UpdateOceanSettings handles PATCH /api/v1/admin/oceans/:id
+func (h *Handler) UpdateOceanSettings(c *fiber.Ctx) error {
+	adminID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	oceanID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid ocean ID")
+	}
+
+	var req models.UpdateOceanSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	var ocean *models.Ocean
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		if ocean, err = tx.Ocean.UpdateOceanSettings(c.Context(), oceanID, req); err != nil {
+			return err
+		}
+		target := strconv.Itoa(ocean.ID)
+		_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+			Action:     models.AuditOceanUpdated,
+			ActorID:    &adminID,
+			TargetType: models.AuditTargetOcean,
+			TargetID:   &target,
+			Details:    map[string]any{"mask_profanity": ocean.MaskProfanity},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ocean)
+}
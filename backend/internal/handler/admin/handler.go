@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	userRepository   storage.UserRepository
+	bottleRepository storage.BottleRepository
+	oceanRepository  storage.OceanRepository
+	auditRepository  storage.AuditRepository
+	transactor       storage.Transactor
+}
+
+func NewHandler(userRepository storage.UserRepository, bottleRepository storage.BottleRepository, oceanRepository storage.OceanRepository, auditRepository storage.AuditRepository, transactor storage.Transactor) *Handler {
+	return &Handler{
+		userRepository,
+		bottleRepository,
+		oceanRepository,
+		auditRepository,
+		transactor,
+	}
+}
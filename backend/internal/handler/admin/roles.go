@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/avatar"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *Handler) GetUser(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+
+	user, err := h.userRepository.GetUserProfile(c.Context(), userID.String())
+	if err != nil {
+		return err
+	}
+	if user.AvatarKey != nil {
+		user.Avatar = avatar.URLs(*user.AvatarKey)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(user)
+}
+
+// SetRole handles PUT /api/v1/admin/users/:id/role. Admins can't change their own role, so
+// that the last of them can't lock everyone out.
+func (h *Handler) SetRole(c *fiber.Ctx) error {
+	adminID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+	if userID == adminID {
+		return errs.Forbidden("You cannot change your own role")
+	}
+
+	var req models.SetRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+	if !req.Role.Valid() {
+		return errs.InvalidRequestData(map[string]string{"role": fmt.Sprintf("must be one of %v", models.Roles)})
+	}
+	if req.Reason, err = parseReason(req.Reason); err != nil {
+		return err
+	}
+
+	role, err := h.userRepository.GetUserRole(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	if role == req.Role {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	var change *models.RoleChange
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		if change, err = tx.User.SetUserRole(c.Context(), userID, req.Role, &adminID); err != nil {
+			return err
+		}
+		target := userID.String()
+		_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+			Action:     models.AuditUserRoleChanged,
+			ActorID:    &adminID,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &target,
+			Reason:     req.Reason,
+			Details:    map[string]any{"old_role": change.OldRole, "new_role": change.NewRole},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(change)
+}
+
+// GetRoleChanges handles GET /api/v1/admin/users/:id/role-changes
+func (h *Handler) GetRoleChanges(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+
+	changes, err := h.userRepository.GetRoleChanges(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"role_changes": changes,
+	})
+}
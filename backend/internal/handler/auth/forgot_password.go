@@ -2,7 +2,6 @@ package auth
 
 import (
 	"fmt"
-	"hackmit/internal/auth"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,7 +19,7 @@ func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
 	}
 
-	err := auth.SupabaseResetPassword(&h.config, payload.Email)
+	err := h.identity.RecoverPassword(c.Context(), payload.Email)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Password reset request failed: %v", err)})
 	}
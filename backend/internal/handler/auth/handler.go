@@ -1,14 +1,16 @@
 package auth
 
 import (
-	"hackmit/internal/config"
+	"hackmit/internal/auth"
 	"hackmit/internal/storage"
 )
 
 type Handler struct {
-	config          config.Supabase
-	userRepository  storage.UserRepository
-	oceanRepository storage.OceanRepository
+	identity          auth.IdentityProvider
+	userRepository    storage.UserRepository
+	oceanRepository   storage.OceanRepository
+	sessionRepository storage.SessionRepository
+	transactor        storage.Transactor
 }
 
 type Credentials struct {
@@ -19,10 +21,30 @@ type Credentials struct {
 	RememberMe bool
 }
 
-func NewHandler(config config.Supabase, userRepository storage.UserRepository, oceanRepository storage.OceanRepository) *Handler {
+// SessionResponse is what signing up, in or refreshing answers with. The refresh token is left
+// out: it only travels in its HttpOnly cookie, out of reach of scripts.
+type SessionResponse struct {
+	AccessToken string           `json:"access_token"`
+	TokenType   string           `json:"token_type"`
+	ExpiresIn   int              `json:"expires_in"`
+	User        auth.SessionUser `json:"user"`
+}
+
+func newSessionResponse(session *auth.Session) SessionResponse {
+	return SessionResponse{
+		session.AccessToken,
+		session.TokenType,
+		session.ExpiresIn,
+		session.User,
+	}
+}
+
+func NewHandler(identity auth.IdentityProvider, userRepository storage.UserRepository, oceanRepository storage.OceanRepository, sessionRepository storage.SessionRepository, transactor storage.Transactor) *Handler {
 	return &Handler{
-		config,
+		identity,
 		userRepository,
 		oceanRepository,
+		sessionRepository,
+		transactor,
 	}
 }
@@ -16,21 +16,21 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	// Call SupabaseLogin function
-	signInResponse, err := auth.SupabaseLogin(&h.config, creds.Email, creds.Password)
+	signInResponse, err := h.identity.Login(c.Context(), creds.Email, creds.Password)
 	if err != nil {
-		fmt.Println("Supabase login error:", err)
+		fmt.Println("Login error:", err)
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	fmt.Println(creds.RememberMe)
 
-	if creds.RememberMe {
-		cookieExp = time.Now().Add(7 * 24 * time.Hour)
-	} else {
-		cookieExp = time.Time{}
+	// Track the session so its refresh token can be exchanged
+	if err := auth.RecordSession(c.Context(), h.sessionRepository, signInResponse, creds.RememberMe, auth.DeviceOf(c)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to record session: %v", err)})
 	}
 
+	cookieExp = auth.CookieExpiry(creds.RememberMe)
+
 	// Set cookies
 	c.Cookie(&fiber.Cookie{
 		Name:     "userID",
@@ -40,13 +40,7 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		SameSite: "Lax",
 	})
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "jwt",
-		Value:    signInResponse.AccessToken,
-		Expires:  cookieExp,
-		Secure:   true,
-		SameSite: "Lax",
-	})
+	auth.SetSessionCookies(c, signInResponse, cookieExp)
 
-	return c.Status(fiber.StatusOK).JSON(signInResponse)
+	return c.Status(fiber.StatusOK).JSON(newSessionResponse(signInResponse))
 }
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"errors"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Refresh exchanges the refresh token cookie for new tokens, rotating the refresh token.
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	refreshToken := c.Cookies(auth.RefreshTokenCookie)
+	if refreshToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No refresh token found"})
+	}
+
+	session, record, err := auth.RefreshSession(c.Context(), h.identity, h.sessionRepository, refreshToken)
+	var httpErr errs.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == fiber.StatusUnauthorized {
+		// The session is over; stop the browser from trying again
+		auth.ClearSessionCookies(c)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": httpErr.Message})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	auth.SetSessionCookies(c, session, auth.CookieExpiry(record.RememberMe))
+
+	return c.Status(fiber.StatusOK).JSON(newSessionResponse(session))
+}
@@ -1,9 +1,6 @@
 package auth
 
 import (
-	"fmt"
-	"hackmit/internal/auth"
-
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -26,9 +23,9 @@ func (h *Handler) ResetPassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Reset token is missing"})
 	}
 
-	err := auth.SupabaseUpdatePassword(&h.config, token, payload.Password)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Password update failed: %v", err)})
+	// Used and expired tokens are turned away with their own errors
+	if err := h.identity.UpdatePassword(c.Context(), token, payload.Password); err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Password has been reset successfully"})
@@ -15,8 +15,11 @@ func (h *Handler) SignOut(c *fiber.Ctx) error {
 	}
 	fmt.Println("Access Token:", accessToken)
 
-	// Call SupabaseRevokeSession to invalidate the session
-	_ = auth.SupabaseRevokeSession(&h.config, accessToken)
+	// End the session here, so its refresh token is dead, then with the identity provider
+	if token, err := h.identity.Verify(c.Context(), accessToken); err == nil {
+		_ = h.sessionRepository.RevokeSession(c.Context(), token.SessionID)
+	}
+	_ = h.identity.Logout(c.Context(), accessToken)
 
 	clearCookie := func(name string) {
 		c.Cookie(&fiber.Cookie{
@@ -30,7 +33,8 @@ func (h *Handler) SignOut(c *fiber.Ctx) error {
 		})
 	}
 
-	clearCookie("jwt")
+	clearCookie(auth.AccessTokenCookie)
+	clearCookie(auth.RefreshTokenCookie)
 	clearCookie("userID")
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
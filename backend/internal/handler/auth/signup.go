@@ -3,10 +3,11 @@ package auth
 import (
 	"fmt"
 	"hackmit/internal/auth"
+	"hackmit/internal/storage"
+	"log/slog"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 func (h *Handler) SignUp(c *fiber.Ctx) error {
@@ -15,46 +16,46 @@ func (h *Handler) SignUp(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	response, err := auth.SupabaseSignup(&h.config, creds.Email, creds.Password)
+	response, err := h.identity.SignUp(c.Context(), creds.Email, creds.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Signup request failed: %v", err)})
+		return err
 	}
+	userUUID := response.User.ID
 
-	// Parse the string ID to UUID
-	userUUID, err := uuid.Parse(response.User.ID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Invalid user ID from Supabase: %v", err)})
-	}
-
-	_, err = h.userRepository.AddUser(c.Context(), userUUID.String(), creds.FirstName, creds.LastName, creds.Email)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Adding User request failed: %v", err)})
-	}
-
-	_, err = h.oceanRepository.CreateOcean(c.Context(), creds.FirstName, getOceanName(creds.FirstName), userUUID)
+	// The profile, personal ocean and session record are created together or not at all
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		if _, err := tx.User.AddUser(c.Context(), userUUID.String(), creds.FirstName, creds.LastName, creds.Email); err != nil {
+			return fmt.Errorf("Adding User request failed: %w", err)
+		}
+		if _, err := tx.Ocean.CreateOcean(c.Context(), creds.FirstName, getOceanName(creds.FirstName), userUUID); err != nil {
+			return fmt.Errorf("Creating personal ocean failed: %w", err)
+		}
+		if err := auth.RecordSession(c.Context(), tx.Session, response, true, auth.DeviceOf(c)); err != nil {
+			return fmt.Errorf("Recording session failed: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Creating personal ocean failed: %v", err)})
+		// Don't leave behind an account the app knows nothing about
+		if deleteErr := h.identity.DeleteUser(c.Context(), userUUID); deleteErr != nil {
+			slog.Error("failed to remove account after signup failed", "user_id", userUUID, "error", deleteErr)
+		}
+		return err
 	}
 
 	// Set cookies with the JWT token
 	expiration := time.Now().Add(30 * 24 * time.Hour) // 30 days
-	c.Cookie(&fiber.Cookie{
-		Name:     "jwt",
-		Value:    response.AccessToken,
-		Expires:  expiration,
-		Secure:   true,
-		SameSite: "Lax",
-	})
+	auth.SetSessionCookies(c, response, expiration)
 
 	c.Cookie(&fiber.Cookie{
-		Name:     "user_id",        // Add the cookie name
-		Value:    response.User.ID, // This is already a string
+		Name:     "user_id", // Add the cookie name
+		Value:    userUUID.String(),
 		Expires:  expiration,
 		Secure:   true,
 		SameSite: "Lax",
 	})
 
-	return c.Status(fiber.StatusCreated).JSON(response)
+	return c.Status(fiber.StatusCreated).JSON(newSessionResponse(response))
 }
 
 func getOceanName(firstName *string) *string {
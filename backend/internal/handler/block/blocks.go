@@ -0,0 +1,131 @@
+package block
+
+import (
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetBlocks handles GET /api/v1/me/blocks
+func (h *Handler) GetBlocks(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := h.blockRepository.GetBlocks(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	for i := range blocks {
+		reveal(&blocks[i])
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"blocks": blocks,
+	})
+}
+
+// BlockUser handles POST /api/v1/me/blocks/:userId
+func (h *Handler) BlockUser(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	blockedID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+	if blockedID == userID {
+		return errs.BadRequest("You cannot block yourself")
+	}
+
+	block, err := h.blockRepository.BlockUser(c.Context(), userID, blockedID, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reveal(block))
+}
+
+// UnblockUser handles DELETE /api/v1/me/blocks/:userId
+func (h *Handler) UnblockUser(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	blockedID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+
+	if err := h.blockRepository.UnblockUser(c.Context(), userID, blockedID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// BlockBottleAuthor handles POST /api/v1/me/blocks/bottles/:bottleId, which blocks whoever
+// wrote the bottle without telling the reader who that is.
+func (h *Handler) BlockBottleAuthor(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("bottleId")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	bottle, err := h.bottleRepository.GetBottleByID(c.Context(), bottleID)
+	if err != nil {
+		return err
+	}
+	if bottle.UserID == nil {
+		return errs.UnprocessableEntity("This bottle was thrown anonymously, so its author can't be blocked")
+	}
+	if *bottle.UserID == userID {
+		return errs.BadRequest("You cannot block yourself")
+	}
+
+	block, err := h.blockRepository.BlockUser(c.Context(), userID, *bottle.UserID, &bottle.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reveal(block))
+}
+
+// UnblockBottleAuthor handles DELETE /api/v1/me/blocks/bottles/:bottleId
+func (h *Handler) UnblockBottleAuthor(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("bottleId")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	if err := h.blockRepository.UnblockBottleAuthor(c.Context(), userID, bottleID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// reveal shows who was blocked, unless they were blocked as the anonymous author of a bottle.
+func reveal(block *models.Block) *models.Block {
+	if block.BottleID == nil {
+		block.UserID = &block.BlockedID
+	}
+	return block
+}
@@ -0,0 +1,17 @@
+package block
+
+import (
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	blockRepository  storage.BlockRepository
+	bottleRepository storage.BottleRepository
+}
+
+func NewHandler(blockRepository storage.BlockRepository, bottleRepository storage.BottleRepository) *Handler {
+	return &Handler{
+		blockRepository,
+		bottleRepository,
+	}
+}
@@ -0,0 +1,86 @@
+package bottle
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxNoteLength = 500
+
+// GetBookmarks handles GET /api/v1/me/bookmarks
+func (h *Handler) GetBookmarks(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	bookmarks, err := h.bottleRepository.GetBookmarks(c.Context(), userID, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"bookmarks": bookmarks,
+		"limit":     page.Limit,
+		"offset":    page.Offset,
+	})
+}
+
+// SaveBookmark handles PUT /api/v1/me/bookmarks/:id
+func (h *Handler) SaveBookmark(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	var req models.SaveBookmarkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+		}
+	}
+	if req.Note != nil && len([]rune(*req.Note)) > maxNoteLength {
+		return errs.BadRequest(fmt.Sprintf("Note must be at most %d characters", maxNoteLength))
+	}
+
+	bookmark, err := h.bottleRepository.SaveBookmark(c.Context(), userID, bottleID, req.Note)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(bookmark)
+}
+
+// DeleteBookmark handles DELETE /api/v1/me/bookmarks/:id
+func (h *Handler) DeleteBookmark(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	if err := h.bottleRepository.DeleteBookmark(c.Context(), userID, bottleID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
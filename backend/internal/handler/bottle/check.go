@@ -0,0 +1,59 @@
+package bottle
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CheckDraft handles POST /api/v1/moderation/check. It takes the same body as throwing a
+// bottle and answers with the verdict throwing it would get, without throwing it.
+func (h *Handler) CheckDraft(c *fiber.Ctx) error {
+	var filterParams models.CreateBottleRequest
+	if err := c.BodyParser(&filterParams); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	var authorID *uuid.UUID
+	if userID, err := auth.UserID(c); err == nil {
+		authorID = &userID
+	}
+
+	screened, err := h.screen(c.Context(), &filterParams, authorID)
+	if err != nil {
+		return err
+	}
+
+	check := models.ModerationCheck{
+		Verdict:    screened.verdict,
+		Categories: []string{},
+		Spans:      []models.ModerationSpan{},
+		Language:   screened.language,
+	}
+	for _, found := range screened.findings {
+		category := found.detection.Pattern.Category.String()
+		if !slices.Contains(check.Categories, category) {
+			check.Categories = append(check.Categories, category)
+		}
+		span := models.ModerationSpan{
+			Field:    found.field,
+			Start:    utf8.RuneCountInString(found.text[:found.detection.StartIndex]),
+			End:      utf8.RuneCountInString(found.text[:found.detection.EndIndex]),
+			Category: category,
+			Severity: found.detection.Pattern.Severity.String(),
+			Masked:   found.masked,
+		}
+		// Several rules can match the same words
+		if !slices.Contains(check.Spans, span) {
+			check.Spans = append(check.Spans, span)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(check)
+}
@@ -1,14 +1,22 @@
 package bottle
 
 import (
+	"context"
 	"fmt"
+	"hackmit/internal/auth"
 	"hackmit/internal/errs"
+	"hackmit/internal/language"
 	"hackmit/internal/models"
+	"hackmit/internal/storage"
 	"log"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // SeverityLevel represents the severity of detected content
@@ -69,6 +77,10 @@ func (c Category) String() string {
 
 // Pattern represents a detection pattern
 type Pattern struct {
+	// ID names the rule in audit entries; it must not change once the rule is in use.
+	ID string
+	// Language is that of the rule set the pattern belongs to.
+	Language    models.Language
 	Regex       *regexp.Regexp
 	Category    Category
 	Severity    SeverityLevel
@@ -91,11 +103,14 @@ type AnalysisResult struct {
 	TotalScore      float64
 	Detections      []DetectionResult
 	DebugInfo       string
+	// Language is what the text was read as, which decides the rule sets applied to it.
+	Language models.Language
 }
 
 // HateSpeechChecker is the main checker struct
 type HateSpeechChecker struct {
 	patterns              []Pattern
+	detector              language.Detector
 	threshold             float64
 	caseSensitive         bool
 	enableProfanityFilter bool
@@ -108,119 +123,169 @@ func NewHateSpeechChecker() *HateSpeechChecker {
 		threshold:             0.7,
 		caseSensitive:         false,
 		enableProfanityFilter: true,
-		debugMode:             true, // Enable debug mode by default
+		debugMode:             false, // Debug output logs what people write, so it is off unless asked for
+		detector:              language.NewNGram(),
 	}
 	checker.initializePatterns()
 	return checker
 }
 
-// initializePatterns sets up the detection patterns
+// rule is a detection pattern as written in a rule set.
+type rule struct {
+	id          string
+	pattern     string
+	category    Category
+	severity    SeverityLevel
+	description string
+	confidence  float64
+}
+
+// initializePatterns sets up the detection patterns of every language's rule set
 func (hsc *HateSpeechChecker) initializePatterns() {
-	patterns := []struct {
-		pattern     string
-		category    Category
-		severity    SeverityLevel
-		description string
-		confidence  float64
-	}{
+	english := []rule{
 		// Direct hate speech and threats
-		{`\b(hate|despise|detest|loathe)\s+(all|those|these|every|you)\s+\w+`, CategoryHate, SeverityHigh, "General hate expression", 0.85},
-		{`\b(kill|murder|eliminate|execute|exterminate)\s+(all|those|these|every)\s+\w+`, CategoryThreat, SeverityCritical, "Violent threat against groups", 0.95},
-		{`\byou\s+(should|deserve to|need to|ought to)\s+(die|disappear|leave|burn|suffer)`, CategoryThreat, SeverityHigh, "Personal threat", 0.90},
-		{`\b(kill|hurt|harm|beat|attack)\s+(yourself|urself)`, CategoryThreat, SeverityCritical, "Self-harm encouragement", 0.95},
-		{`\bcommit\s+suicide\b`, CategoryThreat, SeverityCritical, "Suicide encouragement", 0.90},
-		{`\bend\s+your\s+life\b`, CategoryThreat, SeverityCritical, "Suicide encouragement", 0.85},
+		{"hate.group-hatred", `\b(hate|despise|detest|loathe)\s+(all|those|these|every|you)\s+\w+`, CategoryHate, SeverityHigh, "General hate expression", 0.85},
+		{"threat.group-violence", `\b(kill|murder|eliminate|execute|exterminate)\s+(all|those|these|every)\s+\w+`, CategoryThreat, SeverityCritical, "Violent threat against groups", 0.95},
+		{"threat.personal", `\byou\s+(should|deserve to|need to|ought to)\s+(die|disappear|leave|burn|suffer)`, CategoryThreat, SeverityHigh, "Personal threat", 0.90},
+		{"threat.self-harm", `\b(kill|hurt|harm|beat|attack)\s+(yourself|urself)`, CategoryThreat, SeverityCritical, "Self-harm encouragement", 0.95},
+		{"threat.suicide", `\bcommit\s+suicide\b`, CategoryThreat, SeverityCritical, "Suicide encouragement", 0.90},
+		{"threat.end-your-life", `\bend\s+your\s+life\b`, CategoryThreat, SeverityCritical, "Suicide encouragement", 0.85},
 
 		// Discrimination and dehumanization
-		{`\b(inferior|subhuman|worthless|animals|vermin|parasites)\s+(race|people|group|beings)`, CategoryDiscrimination, SeverityHigh, "Dehumanizing language", 0.85},
-		{`\b(go back to|don't belong|not welcome|not wanted|get out)`, CategoryDiscrimination, SeverityMedium, "Exclusionary language", 0.65},
-		{`\b(pure|master|superior)\s+(race|blood|breeding|genes)`, CategoryDiscrimination, SeverityCritical, "Supremacist language", 0.90},
-		{`\b(ethnic|racial)\s+(cleansing|purification|removal)`, CategoryDiscrimination, SeverityCritical, "Genocide language", 0.95},
-		{`\bfinal\s+solution\b`, CategoryDiscrimination, SeverityCritical, "Nazi reference", 0.95},
+		{"discrimination.dehumanizing", `\b(inferior|subhuman|worthless|animals|vermin|parasites)\s+(race|people|group|beings)`, CategoryDiscrimination, SeverityHigh, "Dehumanizing language", 0.85},
+		{"discrimination.exclusionary", `\b(go back to|don't belong|not welcome|not wanted|get out)`, CategoryDiscrimination, SeverityMedium, "Exclusionary language", 0.65},
+		{"discrimination.supremacist", `\b(pure|master|superior)\s+(race|blood|breeding|genes)`, CategoryDiscrimination, SeverityCritical, "Supremacist language", 0.90},
+		{"discrimination.genocide", `\b(ethnic|racial)\s+(cleansing|purification|removal)`, CategoryDiscrimination, SeverityCritical, "Genocide language", 0.95},
+		{"discrimination.final-solution", `\bfinal\s+solution\b`, CategoryDiscrimination, SeverityCritical, "Nazi reference", 0.95},
 
 		// Slurs and offensive terms (using patterns to avoid explicit terms)
-		{`\b\w*[nN][1!i][gG9]{2}[aAeE3@][hHrR]*\w*\b`, CategorySlur, SeverityCritical, "Racial slur", 0.95},
-		{`\b[fF][aA4@][gG9]{1,2}[oO0@][tT7]?[sS5$]?\b`, CategorySlur, SeverityHigh, "Homophobic slur", 0.90},
-		{`\b[rR][eE3][tT7][aA4@][rR][dD]([eE3][dD]|[sS5$])?\b`, CategorySlur, SeverityHigh, "Ableist slur", 0.85},
-		{`\b\w*[tT7][aA4@][rR][dD]\b`, CategorySlur, SeverityMedium, "Ableist suffix", 0.70},
-		{`\b[tT7]r[aA4@]nn(y|ie|ies)\b`, CategorySlur, SeverityHigh, "Transphobic slur", 0.85},
-		{`\b[kK][iI1!][kK3][eE3]\b`, CategorySlur, SeverityCritical, "Ethnic slur", 0.90},
-		{`\b[sS5$][pP][iI1!][cC][kK3]?\b`, CategorySlur, SeverityMedium, "Ethnic slur", 0.75},
+		{"slur.racial", `\b\w*[nN][1!i][gG9]{2}[aAeE3@][hHrR]*\w*\b`, CategorySlur, SeverityCritical, "Racial slur", 0.95},
+		{"slur.homophobic", `\b[fF][aA4@][gG9]{1,2}[oO0@][tT7]?[sS5$]?\b`, CategorySlur, SeverityHigh, "Homophobic slur", 0.90},
+		{"slur.ableist", `\b[rR][eE3][tT7][aA4@][rR][dD]([eE3][dD]|[sS5$])?\b`, CategorySlur, SeverityHigh, "Ableist slur", 0.85},
+		{"slur.ableist-suffix", `\b\w*[tT7][aA4@][rR][dD]\b`, CategorySlur, SeverityMedium, "Ableist suffix", 0.70},
+		{"slur.transphobic", `\b[tT7]r[aA4@]nn(y|ie|ies)\b`, CategorySlur, SeverityHigh, "Transphobic slur", 0.85},
+		{"slur.ethnic-k", `\b[kK][iI1!][kK3][eE3]\b`, CategorySlur, SeverityCritical, "Ethnic slur", 0.90},
+		{"slur.ethnic-s", `\b[sS5$][pP][iI1!][cC][kK3]?\b`, CategorySlur, SeverityMedium, "Ethnic slur", 0.75},
 
 		// Harassment and personal attacks
-		{`\b(ugly|stupid|worthless|pathetic|disgusting|repulsive)\s+(piece of|excuse for|waste of)`, CategoryHarassment, SeverityMedium, "Personal attack", 0.75},
-		{`\byou\s+(suck|blow|are\s+(garbage|trash|shit|crap))`, CategoryHarassment, SeverityMedium, "Personal insult", 0.70},
-		{`\b(shut up|stfu|fuck off|piss off|go away)\s+(bitch|whore|slut|cunt)`, CategoryHarassment, SeverityHigh, "Gendered harassment", 0.85},
-		{`\b(dumb|stupid|retarded)\s+(bitch|whore|slut|cunt|hoe)`, CategoryHarassment, SeverityHigh, "Misogynistic abuse", 0.85},
-		{`\byour\s+(mom|mother|family)\s+(is|are)\s+(dead|gonna die|should die)`, CategoryThreat, SeverityHigh, "Family threat", 0.80},
+		{"harassment.personal-attack", `\b(ugly|stupid|worthless|pathetic|disgusting|repulsive)\s+(piece of|excuse for|waste of)`, CategoryHarassment, SeverityMedium, "Personal attack", 0.75},
+		{"harassment.personal-insult", `\byou\s+(suck|blow|are\s+(garbage|trash|shit|crap))`, CategoryHarassment, SeverityMedium, "Personal insult", 0.70},
+		{"harassment.gendered", `\b(shut up|stfu|fuck off|piss off|go away)\s+(bitch|whore|slut|cunt)`, CategoryHarassment, SeverityHigh, "Gendered harassment", 0.85},
+		{"harassment.misogynistic", `\b(dumb|stupid|retarded)\s+(bitch|whore|slut|cunt|hoe)`, CategoryHarassment, SeverityHigh, "Misogynistic abuse", 0.85},
+		{"threat.family", `\byour\s+(mom|mother|family)\s+(is|are)\s+(dead|gonna die|should die)`, CategoryThreat, SeverityHigh, "Family threat", 0.80},
 
 		// Sexual harassment
-		{`\b(send|show|post)\s+(nudes|nude pics|naked pics)`, CategoryHarassment, SeverityMedium, "Sexual harassment", 0.75},
-		{`\bi\s+(want to|gonna|will)\s+(rape|molest|assault)\s+you`, CategoryThreat, SeverityCritical, "Sexual threat", 0.95},
-		{`\bsuck\s+my\s+(dick|cock|penis)`, CategoryHarassment, SeverityMedium, "Sexual harassment", 0.70},
+		{"harassment.sexual-request", `\b(send|show|post)\s+(nudes|nude pics|naked pics)`, CategoryHarassment, SeverityMedium, "Sexual harassment", 0.75},
+		{"threat.sexual", `\bi\s+(want to|gonna|will)\s+(rape|molest|assault)\s+you`, CategoryThreat, SeverityCritical, "Sexual threat", 0.95},
+		{"harassment.sexual-vulgar", `\bsuck\s+my\s+(dick|cock|penis)`, CategoryHarassment, SeverityMedium, "Sexual harassment", 0.70},
 
 		// Violence and threats
-		{`\bi\s+(will|gonna|am going to)\s+(kill|murder|hurt|beat|stab|shoot)\s+you`, CategoryThreat, SeverityCritical, "Direct threat", 0.95},
-		{`\bi\s+know\s+where\s+you\s+live`, CategoryThreat, SeverityHigh, "Stalking threat", 0.85},
-		{`\bwatch\s+your\s+back\b`, CategoryThreat, SeverityMedium, "Implicit threat", 0.65},
-		{`\byou(r|re)\s+(gonna|going to)\s+(get|be)\s+(hurt|killed|beaten|shot|stabbed)`, CategoryThreat, SeverityHigh, "Threat prediction", 0.80},
+		{"threat.direct", `\bi\s+(will|gonna|am going to)\s+(kill|murder|hurt|beat|stab|shoot)\s+you`, CategoryThreat, SeverityCritical, "Direct threat", 0.95},
+		{"threat.stalking", `\bi\s+know\s+where\s+you\s+live`, CategoryThreat, SeverityHigh, "Stalking threat", 0.85},
+		{"threat.watch-your-back", `\bwatch\s+your\s+back\b`, CategoryThreat, SeverityMedium, "Implicit threat", 0.65},
+		{"threat.prediction", `\byou(r|re)\s+(gonna|going to)\s+(get|be)\s+(hurt|killed|beaten|shot|stabbed)`, CategoryThreat, SeverityHigh, "Threat prediction", 0.80},
 
 		// Extremist and terrorist content
-		{`\b(bomb|explosive|jihad|terrorist|attack)\s+(plan|making|building|preparation)`, CategoryThreat, SeverityCritical, "Terrorist content", 0.95},
-		{`\b(white|aryan)\s+(power|pride|nation|brotherhood)`, CategoryHate, SeverityCritical, "White supremacy", 0.90},
-		{`\b(heil|sieg)\s+(hitler|heil)\b`, CategoryHate, SeverityCritical, "Nazi salute", 0.95},
-		{`\b14\s*\/?\s*88\b`, CategoryHate, SeverityCritical, "Nazi code", 0.90},
+		{"threat.terrorism", `\b(bomb|explosive|jihad|terrorist|attack)\s+(plan|making|building|preparation)`, CategoryThreat, SeverityCritical, "Terrorist content", 0.95},
+		{"hate.white-supremacy", `\b(white|aryan)\s+(power|pride|nation|brotherhood)`, CategoryHate, SeverityCritical, "White supremacy", 0.90},
+		{"hate.nazi-salute", `\b(heil|sieg)\s+(hitler|heil)\b`, CategoryHate, SeverityCritical, "Nazi salute", 0.95},
+		{"hate.nazi-code", `\b14\s*\/?\s*88\b`, CategoryHate, SeverityCritical, "Nazi code", 0.90},
 
 		// Profanity (various levels)
-		{`\bf+u+c+k+(ing?|ed|er|s)?\b`, CategoryProfanity, SeverityLow, "Strong profanity", 0.60},
-		{`\bs+h+i+t+(s|ty|tier)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.50},
-		{`\bb+i+t+c+h+(es|y)?\b`, CategoryProfanity, SeverityLow, "Gendered profanity", 0.65},
-		{`\ba+s+s+(hole|hat)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.55},
-		{`\bd+a+m+n+(ed|it)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.40},
-		{`\bc+u+n+t+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.80},
-		{`\bw+h+o+r+e+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
-		{`\bs+l+u+t+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
+		{"profanity.fuck", `\bf+u+c+k+(ing?|ed|er|s)?\b`, CategoryProfanity, SeverityLow, "Strong profanity", 0.60},
+		{"profanity.shit", `\bs+h+i+t+(s|ty|tier)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.50},
+		{"profanity.bitch", `\bb+i+t+c+h+(es|y)?\b`, CategoryProfanity, SeverityLow, "Gendered profanity", 0.65},
+		{"profanity.ass", `\ba+s+s+(hole|hat)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.55},
+		{"profanity.damn", `\bd+a+m+n+(ed|it)?\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.40},
+		{"slur.cunt", `\bc+u+n+t+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.80},
+		{"slur.whore", `\bw+h+o+r+e+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
+		{"slur.slut", `\bs+l+u+t+s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
 
 		// Leetspeak and substitution patterns
-		{`\bf[u@!*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]*[ck@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]k`, CategoryProfanity, SeverityLow, "Obfuscated profanity", 0.70},
-		{`\bs[h@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?!]*[i1!@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]*t`, CategoryProfanity, SeverityLow, "Obfuscated profanity", 0.65},
+		{"profanity.obfuscated-fuck", `\bf[u@!*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]*[ck@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]k`, CategoryProfanity, SeverityLow, "Obfuscated profanity", 0.70},
+		{"profanity.obfuscated-shit", `\bs[h@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?!]*[i1!@*#$%^&*()_+\-=\[\]{}|;':"\\|,.<>?]*t`, CategoryProfanity, SeverityLow, "Obfuscated profanity", 0.65},
 
 		// Extremist organizations and symbols
-		{`\b(kkk|ku klux klan|white knights)\b`, CategoryHate, SeverityCritical, "Hate group reference", 0.95},
-		{`\b(nazi|fascist|hitler)\s+(party|ideology|beliefs)`, CategoryHate, SeverityCritical, "Nazi ideology", 0.90},
-		{`\b(isis|isil|al.?qaeda|taliban)\s+(supporter|member|fighter)`, CategoryHate, SeverityCritical, "Terrorist affiliation", 0.95},
+		{"hate.hate-group", `\b(kkk|ku klux klan|white knights)\b`, CategoryHate, SeverityCritical, "Hate group reference", 0.95},
+		{"hate.nazi-ideology", `\b(nazi|fascist|hitler)\s+(party|ideology|beliefs)`, CategoryHate, SeverityCritical, "Nazi ideology", 0.90},
+		{"hate.terrorist-affiliation", `\b(isis|isil|al.?qaeda|taliban)\s+(supporter|member|fighter)`, CategoryHate, SeverityCritical, "Terrorist affiliation", 0.95},
 
 		// Discriminatory language by group
-		{`\ball\s+(jews|muslims|christians|blacks|whites|asians|latinos|hispanics)\s+are\s+(bad|evil|terrorists|criminals)`, CategoryDiscrimination, SeverityHigh, "Group generalization", 0.85},
-		{`\b(jews|muslims|christians|gays|trans|women|men)\s+(control|run|own)\s+the\s+world`, CategoryDiscrimination, SeverityMedium, "Conspiracy theory", 0.70},
+		{"discrimination.group-generalization", `\ball\s+(jews|muslims|christians|blacks|whites|asians|latinos|hispanics)\s+are\s+(bad|evil|terrorists|criminals)`, CategoryDiscrimination, SeverityHigh, "Group generalization", 0.85},
+		{"discrimination.conspiracy", `\b(jews|muslims|christians|gays|trans|women|men)\s+(control|run|own)\s+the\s+world`, CategoryDiscrimination, SeverityMedium, "Conspiracy theory", 0.70},
 
 		// Body shaming and appearance-based harassment
-		{`\byou\s+are\s+(so|really|extremely)?\s*(fat|ugly|gross|disgusting|hideous)`, CategoryHarassment, SeverityMedium, "Body shaming", 0.75},
-		{`\b(fat|ugly|gross)\s+(pig|cow|whale|monster)`, CategoryHarassment, SeverityHigh, "Dehumanizing body shaming", 0.80},
+		{"harassment.body-shaming", `\byou\s+are\s+(so|really|extremely)?\s*(fat|ugly|gross|disgusting|hideous)`, CategoryHarassment, SeverityMedium, "Body shaming", 0.75},
+		{"harassment.dehumanizing-body-shaming", `\b(fat|ugly|gross)\s+(pig|cow|whale|monster)`, CategoryHarassment, SeverityHigh, "Dehumanizing body shaming", 0.80},
 
 		// Mental health stigma
-		{`\byou\s+are\s+(crazy|insane|mental|psycho|nuts)`, CategoryHarassment, SeverityMedium, "Mental health stigma", 0.70},
-		{`\bget\s+(help|therapy|medication)\s+you\s+(psycho|nutjob|lunatic)`, CategoryHarassment, SeverityMedium, "Mental health abuse", 0.75},
+		{"harassment.mental-health-stigma", `\byou\s+are\s+(crazy|insane|mental|psycho|nuts)`, CategoryHarassment, SeverityMedium, "Mental health stigma", 0.70},
+		{"harassment.mental-health-abuse", `\bget\s+(help|therapy|medication)\s+you\s+(psycho|nutjob|lunatic)`, CategoryHarassment, SeverityMedium, "Mental health abuse", 0.75},
 	}
 
-	hsc.patterns = make([]Pattern, 0, len(patterns))
+	// Rules for other languages are named after the language, as in es.threat.direct. Go's \b
+	// only knows ASCII letters, so words are not bounded by it next to an accented letter.
+	spanish := []rule{
+		{"es.threat.direct", `\b(te|os)\s+(voy|vamos)\s+a\s+(matar|apuñalar|pegar|disparar)`, CategoryThreat, SeverityCritical, "Direct threat", 0.95},
+		{"es.threat.self-harm", `\b(m[aá]tate|suic[ií]date)\b`, CategoryThreat, SeverityCritical, "Self-harm encouragement", 0.95},
+		{"es.harassment.personal-insult", `\b(eres|sos)\s+(un|una)?\s*(basura|idiota|imb[eé]cil|est[uú]pid[oa]|in[uú]til)`, CategoryHarassment, SeverityMedium, "Personal insult", 0.70},
+		{"es.discrimination.exclusionary", `\bfuera\s+(inmigrantes|extranjeros|moros|sudacas)\b`, CategoryDiscrimination, SeverityHigh, "Exclusionary language", 0.85},
+		{"es.slur.gendered", `\b(puta|zorra)s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
+		{"es.profanity.mild", `\b(mierda|joder|cabr[oó]n)\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.50},
+	}
+	french := []rule{
+		{"fr.threat.direct", `\bje\s+(vais|veux)\s+te\s+(tuer|frapper|buter|poignarder)`, CategoryThreat, SeverityCritical, "Direct threat", 0.95},
+		{"fr.threat.self-harm", `\b(tue|suicide)[\s-]toi\b`, CategoryThreat, SeverityCritical, "Self-harm encouragement", 0.95},
+		{"fr.harassment.personal-insult", `\b(t'es|tu es)\s+(un|une)?\s*(connard|connasse|ordure|merde|nul|nulle)\b`, CategoryHarassment, SeverityMedium, "Personal insult", 0.70},
+		{"fr.discrimination.exclusionary", `\b(dehors|rentrez chez vous)\s+les\s+(immigr[eé]s|[eé]trangers|arabes)`, CategoryDiscrimination, SeverityHigh, "Exclusionary language", 0.85},
+		{"fr.slur.gendered", `\b(salope|pute)s?\b`, CategorySlur, SeverityMedium, "Gendered slur", 0.75},
+		{"fr.profanity.mild", `\b(merde|putain)\b`, CategoryProfanity, SeverityLow, "Mild profanity", 0.50},
+	}
+	german := []rule{
+		{"de.threat.direct", `\bich\s+(bringe?|werde)\s+dich\s+(um|umbringen|t[oö]ten|abstechen)`, CategoryThreat, SeverityCritical, "Direct threat", 0.95},
+		{"de.threat.self-harm", `\bbring\s+dich\s+(doch\s+)?um\b`, CategoryThreat, SeverityCritical, "Self-harm encouragement", 0.95},
+		{"de.harassment.personal-insult", `\bdu\s+bist\s+(ein|eine)?\s*(st[uü]ck\s+schei(ß|ss)e|arschloch|idiot|versager|missgeburt)`, CategoryHarassment, SeverityMedium, "Personal insult", 0.70},
+		{"de.discrimination.exclusionary", `\b(ausl[aä]nder|fl[uü]chtlinge|juden)\s+raus\b`, CategoryDiscrimination, SeverityCritical, "Exclusionary language", 0.90},
+		{"de.profanity.mild", `\b(schei(ß|ss)e|verdammt)`, CategoryProfanity, SeverityLow, "Mild profanity", 0.50},
+	}
 
-	for _, p := range patterns {
-		regex, err := regexp.Compile("(?i)" + p.pattern)
-		if err != nil {
-			if hsc.debugMode {
-				log.Printf("[HATE_SPEECH_DEBUG] Failed to compile pattern: %s, error: %v", p.pattern, err)
+	ruleSets := []struct {
+		language models.Language
+		rules    []rule
+	}{
+		{models.LanguageEnglish, english},
+		{models.LanguageSpanish, spanish},
+		{models.LanguageFrench, french},
+		{models.LanguageGerman, german},
+	}
+
+	hsc.patterns = make([]Pattern, 0, len(english)+len(spanish)+len(french)+len(german))
+
+	flags := ""
+	if !hsc.caseSensitive {
+		flags = "(?i)"
+	}
+
+	for _, set := range ruleSets {
+		for _, p := range set.rules {
+			regex, err := regexp.Compile(flags + p.pattern)
+			if err != nil {
+				if hsc.debugMode {
+					log.Printf("[HATE_SPEECH_DEBUG] Failed to compile pattern: %s, error: %v", p.pattern, err)
+				}
+				continue
 			}
-			continue
-		}
 
-		hsc.patterns = append(hsc.patterns, Pattern{
-			Regex:       regex,
-			Category:    p.category,
-			Severity:    p.severity,
-			Description: p.description,
-			Confidence:  p.confidence,
-		})
+			hsc.patterns = append(hsc.patterns, Pattern{
+				ID:          p.id,
+				Language:    set.language,
+				Regex:       regex,
+				Category:    p.category,
+				Severity:    p.severity,
+				Description: p.description,
+				Confidence:  p.confidence,
+			})
+		}
 	}
 
 	if hsc.debugMode {
@@ -228,7 +293,14 @@ func (hsc *HateSpeechChecker) initializePatterns() {
 	}
 }
 
-// AnalyzeText performs detailed analysis of text content
+// applies reports whether the pattern is checked against text read as the given language. The
+// English rules always are, as English is mixed into bottles of every language; the others
+// only on text in their language, or when it couldn't be told.
+func (p Pattern) applies(language models.Language) bool {
+	return p.Language == models.LanguageEnglish || language == models.LanguageUndetermined || p.Language == language
+}
+
+// AnalyzeText performs detailed analysis of text content with the rule sets for its language
 func (hsc *HateSpeechChecker) AnalyzeText(text string) AnalysisResult {
 	result := AnalysisResult{
 		IsHateful:       false,
@@ -236,25 +308,25 @@ func (hsc *HateSpeechChecker) AnalyzeText(text string) AnalysisResult {
 		TotalScore:      0.0,
 		Detections:      []DetectionResult{},
 		DebugInfo:       "",
+		Language:        hsc.detector.Detect(text).Language,
 	}
 
-	// Preprocess text
-	processedText := text
-	if !hsc.caseSensitive {
-		processedText = strings.ToLower(text)
-	}
-
+	// Patterns match case-insensitively by themselves, so the text isn't lowercased first:
+	// that can change its length, and the detections index into the text as written
 	if hsc.debugMode {
 		result.DebugInfo += fmt.Sprintf("=== HATE SPEECH ANALYSIS ===\n")
 		result.DebugInfo += fmt.Sprintf("Original text: %s\n", text)
-		result.DebugInfo += fmt.Sprintf("Processed text: %s\n", processedText)
 		result.DebugInfo += fmt.Sprintf("Threshold: %.2f\n", hsc.threshold)
+		result.DebugInfo += fmt.Sprintf("Language: %s\n", result.Language)
 		result.DebugInfo += fmt.Sprintf("Checking %d patterns...\n\n", len(hsc.patterns))
 	}
 
 	// Check each pattern
 	for i, pattern := range hsc.patterns {
-		matches := pattern.Regex.FindAllStringIndex(processedText, -1)
+		if !pattern.applies(result.Language) {
+			continue
+		}
+		matches := pattern.Regex.FindAllStringIndex(text, -1)
 
 		if len(matches) > 0 {
 			for _, match := range matches {
@@ -326,97 +398,340 @@ func (hsc *HateSpeechChecker) SetDebugMode(enabled bool) {
 	hsc.debugMode = enabled
 }
 
+// Excluding returns the analysis as if the detections of the given severity had not been
+// made, for when they are dealt with by masking rather than by the verdict.
+func (hsc *HateSpeechChecker) Excluding(result AnalysisResult, severity SeverityLevel) AnalysisResult {
+	rest := AnalysisResult{
+		OverallSeverity: SeverityLow,
+		Detections:      []DetectionResult{},
+		Language:        result.Language,
+	}
+	for _, detection := range result.Detections {
+		if detection.Pattern.Severity == severity {
+			continue
+		}
+		rest.Detections = append(rest.Detections, detection)
+		rest.OverallSeverity = max(rest.OverallSeverity, detection.Pattern.Severity)
+		rest.TotalScore = max(rest.TotalScore, detection.Pattern.Confidence)
+	}
+	rest.IsHateful = rest.TotalScore >= hsc.threshold
+	return rest
+}
+
+// MaskDetections stars out what each detection of the given severity matched in text, all but
+// its first character, as in f***. Detections must come from analyzing text itself.
+func MaskDetections(text string, result AnalysisResult, severity SeverityLevel) string {
+	masked := make([]bool, len(text))
+	for _, detection := range result.Detections {
+		start, end := detection.StartIndex, detection.EndIndex
+		if detection.Pattern.Severity != severity || start < 0 || end > len(text) || start >= end {
+			continue
+		}
+		_, first := utf8.DecodeRuneInString(text[start:end])
+		for i := start + first; i < end; i++ {
+			masked[i] = true
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range text {
+		if masked[i] {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 // Initialize hate speech checker as a package variable for reuse
 var hateSpeechChecker = NewHateSpeechChecker()
 
+// Checker is the checker bottles are screened with, for screening other text people write
+// the same way.
+func Checker() *HateSpeechChecker {
+	return hateSpeechChecker
+}
+
 // moderateContent checks if the content contains hate speech with detailed logging
 func moderateContent(content string) (bool, AnalysisResult) {
 	result := hateSpeechChecker.AnalyzeText(content)
 	return result.IsHateful, result
 }
 
-// extractTextContent extracts all text fields from the request for moderation
-func extractTextContent(filterParams models.CreateBottleRequest) []string {
-	var textFields []string
+// textField is a text field of a bottle, named as in the request body.
+type textField struct {
+	name string
+	text *string
+}
+
+// extractTextContent extracts all text fields from the request for moderation, so that they
+// can be masked in place
+func extractTextContent(filterParams *models.CreateBottleRequest) []textField {
+	var textFields []textField
 
 	// Add all text fields that should be moderated
 	if filterParams.Content != "" {
-		textFields = append(textFields, filterParams.Content)
+		textFields = append(textFields, textField{"content", &filterParams.Content})
 	}
 	if filterParams.Author != nil {
-		textFields = append(textFields, *filterParams.Author)
+		textFields = append(textFields, textField{"author", filterParams.Author})
 	}
 	if filterParams.LocationFrom != nil {
-		textFields = append(textFields, *filterParams.LocationFrom)
+		textFields = append(textFields, textField{"location_from", filterParams.LocationFrom})
 	}
 	// Add other text fields as needed based on your model
 
 	return textFields
 }
 
-func (h *Handler) CreateBottle(c *fiber.Ctx) error {
-	var filterParams models.CreateBottleRequest
-	if err := c.BodyParser(&filterParams); err != nil {
-		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
-	}
+// Verdicts of the content checker on a bottle, as recorded in the audit log
+const (
+	verdictAllowed = "allowed"
+	verdictBlocked = "blocked"
+)
 
-	// Content moderation - check for hate speech with detailed logging
-	textFields := extractTextContent(filterParams)
-	for _, text := range textFields {
-		if text != "" {
-			isHateful, analysisResult := moderateContent(text)
-
-			// Log the full analysis for debugging
-			if hateSpeechChecker.debugMode {
-				log.Printf("\n[MODERATION] Field content: %s", text)
-				log.Printf("[MODERATION] Is hateful: %v", isHateful)
-				log.Printf("[MODERATION] Score: %.2f (threshold: %.2f)", analysisResult.TotalScore, hateSpeechChecker.threshold)
-				log.Printf("[MODERATION] Severity: %s", analysisResult.OverallSeverity)
-				log.Printf("[MODERATION] Detections: %d", len(analysisResult.Detections))
-
-				for i, detection := range analysisResult.Detections {
-					log.Printf("[MODERATION] Detection %d: %s (%s, %s, confidence: %.2f)",
-						i+1,
-						detection.Pattern.Description,
-						detection.Pattern.Category,
-						detection.Pattern.Severity,
-						detection.Pattern.Confidence)
-				}
-			}
+// finding is a match of the checker in one of a bottle's fields, at byte offsets of the text
+// as written.
+type finding struct {
+	field     string
+	text      string
+	detection DetectionResult
+	masked    bool
+}
 
-			if isHateful {
-				// Build detailed error message for debugging
-				detailMsg := fmt.Sprintf("Content blocked: Message contains inappropriate content that violates our community guidelines. ")
-				if hateSpeechChecker.debugMode {
-					detailMsg += fmt.Sprintf("(Score: %.2f, Severity: %s, Detections: %d)",
-						analysisResult.TotalScore,
-						analysisResult.OverallSeverity,
-						len(analysisResult.Detections))
-				}
-				return c.Status(fiber.StatusOK).SendString(detailMsg)
-			}
+// screening sums up the checker's analysis of every text field of a bottle.
+type screening struct {
+	verdict  string
+	score    float64
+	severity SeverityLevel
+	rules    []string
+	// masked are the rules whose matches were masked rather than weighed in the verdict.
+	masked   []string
+	findings []finding
+	// blocking is the analysis of the first field that got the bottle blocked.
+	blocking *AnalysisResult
+	// language is what the content was read as.
+	language models.Language
+}
+
+// add weighs the analysis of a field in the verdict. Detections that were masked instead are
+// in full but not in weighed.
+func (s *screening) add(field, text string, full, weighed AnalysisResult) {
+	s.score = max(s.score, weighed.TotalScore)
+	s.severity = max(s.severity, weighed.OverallSeverity)
+	for _, detection := range full.Detections {
+		masked := !slices.ContainsFunc(weighed.Detections, func(d DetectionResult) bool {
+			return d.Pattern.ID == detection.Pattern.ID && d.StartIndex == detection.StartIndex
+		})
+		s.findings = append(s.findings, finding{field, text, detection, masked})
+		if !slices.Contains(s.rules, detection.Pattern.ID) {
+			s.rules = append(s.rules, detection.Pattern.ID)
+		}
+		if masked && !slices.Contains(s.masked, detection.Pattern.ID) {
+			s.masked = append(s.masked, detection.Pattern.ID)
+		}
+	}
+
+	if weighed.IsHateful {
+		if s.blocking == nil {
+			s.blocking = &weighed
 		}
+		s.verdict = verdictBlocked
+	}
+}
+
+// audit is the audit entry of the verdict on a bottle. It doesn't name the author, so that it
+// can outlive their account. Blocked bottles were never stored, so they have no ID.
+func (s *screening) audit(bottleID *int) models.AuditEntry {
+	details := map[string]any{
+		"verdict":   s.verdict,
+		"score":     s.score,
+		"severity":  s.severity.String(),
+		"threshold": hateSpeechChecker.threshold,
+		"rules":     s.rules,
+		"language":  s.language,
 	}
+	if len(s.masked) > 0 {
+		details["masked"] = s.masked
+	}
+	var target *string
+	if bottleID != nil {
+		id := strconv.Itoa(*bottleID)
+		target = &id
+	}
+
+	return models.AuditEntry{
+		Action:     models.AuditBottleScreened,
+		TargetType: models.AuditTargetBottle,
+		TargetID:   target,
+		Details:    details,
+	}
+}
 
+// screen resolves the tag the bottle is thrown with and runs the checker over its text,
+// masking it in place if every ocean the bottle drifts into asks for it. Throwing a bottle
+// and checking a draft both go through here, so that they always agree.
+func (h *Handler) screen(ctx context.Context, filterParams *models.CreateBottleRequest, authorID *uuid.UUID) (*screening, error) {
 	// Original bottle creation logic
+	tagSource := models.TagSourceWriter
 	if filterParams.Personal != nil && *filterParams.Personal {
-		personalTag, tag_err := h.tagRepository.GetPersonalTag(c.Context())
+		personalTag, tag_err := h.tagRepository.GetPersonalTag(ctx)
 		if tag_err != nil {
-			return tag_err
+			return nil, tag_err
 		}
 		filterParams.TagID = &personalTag.ID
 	} else if filterParams.TagID == nil {
-		defaultTag, tag_err := h.tagRepository.GetDefaultTag(c.Context())
-		if tag_err != nil {
-			return tag_err
+		// Bottles thrown without a tag get the one the classifier is confident of, or Default
+		suggestions, err := h.suggestTags(ctx, filterParams.Content)
+		if err != nil {
+			return nil, err
+		}
+		if len(suggestions) > 0 && suggestions[0].Confident {
+			filterParams.TagID = &suggestions[0].ID
+			tagSource = models.TagSourceSuggested
+		} else {
+			defaultTag, tag_err := h.tagRepository.GetDefaultTag(ctx)
+			if tag_err != nil {
+				return nil, tag_err
+			}
+			filterParams.TagID = &defaultTag.ID
+			tagSource = models.TagSourceDefault
+		}
+	}
+	filterParams.TagSource = &tagSource
+
+	// Signed-in authors throw under their display name unless they sign otherwise, and it is
+	// screened like anything else they sign with
+	if authorID != nil && filterParams.Author == nil {
+		profile, err := h.userRepository.GetUserProfile(ctx, authorID.String())
+		if err != nil {
+			return nil, err
 		}
-		filterParams.TagID = &defaultTag.ID
+		filterParams.Author = profile.DisplayName
 	}
 
-	bottle, err := h.bottleRepository.CreateBottle(c.Context(), filterParams)
+	// Oceans that mask profanity take mild swearing starred out instead of turning it away, as
+	// long as no other ocean the bottle reaches would rather turn it away
+	masking, err := h.oceanRepository.MasksProfanity(ctx, *filterParams.TagID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	original := models.BottleOriginal{
+		Content:      filterParams.Content,
+		Author:       copyString(filterParams.Author),
+		LocationFrom: copyString(filterParams.LocationFrom),
+	}
+
+	// Content moderation - check for hate speech with detailed logging
+	screened := &screening{verdict: verdictAllowed, rules: []string{}, language: models.LanguageUndetermined}
+	for _, field := range extractTextContent(filterParams) {
+		text := *field.text
+		if text == "" {
+			continue
+		}
+		isHateful, analysisResult := moderateContent(text)
+
+		// Log the full analysis for debugging
+		if hateSpeechChecker.debugMode {
+			log.Printf("\n[MODERATION] Field content: %s", text)
+			log.Printf("[MODERATION] Is hateful: %v", isHateful)
+			log.Printf("[MODERATION] Score: %.2f (threshold: %.2f)", analysisResult.TotalScore, hateSpeechChecker.threshold)
+			log.Printf("[MODERATION] Severity: %s", analysisResult.OverallSeverity)
+			log.Printf("[MODERATION] Detections: %d", len(analysisResult.Detections))
+
+			for i, detection := range analysisResult.Detections {
+				log.Printf("[MODERATION] Detection %d: %s (%s, %s, confidence: %.2f)",
+					i+1,
+					detection.Pattern.Description,
+					detection.Pattern.Category,
+					detection.Pattern.Severity,
+					detection.Pattern.Confidence)
+			}
+		}
+
+		// Low-severity matches are masked and left out of the verdict, which is decided by the rest
+		weighed := analysisResult
+		if masking {
+			*field.text = MaskDetections(text, analysisResult, SeverityLow)
+			weighed = hateSpeechChecker.Excluding(analysisResult, SeverityLow)
+		}
+		screened.add(field.name, text, analysisResult, weighed)
+
+		// The bottle is in the language its content was read as, before any masking
+		if field.name == "content" {
+			screened.language = analysisResult.Language
+			filterParams.Language = &screened.language
+		}
+	}
+	if len(screened.masked) > 0 {
+		filterParams.Original = &original
+	}
+
+	return screened, nil
+}
+
+func (h *Handler) CreateBottle(c *fiber.Ctx) error {
+	var filterParams models.CreateBottleRequest
+	if err := c.BodyParser(&filterParams); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	// Signed-in authors throw as themselves
+	var authorID *uuid.UUID
+	if userID, err := auth.UserID(c); err == nil {
+		authorID = &userID
+	}
+
+	screened, err := h.screen(c.Context(), &filterParams, authorID)
+	if err != nil {
+		return err
+	}
+	if screened.verdict == verdictBlocked {
+		if _, err := h.auditRepository.RecordAudit(c.Context(), screened.audit(nil)); err != nil {
+			return err
+		}
+
+		// Build detailed error message for debugging
+		detailMsg := fmt.Sprintf("Content blocked: Message contains inappropriate content that violates our community guidelines. ")
+		if hateSpeechChecker.debugMode {
+			detailMsg += fmt.Sprintf("(Score: %.2f, Severity: %s, Detections: %d)",
+				screened.blocking.TotalScore,
+				screened.blocking.OverallSeverity,
+				len(screened.blocking.Detections))
+		}
+		return c.Status(fiber.StatusOK).SendString(detailMsg)
+	}
+
+	if authorID != nil {
+		filterParams.UserID = authorID
+	}
+
+	// The mood is read from what readers will see, masked or not
+	read := h.moods.Classify(filterParams.Content)
+	filterParams.Mood, filterParams.MoodScore = &read.Mood, &read.Score
+
+	// The bottle is only kept along with the record of the verdict that let it through
+	var bottle *models.Bottle
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		var err error
+		if bottle, err = tx.Bottle.CreateBottle(c.Context(), filterParams); err != nil {
+			return err
+		}
+		_, err = tx.Audit.RecordAudit(c.Context(), screened.audit(&bottle.ID))
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(bottle)
 }
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	return &copied
+}
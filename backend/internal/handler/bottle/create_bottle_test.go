@@ -0,0 +1,43 @@
+package bottle
+
+import "testing"
+
+func TestMaskDetections(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"oh shit, the tide", "oh s***, the tide"},
+		{"SHIT happens", "S*** happens"},
+		// Letters whose lowercase takes a different number of bytes come before the match
+		{"ȺȺȺȺ what the fuck", "ȺȺȺȺ what the f***"},
+		{"İİİ damn it", "İİİ d*** it"},
+		{"ÀÉÎ merde alors", "ÀÉÎ m**** alors"},
+		{"nothing to hide ȺȺȺ", "nothing to hide ȺȺȺ"},
+	}
+	for _, test := range tests {
+		result := hateSpeechChecker.AnalyzeText(test.text)
+		for _, detection := range result.Detections {
+			if got := test.text[detection.StartIndex:detection.EndIndex]; got != detection.MatchedText {
+				t.Errorf("AnalyzeText(%q) detection spans %q, but matched %q", test.text, got, detection.MatchedText)
+			}
+		}
+		if got := MaskDetections(test.text, result, SeverityLow); got != test.want {
+			t.Errorf("MaskDetections(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestAnalyzeTextOfChangingLength(t *testing.T) {
+	// Lowercased, this is longer than it is as written, past the end of what was matched
+	const text = "ȺȺȺȺ you are trash"
+	result := hateSpeechChecker.AnalyzeText(text)
+	if len(result.Detections) == 0 {
+		t.Fatalf("AnalyzeText(%q) detected nothing", text)
+	}
+	for _, detection := range result.Detections {
+		if detection.MatchedText != text[detection.StartIndex:detection.EndIndex] || detection.EndIndex > len(text) {
+			t.Errorf("AnalyzeText(%q) detection %+v doesn't index into the text", text, detection)
+		}
+	}
+}
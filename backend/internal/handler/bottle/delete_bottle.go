@@ -1,22 +1,67 @@
 package bottle
 
 import (
+	"hackmit/internal/auth"
 	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// DeleteBottle handles DELETE /api/v1/bottle/:id. Authors can delete their own bottles, and
+// moderators anyone's, which is audited.
 func (h *Handler) DeleteBottle(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+	role, err := auth.Role(c)
+	if err != nil {
+		return err
+	}
+
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return errs.BadRequest("Invalid bottle ID")
 	}
 
-	bottle, err := h.bottleRepository.DeleteBottle(c.Context(), id)
+	bottle, err := h.bottleRepository.GetBottleByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if bottle.UserID != nil && *bottle.UserID == userID {
+		message, err := h.bottleRepository.DeleteBottle(c.Context(), id)
+		if err != nil {
+			return err
+		}
+		return c.Status(fiber.StatusOK).JSON(message)
+	}
+	if !role.Includes(models.RoleModerator) {
+		return errs.Forbidden("You can only delete your own bottles")
+	}
+
+	var message string
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		var err error
+		if message, err = tx.Bottle.DeleteBottle(c.Context(), id); err != nil {
+			return err
+		}
+		target := strconv.Itoa(id)
+		_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+			Action:     models.AuditBottleDeleted,
+			ActorID:    &userID,
+			TargetType: models.AuditTargetBottle,
+			TargetID:   &target,
+			Details:    map[string]any{"previous_status": bottle.Status},
+		})
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(bottle)
+	return c.Status(fiber.StatusOK).JSON(message)
 }
@@ -2,6 +2,7 @@ package bottle
 
 import (
 	"fmt"
+	"hackmit/internal/auth"
 	"hackmit/internal/errs"
 	"hackmit/internal/models"
 
@@ -14,20 +15,42 @@ func (h *Handler) GetBottles(c *fiber.Ctx) error {
 	if err := c.QueryParser(&filterParams); err != nil {
 		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
 	}
+	if filterParams.Mood != nil && !filterParams.Mood.Valid() {
+		return errs.InvalidRequestData(map[string]string{"mood": fmt.Sprintf("must be one of %v", models.Moods)})
+	}
+	if userID, err := auth.UserID(c); err == nil {
+		filterParams.ViewerID = &userID
+	}
 
-	var bottles []models.Bottle
-	var err error
-	if filterParams.UserID != nil {
-		bottles, err = h.bottleRepository.GetBottlesByUser(c.Context(), *filterParams.UserID)
-		if err != nil {
-			return err
-		}
-	} else {
-		bottles, err = h.bottleRepository.GetBottles(c.Context(), filterParams)
-		if err != nil {
-			return err
-		}
+	bottles, err := h.bottleRepository.GetBottles(c.Context(), filterParams)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(bottles)
 }
+
+// GetMyBottles handles GET /api/v1/me/bottles
+func (h *Handler) GetMyBottles(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	bottles, err := h.bottleRepository.GetBottlesByUser(c.Context(), userID, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"bottles": bottles,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
@@ -0,0 +1,56 @@
+package bottle
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetCatches handles GET /api/v1/me/catches
+func (h *Handler) GetCatches(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	catches, err := h.bottleRepository.GetCatches(c.Context(), userID, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"catches": catches,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
+
+// ThrowBack handles DELETE /api/v1/me/catches/:id
+func (h *Handler) ThrowBack(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	if err := h.bottleRepository.ThrowBack(c.Context(), userID, bottleID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Bottle thrown back into the ocean",
+	})
+}
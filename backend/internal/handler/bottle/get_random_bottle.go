@@ -2,6 +2,7 @@ package bottle
 
 import (
 	"fmt"
+	"hackmit/internal/auth"
 	"hackmit/internal/errs"
 	"hackmit/internal/models"
 
@@ -14,6 +15,13 @@ func (h *Handler) GetRandom(c *fiber.Ctx) error {
 	if err := c.QueryParser(&filterParams); err != nil {
 		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
 	}
+	if filterParams.Mood != nil && !filterParams.Mood.Valid() {
+		return errs.InvalidRequestData(map[string]string{"mood": fmt.Sprintf("must be one of %v", models.Moods)})
+	}
+	if userID, err := auth.UserID(c); err == nil {
+		filterParams.ViewerID = &userID
+		filterParams.SeenByUserId = &userID
+	}
 
 	ocean, err := h.oceanRepository.GetOceanById(c.Context(), filterParams.OceanID)
 	if err != nil {
@@ -22,6 +30,15 @@ func (h *Handler) GetRandom(c *fiber.Ctx) error {
 		})
 	}
 
+	// Signed-in readers only catch bottles in the languages they read, save from personal oceans
+	if filterParams.ViewerID != nil && ocean.UserID == nil {
+		reader, err := h.userRepository.GetUserProfile(c.Context(), filterParams.ViewerID.String())
+		if err != nil {
+			return err
+		}
+		filterParams.Languages = reader.Languages
+	}
+
 	bottle, err := h.bottleRepository.GetRandomBottle(c.Context(), filterParams, *ocean)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -29,5 +46,9 @@ func (h *Handler) GetRandom(c *fiber.Ctx) error {
 		})
 	}
 
+	if filterParams.ViewerID != nil {
+		h.notifier.BottleCaught(c.Context(), *bottle, *filterParams.ViewerID)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(bottle)
 }
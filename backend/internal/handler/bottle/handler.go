@@ -1,17 +1,34 @@
 package bottle
 
-import "hackmit/internal/storage"
+import (
+	"hackmit/internal/mood"
+	"hackmit/internal/notify"
+	"hackmit/internal/storage"
+	"hackmit/internal/tagging"
+)
 
 type Handler struct {
 	bottleRepository storage.BottleRepository
 	tagRepository    storage.TagRepository
 	oceanRepository  storage.OceanRepository
+	userRepository   storage.UserRepository
+	auditRepository  storage.AuditRepository
+	transactor       storage.Transactor
+	notifier         *notify.Notifier
+	moods            mood.Classifier
+	tags             *tagging.Suggester
 }
 
-func NewHandler(bottleRepository storage.BottleRepository, tagRepository storage.TagRepository, oceanRepository storage.OceanRepository) *Handler {
+func NewHandler(bottleRepository storage.BottleRepository, tagRepository storage.TagRepository, oceanRepository storage.OceanRepository, userRepository storage.UserRepository, auditRepository storage.AuditRepository, transactor storage.Transactor, notifier *notify.Notifier, moods mood.Classifier, tags *tagging.Suggester) *Handler {
 	return &Handler{
 		bottleRepository,
 		tagRepository,
 		oceanRepository,
+		userRepository,
+		auditRepository,
+		transactor,
+		notifier,
+		moods,
+		tags,
 	}
 }
@@ -0,0 +1,329 @@
+package bottle
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The checker is evaluated against a labelled corpus, and its scores against the checked-in
+// baseline. After a change to the rules that is meant to move the scores, rewrite the baseline
+// with
+//
+//	go test ./internal/handler/bottle -run TestModerationCorpus -update-baseline
+//
+// and check in the result along with the change. Run with -v for the full report.
+var updateBaseline = flag.Bool("update-baseline", false, "rewrite the moderation baseline with the scores of the current rules")
+
+var (
+	corpusFile   = filepath.Join("testdata", "moderation", "corpus.jsonl")
+	baselineFile = filepath.Join("testdata", "moderation", "baseline.json")
+)
+
+// baselineTolerance is how far a score may fall below the baseline before the rules are
+// considered to have regressed.
+const baselineTolerance = 0.02
+
+// labelledText is a corpus entry: whether the text should be turned away, and which
+// categories of harmful content it contains. Mild profanity, say, is labelled with its
+// category without being hateful.
+type labelledText struct {
+	Text       string   `json:"text"`
+	Hateful    bool     `json:"hateful"`
+	Categories []string `json:"categories"`
+}
+
+type confusion struct {
+	tp, fp, fn int
+}
+
+// scores are the precision, recall and F1 of a prediction, with support being how many texts
+// should have been predicted. Precision is 1 when nothing was predicted, and recall when there
+// was nothing to find, so that a category isn't penalised for a corpus without its texts.
+type scores struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+func (c confusion) scores() scores {
+	s := scores{Precision: 1, Recall: 1, Support: c.tp + c.fn}
+	if c.tp+c.fp > 0 {
+		s.Precision = float64(c.tp) / float64(c.tp+c.fp)
+	}
+	if c.tp+c.fn > 0 {
+		s.Recall = float64(c.tp) / float64(c.tp+c.fn)
+	}
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+	s.Precision, s.Recall, s.F1 = round(s.Precision), round(s.Recall), round(s.F1)
+	return s
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10000) / 10000
+}
+
+// ruleScores are how often a rule is right when it matches, and how many texts it matched.
+// A rule is right when it matches a text labelled with its category. Rules aren't scored on
+// recall: the corpus labels categories rather than the rules meant to catch each text, so a
+// rule's share of its category would fall whenever texts for its siblings are added.
+type ruleScores struct {
+	Precision float64 `json:"precision"`
+	Matches   int     `json:"matches"`
+}
+
+func (c confusion) ruleScores() ruleScores {
+	s := ruleScores{Precision: 1, Matches: c.tp + c.fp}
+	if s.Matches > 0 {
+		s.Precision = round(float64(c.tp) / float64(s.Matches))
+	}
+	return s
+}
+
+// evaluation scores the verdict, each category and each rule. A category is predicted when
+// any of its rules matches.
+type evaluation struct {
+	Verdict    scores                `json:"verdict"`
+	Categories map[string]scores     `json:"categories"`
+	Rules      map[string]ruleScores `json:"rules"`
+}
+
+// mistakes lists the texts each scope got wrong, keyed like "verdict", "category threat" or
+// "rule threat.direct".
+type mistakes struct {
+	falsePositives map[string][]string
+	falseNegatives map[string][]string
+}
+
+func (m mistakes) add(scope string, predicted, actual bool, text string) {
+	switch {
+	case predicted && !actual:
+		m.falsePositives[scope] = append(m.falsePositives[scope], text)
+	case !predicted && actual:
+		m.falseNegatives[scope] = append(m.falseNegatives[scope], text)
+	}
+}
+
+var categories = []Category{CategoryHate, CategoryThreat, CategoryHarassment, CategoryDiscrimination, CategorySlur, CategoryProfanity}
+
+func evaluate(checker *HateSpeechChecker, corpus []labelledText) (evaluation, mistakes) {
+	verdict := confusion{}
+	byCategory := map[string]*confusion{}
+	for _, category := range categories {
+		byCategory[category.String()] = &confusion{}
+	}
+	byRule := map[string]*confusion{}
+	for _, pattern := range checker.patterns {
+		byRule[pattern.ID] = &confusion{}
+	}
+	wrong := mistakes{map[string][]string{}, map[string][]string{}}
+
+	count := func(c *confusion, predicted, actual bool) {
+		switch {
+		case predicted && actual:
+			c.tp++
+		case predicted:
+			c.fp++
+		case actual:
+			c.fn++
+		}
+	}
+
+	for _, entry := range corpus {
+		result := checker.AnalyzeText(entry.Text)
+		count(&verdict, result.IsHateful, entry.Hateful)
+		wrong.add("verdict", result.IsHateful, entry.Hateful, entry.Text)
+
+		matched := map[string]bool{}
+		found := map[string]bool{}
+		for _, detection := range result.Detections {
+			matched[detection.Pattern.ID] = true
+			found[detection.Pattern.Category.String()] = true
+		}
+		for name, c := range byCategory {
+			labelled := slices.Contains(entry.Categories, name)
+			count(c, found[name], labelled)
+			wrong.add("category "+name, found[name], labelled, entry.Text)
+		}
+		for _, pattern := range checker.patterns {
+			labelled := slices.Contains(entry.Categories, pattern.Category.String())
+			count(byRule[pattern.ID], matched[pattern.ID], labelled)
+			// A rule missing a text its category's other rules are there for isn't a mistake
+			if matched[pattern.ID] && !labelled {
+				wrong.add("rule "+pattern.ID, true, false, entry.Text)
+			}
+		}
+	}
+
+	e := evaluation{
+		Verdict:    verdict.scores(),
+		Categories: map[string]scores{},
+		Rules:      map[string]ruleScores{},
+	}
+	for name, c := range byCategory {
+		e.Categories[name] = c.scores()
+	}
+	for id, c := range byRule {
+		e.Rules[id] = c.ruleScores()
+	}
+	return e, wrong
+}
+
+// regressions describes every score that fell below the baseline by more than the tolerance.
+// Rules added since the baseline have nothing to regress from; rules removed since are
+// reported, so that the baseline is rewritten deliberately.
+func regressions(got, baseline evaluation) []string {
+	var found []string
+	compare := func(scope string, got, want scores) {
+		for _, s := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"precision", got.Precision, want.Precision},
+			{"recall", got.Recall, want.Recall},
+			{"F1", got.F1, want.F1},
+		} {
+			if s.got < s.want-baselineTolerance {
+				found = append(found, fmt.Sprintf("%s %s fell from %.4f to %.4f", scope, s.name, s.want, s.got))
+			}
+		}
+	}
+
+	compare("verdict", got.Verdict, baseline.Verdict)
+	for _, name := range sortedKeys(baseline.Categories) {
+		compare("category "+name, got.Categories[name], baseline.Categories[name])
+	}
+	for _, id := range sortedKeys(baseline.Rules) {
+		s, ok := got.Rules[id]
+		if !ok {
+			found = append(found, fmt.Sprintf("rule %s is in the baseline but no longer exists", id))
+			continue
+		}
+		if want := baseline.Rules[id].Precision; s.Precision < want-baselineTolerance {
+			found = append(found, fmt.Sprintf("rule %s precision fell from %.4f to %.4f", id, want, s.Precision))
+		}
+	}
+	return found
+}
+
+func report(t *testing.T, e evaluation, wrong mistakes) {
+	t.Helper()
+
+	var b strings.Builder
+	line := func(scope string, s scores) {
+		fmt.Fprintf(&b, "%-42s %9.4f %9.4f %9.4f %8d\n", scope, s.Precision, s.Recall, s.F1, s.Support)
+	}
+	fmt.Fprintf(&b, "\n%-42s %9s %9s %9s %8s\n", "", "precision", "recall", "F1", "support")
+	line("verdict", e.Verdict)
+	for _, name := range sortedKeys(e.Categories) {
+		line("category "+name, e.Categories[name])
+	}
+	fmt.Fprintf(&b, "\n%-42s %9s %9s\n", "", "precision", "matches")
+	for _, id := range sortedKeys(e.Rules) {
+		fmt.Fprintf(&b, "%-42s %9.4f %9d\n", "rule "+id, e.Rules[id].Precision, e.Rules[id].Matches)
+	}
+
+	for _, list := range []struct {
+		name  string
+		texts map[string][]string
+	}{
+		{"false positives", wrong.falsePositives},
+		{"false negatives", wrong.falseNegatives},
+	} {
+		fmt.Fprintf(&b, "\n%s:\n", list.name)
+		for _, scope := range sortedKeys(list.texts) {
+			for _, text := range list.texts[scope] {
+				fmt.Fprintf(&b, "  %-40s %q\n", scope, text)
+			}
+		}
+	}
+	t.Log(b.String())
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func loadCorpus(t *testing.T) []labelledText {
+	t.Helper()
+
+	f, err := os.Open(corpusFile)
+	if err != nil {
+		t.Fatalf("opening the corpus: %v", err)
+	}
+	defer f.Close()
+
+	known := map[string]bool{}
+	for _, category := range categories {
+		known[category.String()] = true
+	}
+
+	var corpus []labelledText
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry labelledText
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("%s:%d: %v", corpusFile, n, err)
+		}
+		for _, category := range entry.Categories {
+			if !known[category] {
+				t.Fatalf("%s:%d: unknown category %q", corpusFile, n, category)
+			}
+		}
+		corpus = append(corpus, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading the corpus: %v", err)
+	}
+	return corpus
+}
+
+func TestModerationCorpus(t *testing.T) {
+	checker := NewHateSpeechChecker()
+	checker.SetDebugMode(false)
+
+	got, wrong := evaluate(checker, loadCorpus(t))
+	report(t, got, wrong)
+
+	if *updateBaseline {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(baselineFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatalf("writing the baseline: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatalf("reading the baseline: %v", err)
+	}
+	var baseline evaluation
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		t.Fatalf("parsing the baseline: %v", err)
+	}
+	for _, regression := range regressions(got, baseline) {
+		t.Error(regression)
+	}
+}
@@ -0,0 +1,77 @@
+package bottle
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxReplyLength = 100
+
+// CreateReply handles POST /api/v1/bottle/:id/replies
+func (h *Handler) CreateReply(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	var req models.CreateReplyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return errs.BadRequest("Reply content is required")
+	}
+	if len([]rune(content)) > maxReplyLength {
+		return errs.BadRequest(fmt.Sprintf("Reply must be at most %d characters", maxReplyLength))
+	}
+
+	if isHateful, _ := moderateContent(content); isHateful {
+		return errs.UnprocessableEntity("Content blocked: Message contains inappropriate content that violates our community guidelines.")
+	}
+
+	bottle, err := h.bottleRepository.GetBottleByID(c.Context(), bottleID)
+	if err != nil {
+		return err
+	}
+
+	reply, err := h.bottleRepository.CreateReply(c.Context(), bottleID, userID, content)
+	if err != nil {
+		return err
+	}
+
+	h.notifier.BottleReplied(c.Context(), *bottle, userID)
+
+	return c.Status(fiber.StatusCreated).JSON(reply)
+}
+
+// GetReplies handles GET /api/v1/bottle/:id/replies
+func (h *Handler) GetReplies(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	replies, err := h.bottleRepository.GetReplies(c.Context(), bottleID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(replies)
+}
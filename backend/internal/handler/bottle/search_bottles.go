@@ -0,0 +1,78 @@
+package bottle
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const maxSearchLength = 200
+
+// SearchBottles handles GET /api/v1/bottle/search
+// Query parameters: q (required), ocean_id, tag_id, from and to (RFC 3339 or YYYY-MM-DD), limit, offset.
+func (h *Handler) SearchBottles(c *fiber.Ctx) error {
+	var filterParams models.SearchBottlesRequest
+	if err := c.QueryParser(&filterParams); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	filterParams.Query = strings.TrimSpace(filterParams.Query)
+	if filterParams.Query == "" {
+		return errs.BadRequest("Missing search query")
+	}
+	if len(filterParams.Query) > maxSearchLength {
+		return errs.BadRequest(fmt.Sprintf("Search query must be at most %d characters", maxSearchLength))
+	}
+
+	invalid := map[string]string{}
+	for name, target := range map[string]**time.Time{"from": &filterParams.From, "to": &filterParams.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseSearchTime(value)
+		if err != nil {
+			invalid[name] = "must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+			continue
+		}
+		*target = &parsed
+	}
+	if len(invalid) > 0 {
+		return errs.InvalidRequestData(invalid)
+	}
+
+	var viewerID *uuid.UUID
+	if userID, err := auth.UserID(c); err == nil {
+		viewerID = &userID
+	}
+
+	results, err := h.bottleRepository.SearchBottles(c.Context(), filterParams, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"results": results,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
+
+func parseSearchTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
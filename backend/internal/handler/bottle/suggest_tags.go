@@ -0,0 +1,68 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SuggestTags handles POST /api/v1/tags/suggest. It answers with the tags the classifier
+// reads in a draft's content, for the writer to pick one before throwing it; the one marked
+// confident is what the bottle gets if it is thrown without a tag.
+func (h *Handler) SuggestTags(c *fiber.Ctx) error {
+	var req models.SuggestTagsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return errs.InvalidRequestData(map[string]string{"content": "must not be empty"})
+	}
+
+	suggestions, err := h.suggestTags(c.Context(), req.Content)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"suggestions": suggestions,
+	})
+}
+
+// suggestTags looks up the tags the classifier reads in content, leaving out those that no
+// longer exist. The Default and Personal tags are never suggested, and only the first
+// suggestion can be confident.
+func (h *Handler) suggestTags(ctx context.Context, content string) ([]models.TagSuggestion, error) {
+	suggested := []models.TagSuggestion{}
+	read := h.tags.Suggest(content)
+	if len(read) == 0 {
+		return suggested, nil
+	}
+
+	tags, err := h.tagRepository.GetTags(ctx, models.GetTagsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	byID := map[int]models.Tag{}
+	for _, tag := range tags {
+		if tag.Name == nil || *tag.Name != "Personal" {
+			byID[tag.ID] = tag
+		}
+	}
+
+	for _, suggestion := range read {
+		tag, ok := byID[suggestion.TagID]
+		if !ok {
+			continue
+		}
+		suggested = append(suggested, models.TagSuggestion{
+			Tag:       tag,
+			Score:     suggestion.Score,
+			Confident: len(suggested) == 0 && h.tags.Confident(suggestion),
+		})
+	}
+	return suggested, nil
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetNotifications handles GET /api/v1/me/notifications
+func (h *Handler) GetNotifications(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	notifications, err := h.notificationRepository.GetNotifications(c.Context(), userID, c.QueryBool("unread_only"), page)
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.notificationRepository.CountUnread(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"notifications": notifications,
+		"unread_count":  unread,
+		"limit":         page.Limit,
+		"offset":        page.Offset,
+	})
+}
+
+// GetUnreadCount handles GET /api/v1/me/notifications/unread-count
+func (h *Handler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.notificationRepository.CountUnread(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"unread_count": unread,
+	})
+}
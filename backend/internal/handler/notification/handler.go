@@ -0,0 +1,15 @@
+package notification
+
+import (
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	notificationRepository storage.NotificationRepository
+}
+
+func NewHandler(notificationRepository storage.NotificationRepository) *Handler {
+	return &Handler{
+		notificationRepository,
+	}
+}
@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MarkRead handles POST /api/v1/me/notifications/:id/read
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	notificationID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid notification ID")
+	}
+
+	if err := h.notificationRepository.MarkRead(c.Context(), userID, notificationID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllRead handles POST /api/v1/me/notifications/read-all
+func (h *Handler) MarkAllRead(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	marked, err := h.notificationRepository.MarkAllRead(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"marked_read": marked,
+	})
+}
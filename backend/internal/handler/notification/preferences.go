@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPreferences handles GET /api/v1/me/notification-preferences
+func (h *Handler) GetPreferences(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := h.notificationRepository.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preferences)
+}
+
+// UpdatePreferences handles PUT /api/v1/me/notification-preferences
+// The body maps notification types to whether they are enabled, e.g. {"bottle_caught": false}.
+func (h *Handler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	invalid := map[string]string{}
+	for notificationType := range req {
+		if !notificationType.Valid() {
+			invalid[string(notificationType)] = "unknown notification type"
+		}
+	}
+	if len(invalid) > 0 {
+		return errs.InvalidRequestData(invalid)
+	}
+
+	if err := h.notificationRepository.UpdatePreferences(c.Context(), userID, req); err != nil {
+		return err
+	}
+
+	preferences, err := h.notificationRepository.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preferences)
+}
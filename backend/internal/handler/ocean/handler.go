@@ -2,14 +2,23 @@ package ocean
 
 import (
 	"hackmit/internal/storage"
+	"hackmit/internal/stream"
 )
 
 type Handler struct {
-	oceanRepository storage.OceanRepository
+	oceanRepository  storage.OceanRepository
+	bottleRepository storage.BottleRepository
+	userRepository   storage.UserRepository
+	blockRepository  storage.BlockRepository
+	hub              *stream.Hub
 }
 
-func NewHandler(oceanRepository storage.OceanRepository) *Handler {
+func NewHandler(oceanRepository storage.OceanRepository, bottleRepository storage.BottleRepository, userRepository storage.UserRepository, blockRepository storage.BlockRepository, hub *stream.Hub) *Handler {
 	return &Handler{
 		oceanRepository,
+		bottleRepository,
+		userRepository,
+		blockRepository,
+		hub,
 	}
 }
@@ -0,0 +1,147 @@
+package ocean
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/stream"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// heartbeatInterval keeps idle connections open through proxies and detects clients that went away.
+const heartbeatInterval = 15 * time.Second
+
+// StreamOcean handles GET /api/v1/oceans/:id/stream
+// It pushes newly afloat bottles, catch counts and the number of sailors on the ocean as Server-Sent Events.
+// Personal oceans are only streamed to their owners, and readers are only told of the bottles
+// they could catch.
+func (h *Handler) StreamOcean(c *fiber.Ctx) error {
+	oceanID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid ocean ID")
+	}
+	viewerID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	ocean, err := h.oceanRepository.GetOceanById(c.Context(), oceanID)
+	if err != nil {
+		return err
+	}
+	if ocean.UserID != nil && *ocean.UserID != viewerID {
+		return errs.Forbidden("You can only stream your own personal ocean")
+	}
+
+	// Readers are told of the bottles in the languages they read, as they catch them
+	var languages []models.Language
+	if ocean.UserID == nil {
+		reader, err := h.userRepository.GetUserProfile(c.Context(), viewerID.String())
+		if err != nil {
+			return err
+		}
+		languages = reader.Languages
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	sub := h.hub.Subscribe(oceanID)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					if sub.Overflowed() {
+						// Tell the client it missed events; EventSource reconnects on its own
+						fmt.Fprint(w, "event: overflow\ndata: {}\n\n")
+						w.Flush()
+					}
+					return
+				}
+				// The request's context is gone once the stream starts
+				if !h.shows(context.Background(), viewerID, languages, event) {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// A failed flush means the client disconnected
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// bottleEvent holds the bottle a bottle or catch event is about.
+type bottleEvent struct {
+	Bottle   *struct{ ID int } `json:"bottle"`
+	BottleID *int              `json:"bottle_id"`
+}
+
+// shows reports whether the viewer is told of the event: events about a bottle are only passed
+// on when it is afloat, by an author the viewer hasn't blocked, and in one of the given
+// languages, if there are any, or in none that could be told.
+func (h *Handler) shows(ctx context.Context, viewerID uuid.UUID, languages []models.Language, event stream.Event) bool {
+	var about bottleEvent
+	if err := json.Unmarshal(event.Data, &about); err != nil {
+		return false
+	}
+	var bottleID int
+	switch {
+	case about.Bottle != nil:
+		bottleID = about.Bottle.ID
+	case about.BottleID != nil:
+		bottleID = *about.BottleID
+	default:
+		return true
+	}
+
+	bottle, err := h.bottleRepository.GetBottleByID(ctx, bottleID)
+	if err != nil {
+		if !errs.IsNotFound(err) {
+			slog.Error("failed to look up a streamed bottle", "bottle_id", bottleID, "error", err)
+		}
+		return false
+	}
+	if bottle.Status != models.BottleStatusAfloat {
+		return false
+	}
+	if len(languages) > 0 && bottle.Language != nil && *bottle.Language != models.LanguageUndetermined && !slices.Contains(languages, *bottle.Language) {
+		return false
+	}
+	if bottle.UserID == nil {
+		return true
+	}
+
+	blocks, err := h.blockRepository.GetBlocks(ctx, viewerID)
+	if err != nil {
+		slog.Error("failed to look up a sailor's blocks", "user_id", viewerID, "error", err)
+		return false
+	}
+	return !slices.ContainsFunc(blocks, func(block models.Block) bool {
+		return block.BlockedID == *bottle.UserID
+	})
+}
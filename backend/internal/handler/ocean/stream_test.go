@@ -0,0 +1,74 @@
+package ocean
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/models"
+	"hackmit/internal/storage/memory"
+	"hackmit/internal/stream"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStreamShowsWhatTheReaderCouldCatch(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository(nil)
+	h := NewHandler(repo.Ocean, repo.Bottle, repo.User, repo.Block, nil)
+
+	addUser := func() uuid.UUID {
+		t.Helper()
+		id := uuid.New()
+		if _, err := repo.User.AddUser(ctx, id.String(), nil, nil, id.String()+"@example.com"); err != nil {
+			t.Fatalf("AddUser: %v", err)
+		}
+		return id
+	}
+	reader, friend, pest := addUser(), addUser(), addUser()
+	defaultTag, err := repo.Tag.GetDefaultTag(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultTag: %v", err)
+	}
+	throw := func(author uuid.UUID, language models.Language, status models.BottleStatus) models.Bottle {
+		t.Helper()
+		bottle, err := repo.Bottle.CreateBottle(ctx, models.CreateBottleRequest{Content: "hello", TagID: &defaultTag.ID, UserID: &author, Language: &language, Status: &status})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		return *bottle
+	}
+	if _, err := repo.Block.BlockUser(ctx, reader, pest, nil); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+
+	afloat := models.BottleStatusAfloat
+	english := []models.Language{models.LanguageEnglish}
+	tests := []struct {
+		name      string
+		bottle    models.Bottle
+		languages []models.Language
+		shown     bool
+	}{
+		{"afloat", throw(friend, models.LanguageEnglish, afloat), english, true},
+		{"pending", throw(friend, models.LanguageEnglish, models.BottleStatusPending), english, false},
+		{"blocked author", throw(pest, models.LanguageEnglish, afloat), english, false},
+		{"other language", throw(friend, models.LanguageFrench, afloat), english, false},
+		{"undetermined language", throw(friend, models.LanguageUndetermined, afloat), english, true},
+		{"any language", throw(friend, models.LanguageFrench, afloat), nil, true},
+	}
+	for _, tt := range tests {
+		for _, event := range []stream.Event{
+			{Type: "bottle", Data: fmt.Appendf(nil, `{"type":"bottle","ocean_id":1,"bottle":{"id":%d}}`, tt.bottle.ID)},
+			{Type: "catch", Data: fmt.Appendf(nil, `{"type":"catch","ocean_id":1,"bottle_id":%d,"catch_count":1}`, tt.bottle.ID)},
+		} {
+			if got := h.shows(ctx, reader, tt.languages, event); got != tt.shown {
+				t.Errorf("%s %s event shown = %v, want %v", tt.name, event.Type, got, tt.shown)
+			}
+		}
+	}
+
+	presence := stream.Event{Type: "presence", Data: []byte(`{"type":"presence","ocean_id":1,"sailors":2}`)}
+	if !h.shows(ctx, reader, english, presence) {
+		t.Error("presence event not shown")
+	}
+}
@@ -0,0 +1,75 @@
+package profile
+
+import (
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UploadAvatar handles PUT /api/v1/me/avatar, a multipart upload with the image in the
+// "avatar" field. The avatar it replaces is deleted.
+func (h *Handler) UploadAvatar(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		return errs.BadRequest("Expected an image in the avatar field")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return errs.BadRequest("Could not read the uploaded avatar")
+	}
+	defer file.Close()
+
+	key, err := h.avatars.Save(userID, file)
+	if err != nil {
+		return err
+	}
+
+	previous, err := h.userRepository.SetUserAvatar(c.Context(), userID, &key)
+	if err != nil {
+		h.deleteAvatar(key)
+		return err
+	}
+	if previous != nil {
+		h.deleteAvatar(*previous)
+	}
+
+	user, err := h.userRepository.GetUserProfile(c.Context(), userID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(withAvatar(user))
+}
+
+// DeleteAvatar handles DELETE /api/v1/me/avatar
+func (h *Handler) DeleteAvatar(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	previous, err := h.userRepository.SetUserAvatar(c.Context(), userID, nil)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		h.deleteAvatar(*previous)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// deleteAvatar removes files that are no longer referenced. Failing to is only logged, since
+// the user's profile has already changed.
+func (h *Handler) deleteAvatar(key string) {
+	if err := h.avatars.Delete(key); err != nil {
+		slog.Error("Failed to delete avatar", "key", key, "err", err)
+	}
+}
@@ -0,0 +1,25 @@
+package profile
+
+import (
+	"hackmit/internal/avatar"
+	"hackmit/internal/storage"
+)
+
+// ContentChecker tells whether text would be turned away as hateful.
+type ContentChecker interface {
+	IsHateful(text string) bool
+}
+
+type Handler struct {
+	userRepository storage.UserRepository
+	avatars        *avatar.Store
+	checker        ContentChecker
+}
+
+func NewHandler(userRepository storage.UserRepository, avatars *avatar.Store, checker ContentChecker) *Handler {
+	return &Handler{
+		userRepository,
+		avatars,
+		checker,
+	}
+}
@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/avatar"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxNameLength = 100
+	maxBioLength  = 280
+)
+
+// displayNamePattern allows letters, digits and a few separators, so that display names can't
+// impersonate system text or carry markup.
+var displayNamePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} ._-]{1,28}[\p{L}\p{N}]$`)
+
+// GetProfile handles GET /api/v1/me
+func (h *Handler) GetProfile(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userRepository.GetUserProfile(c.Context(), userID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(withAvatar(user))
+}
+
+// UpdateProfile handles PATCH /api/v1/me. Fields left out of the body are unchanged, and
+// fields set to "" are cleared.
+func (h *Handler) UpdateProfile(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+
+	for _, field := range []*string{req.FirstName, req.LastName, req.DisplayName, req.Bio} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	problems := map[string]string{}
+	if req.FirstName != nil && utf8.RuneCountInString(*req.FirstName) > maxNameLength {
+		problems["first_name"] = fmt.Sprintf("must be at most %d characters", maxNameLength)
+	}
+	if req.LastName != nil && utf8.RuneCountInString(*req.LastName) > maxNameLength {
+		problems["last_name"] = fmt.Sprintf("must be at most %d characters", maxNameLength)
+	}
+	if req.DisplayName != nil && *req.DisplayName != "" && !displayNamePattern.MatchString(*req.DisplayName) {
+		problems["display_name"] = "must be 3 to 30 letters, digits, spaces, dots, dashes or underscores, starting and ending with a letter or digit"
+	}
+	if req.Bio != nil && utf8.RuneCountInString(*req.Bio) > maxBioLength {
+		problems["bio"] = fmt.Sprintf("must be at most %d characters", maxBioLength)
+	}
+	// Display names and bios are shown alongside bottles, so they are held to the same rules
+	if req.DisplayName != nil && problems["display_name"] == "" && h.checker.IsHateful(*req.DisplayName) {
+		problems["display_name"] = "contains inappropriate content that violates our community guidelines"
+	}
+	if req.Bio != nil && problems["bio"] == "" && h.checker.IsHateful(*req.Bio) {
+		problems["bio"] = "contains inappropriate content that violates our community guidelines"
+	}
+	if req.Languages != nil {
+		var languages []models.Language
+		for _, language := range *req.Languages {
+			if !language.Valid() {
+				problems["languages"] = fmt.Sprintf("must be among %v", models.Languages)
+				break
+			}
+			if !slices.Contains(languages, language) {
+				languages = append(languages, language)
+			}
+		}
+		// An empty list clears the languages, where a nil one would leave them unchanged
+		if languages == nil {
+			languages = []models.Language{}
+		}
+		req.Languages = &languages
+	}
+	if len(problems) > 0 {
+		return errs.InvalidRequestData(problems)
+	}
+
+	user, err := h.userRepository.UpdateUserProfile(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(withAvatar(user))
+}
+
+func withAvatar(user *models.User) *models.User {
+	if user.AvatarKey != nil {
+		user.Avatar = avatar.URLs(*user.AvatarKey)
+	}
+	return user
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"hackmit/internal/notify"
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	reportRepository storage.ReportRepository
+	bottleRepository storage.BottleRepository
+	transactor       storage.Transactor
+	notifier         *notify.Notifier
+	// threshold is how many readers' reports hide a bottle until it is reviewed.
+	threshold int
+}
+
+func NewHandler(reportRepository storage.ReportRepository, bottleRepository storage.BottleRepository, transactor storage.Transactor, notifier *notify.Notifier, threshold int) *Handler {
+	return &Handler{
+		reportRepository,
+		bottleRepository,
+		transactor,
+		notifier,
+		threshold,
+	}
+}
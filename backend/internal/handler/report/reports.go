@@ -0,0 +1,123 @@
+package report
+
+import (
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxCommentLength = 280
+	// maxReasonLength bounds the explanation a moderator gives for a decision.
+	maxReasonLength = 500
+)
+
+// ReportBottle handles POST /api/v1/bottle/:id/report. Once enough readers have reported a
+// bottle it is taken out of the ocean until a moderator has reviewed it.
+func (h *Handler) ReportBottle(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	bottleID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid bottle ID")
+	}
+
+	var req models.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+	if !req.Reason.Valid() {
+		return errs.InvalidRequestData(map[string]string{"reason": fmt.Sprintf("must be one of %v", models.ReportReasons)})
+	}
+	if req.Comment != nil {
+		comment := strings.TrimSpace(*req.Comment)
+		if utf8.RuneCountInString(comment) > maxCommentLength {
+			return errs.InvalidRequestData(map[string]string{"comment": fmt.Sprintf("must be at most %d characters", maxCommentLength)})
+		}
+		req.Comment = &comment
+		if comment == "" {
+			req.Comment = nil
+		}
+	}
+
+	bottle, err := h.bottleRepository.GetBottleByID(c.Context(), bottleID)
+	if err != nil {
+		return err
+	}
+	// Only bottles readers can catch can be reported; held and hidden ones stay out of sight
+	if bottle.Status != models.BottleStatusAfloat {
+		return errs.NotFound("bottle", "id", strconv.Itoa(bottleID))
+	}
+	if bottle.UserID != nil && *bottle.UserID == userID {
+		return errs.BadRequest("You cannot report your own bottle")
+	}
+
+	report, err := h.reportRepository.CreateReport(c.Context(), bottleID, userID, req)
+	if err != nil {
+		return err
+	}
+
+	reports, err := h.reportRepository.CountPendingReports(c.Context(), bottleID)
+	if err != nil {
+		return err
+	}
+	if reports >= h.threshold {
+		var hidden *models.Bottle
+		err := h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+			var err error
+			if hidden, err = tx.Bottle.SetBottleStatus(c.Context(), bottleID, models.BottleStatusPending); err != nil {
+				return err
+			}
+			// The entry is also what tells a dismissal that the bottle was hidden by its reports
+			target := strconv.Itoa(bottleID)
+			_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+				Action:     models.AuditBottleHidden,
+				TargetType: models.AuditTargetBottle,
+				TargetID:   &target,
+				Details:    map[string]any{"reports": reports, "threshold": h.threshold},
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		h.notifier.BottleModerated(c.Context(), *hidden)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// GetMyReports handles GET /api/v1/me/reports, where readers follow what came of their reports.
+func (h *Handler) GetMyReports(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	reports, err := h.reportRepository.ListReports(c.Context(), models.ListReportsRequest{ReporterID: &userID}, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reports": reports,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
@@ -0,0 +1,233 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetReports handles GET /api/v1/admin/reports, the pending reports unless another status is asked for.
+func (h *Handler) GetReports(c *fiber.Ctx) error {
+	var filterParams models.ListReportsRequest
+	if err := c.QueryParser(&filterParams); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	if filterParams.Status == nil {
+		pending := models.ReportStatusPending
+		filterParams.Status = &pending
+	}
+	if !filterParams.Status.Valid() {
+		return errs.InvalidRequestData(map[string]string{"status": "must be pending or one of the resolutions"})
+	}
+
+	var page models.PaginationRequest
+	if err := c.QueryParser(&page); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing query parameters: %v", err))
+	}
+	page.Normalize()
+
+	reports, err := h.reportRepository.ListReports(c.Context(), filterParams, page)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reports": reports,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+	})
+}
+
+// ResolveReport handles POST /api/v1/admin/reports/:id/resolve. The decision is about the
+// bottle, so it resolves every pending report on it: dismissing them sets a bottle hidden by
+// reports afloat again, while removing it, with or without a strike against its author, takes
+// it out of the ocean for good. The decision and the moderator's reason are audited, and the
+// author and every reporter are told the outcome.
+func (h *Handler) ResolveReport(c *fiber.Ctx) error {
+	moderatorID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	reportID, err := c.ParamsInt("id")
+	if err != nil {
+		return errs.BadRequest("Invalid report ID")
+	}
+
+	var req models.ResolveReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errs.BadRequest(fmt.Sprintf("error parsing request body: %v", err))
+	}
+	if !req.Resolution.Valid() || req.Resolution == models.ReportStatusPending {
+		return errs.InvalidRequestData(map[string]string{"resolution": fmt.Sprintf("must be one of %v", models.ReportResolutions)})
+	}
+	if req.Reason != nil {
+		reason := strings.TrimSpace(*req.Reason)
+		if utf8.RuneCountInString(reason) > maxReasonLength {
+			return errs.InvalidRequestData(map[string]string{"reason": fmt.Sprintf("must be at most %d characters", maxReasonLength)})
+		}
+		req.Reason = &reason
+		if reason == "" {
+			req.Reason = nil
+		}
+	}
+
+	report, err := h.reportRepository.GetReport(c.Context(), reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != models.ReportStatusPending {
+		return errs.Conflict(fmt.Sprintf("This report was already resolved as %s", report.Status))
+	}
+
+	var (
+		bottle   *models.Bottle
+		moved    bool
+		strike   *models.Strike
+		resolved []models.Report
+	)
+	err = h.transactor.WithTx(c.Context(), func(tx *storage.Repository) error {
+		bottle, err = tx.Bottle.GetBottleByID(c.Context(), report.BottleID)
+		if err != nil {
+			return err
+		}
+
+		// Dismissing reports only undoes what they did: a bottle the checker or a moderator
+		// held stays held until a moderator approves it
+		previous := bottle.Status
+		status := bottle.Status
+		switch req.Resolution {
+		case models.ReportStatusDismissed:
+			if bottle.Status == models.BottleStatusPending {
+				hidden, err := hiddenByReports(c.Context(), tx.Audit, bottle.ID)
+				if err != nil {
+					return err
+				}
+				if hidden {
+					status = models.BottleStatusAfloat
+				}
+			}
+		case models.ReportStatusRemoved, models.ReportStatusStruck:
+			status = models.BottleStatusRemoved
+		}
+		if status != bottle.Status {
+			if bottle, err = tx.Bottle.SetBottleStatus(c.Context(), bottle.ID, status); err != nil {
+				return err
+			}
+			moved = true
+		}
+
+		if req.Resolution == models.ReportStatusStruck {
+			if bottle.UserID == nil {
+				return errs.UnprocessableEntity("This bottle was thrown anonymously, so there is no author to strike")
+			}
+			if strike, err = tx.Report.AddStrike(c.Context(), *bottle.UserID, &bottle.ID, &moderatorID); err != nil {
+				return err
+			}
+			author := bottle.UserID.String()
+			_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+				Action:     models.AuditUserStruck,
+				ActorID:    &moderatorID,
+				TargetType: models.AuditTargetUser,
+				TargetID:   &author,
+				Reason:     req.Reason,
+				Details:    map[string]any{"strike_id": strike.ID, "bottle_id": bottle.ID},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if resolved, err = tx.Report.ResolveReports(c.Context(), bottle.ID, req.Resolution, &moderatorID); err != nil {
+			return err
+		}
+
+		reportIDs := make([]int, len(resolved))
+		for i, report := range resolved {
+			reportIDs[i] = report.ID
+		}
+		target := strconv.Itoa(bottle.ID)
+		_, err = tx.Audit.RecordAudit(c.Context(), models.AuditEntry{
+			Action:     models.AuditReportResolved,
+			ActorID:    &moderatorID,
+			TargetType: models.AuditTargetBottle,
+			TargetID:   &target,
+			Reason:     req.Reason,
+			Details: map[string]any{
+				"resolution":      req.Resolution,
+				"report_ids":      reportIDs,
+				"previous_status": previous,
+				"status":          bottle.Status,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if moved {
+		h.notifier.BottleModerated(c.Context(), *bottle)
+	}
+	for _, report := range resolved {
+		h.notifier.ReportResolved(c.Context(), report)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"bottle":  bottle.Moderated(),
+		"reports": resolved,
+		"strike":  strike,
+	})
+}
+
+// hiddenByReports reports whether the last change to the bottle's status was readers' reports
+// hiding it, going by the audit log every change of status is recorded in.
+func hiddenByReports(ctx context.Context, audit storage.AuditRepository, bottleID int) (bool, error) {
+	targetType := models.AuditTargetBottle
+	target := strconv.Itoa(bottleID)
+	page := models.PaginationRequest{Limit: models.MaxPageLimit}
+	for {
+		entries, err := audit.ListAudit(ctx, models.ListAuditRequest{TargetType: &targetType, TargetID: &target}, page)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			switch entry.Action {
+			case models.AuditBottleHidden:
+				return true, nil
+			case models.AuditBottleScreened, models.AuditBottleModerated, models.AuditReportResolved:
+				return false, nil
+			}
+		}
+		if len(entries) < page.Limit {
+			return false, nil
+		}
+		page.Offset += page.Limit
+	}
+}
+
+// GetStrikes handles GET /api/v1/admin/users/:id/strikes
+func (h *Handler) GetStrikes(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid user ID")
+	}
+
+	strikes, err := h.reportRepository.GetStrikes(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"strikes": strikes,
+	})
+}
@@ -0,0 +1,15 @@
+package session
+
+import (
+	"hackmit/internal/storage"
+)
+
+type Handler struct {
+	sessionRepository storage.SessionRepository
+}
+
+func NewHandler(sessionRepository storage.SessionRepository) *Handler {
+	return &Handler{
+		sessionRepository,
+	}
+}
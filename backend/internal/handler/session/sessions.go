@@ -0,0 +1,87 @@
+package session
+
+import (
+	"hackmit/internal/auth"
+	"hackmit/internal/errs"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetSessions handles GET /api/v1/me/sessions
+func (h *Handler) GetSessions(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+	currentID, err := auth.SessionID(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.sessionRepository.ListSessions(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession handles DELETE /api/v1/me/sessions/:id
+func (h *Handler) RevokeSession(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+	currentID, err := auth.SessionID(c)
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return errs.BadRequest("Invalid session ID")
+	}
+
+	// Other users' sessions are as good as missing
+	session, err := h.sessionRepository.GetSession(c.Context(), sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return errs.NotFound("session", "id", sessionID.String())
+	}
+
+	if err := h.sessionRepository.RevokeSession(c.Context(), sessionID); err != nil {
+		return err
+	}
+	if sessionID == currentID {
+		auth.ClearSessionCookies(c)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeAllSessions handles DELETE /api/v1/me/sessions, signing the user out everywhere
+// including here.
+func (h *Handler) RevokeAllSessions(c *fiber.Ctx) error {
+	userID, err := auth.UserID(c)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := h.sessionRepository.RevokeUserSessions(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	auth.ClearSessionCookies(c)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,18 @@
+// Package language tells which language a bottle is written in, so that readers can fish for
+// bottles they can read and moderation can apply the rules written for that language.
+package language
+
+import "hackmit/internal/models"
+
+// Result is the language a text reads as, and how confident the detector is of it, from 0 to
+// 1. Texts it can't tell are models.LanguageUndetermined.
+type Result struct {
+	Language   models.Language
+	Confidence float64
+}
+
+// Detector tells the language of a text. It must work offline, as it runs on every bottle
+// thrown.
+type Detector interface {
+	Detect(text string) Result
+}
@@ -0,0 +1,156 @@
+package language
+
+import (
+	"embed"
+	"fmt"
+	"hackmit/internal/models"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// corpus holds a sample of text in each language, named after its code, that the model is
+// trained on when the detector is created.
+//
+//go:embed corpus/*.txt
+var corpus embed.FS
+
+const (
+	// minTrigrams is how many trigrams a text needs for its language to be told; a couple of
+	// words are shared by too many languages.
+	minTrigrams = 12
+	// minConfidence is how likely the best language must be for the text to be taken as
+	// written in it.
+	minConfidence = 0.9
+)
+
+// profile is how likely each trigram is in a language. Trigrams the sample never had get the
+// likelihood of having been seen once less than that of a trigram seen once.
+type profile struct {
+	logProbs map[string]float64
+	unseen   float64
+}
+
+// NGram is a naive Bayes classifier over the character trigrams of a text.
+type NGram struct {
+	languages []models.Language
+	profiles  map[models.Language]profile
+}
+
+// NewNGram trains the detector on the built-in samples.
+func NewNGram() *NGram {
+	detector, err := train(corpus)
+	if err != nil {
+		panic(fmt.Sprintf("language: built-in corpus: %v", err))
+	}
+	return detector
+}
+
+// train builds a profile from each corpus/<code>.txt in fsys, smoothing the counts so that
+// every trigram seen in any language has some likelihood in all of them.
+func train(fsys fs.FS) (*NGram, error) {
+	names, err := fs.Glob(fsys, "corpus/*.txt")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no samples")
+	}
+
+	counts := map[models.Language]map[string]int{}
+	vocabulary := map[string]bool{}
+	for _, name := range names {
+		language := models.Language(strings.TrimSuffix(path.Base(name), ".txt"))
+		if !language.Valid() {
+			return nil, fmt.Errorf("%s: unknown language %q", name, language)
+		}
+		sample, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		grams := trigrams(string(sample))
+		if len(grams) == 0 {
+			return nil, fmt.Errorf("%s: empty sample", name)
+		}
+		counts[language] = map[string]int{}
+		for _, gram := range grams {
+			counts[language][gram]++
+			vocabulary[gram] = true
+		}
+	}
+
+	n := &NGram{profiles: map[models.Language]profile{}}
+	for language, grams := range counts {
+		total := 0
+		for _, count := range grams {
+			total += count
+		}
+		denominator := float64(total + len(vocabulary) + 1)
+
+		p := profile{logProbs: map[string]float64{}, unseen: math.Log(1 / denominator)}
+		for gram, count := range grams {
+			p.logProbs[gram] = math.Log(float64(count+1) / denominator)
+		}
+		n.profiles[language] = p
+		n.languages = append(n.languages, language)
+	}
+	slices.Sort(n.languages)
+	return n, nil
+}
+
+// Detect scores text against each language, taking them all as equally likely beforehand.
+// Short texts, and texts that could as well be in another language, are undetermined.
+func (n *NGram) Detect(text string) Result {
+	grams := trigrams(text)
+	if len(grams) < minTrigrams {
+		return Result{Language: models.LanguageUndetermined}
+	}
+
+	scores := make([]float64, len(n.languages))
+	for i, language := range n.languages {
+		p := n.profiles[language]
+		for _, gram := range grams {
+			if logProb, ok := p.logProbs[gram]; ok {
+				scores[i] += logProb
+			} else {
+				scores[i] += p.unseen
+			}
+		}
+	}
+
+	best := 0
+	for i := range scores {
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+	// The posterior of the best language, computed relative to it so that nothing underflows
+	var sum float64
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	confidence := math.Round(1/sum*1000) / 1000
+
+	if confidence < minConfidence {
+		return Result{Language: models.LanguageUndetermined, Confidence: confidence}
+	}
+	return Result{Language: n.languages[best], Confidence: confidence}
+}
+
+// trigrams splits text into lowercase words of letters and returns the overlapping three-rune
+// sequences of each, padded with a space on either side so that "sea" yields " se", "sea"
+// and "ea ".
+func trigrams(text string) []string {
+	var grams []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+	return grams
+}
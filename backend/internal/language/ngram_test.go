@@ -0,0 +1,49 @@
+package language
+
+import (
+	"hackmit/internal/models"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetect(t *testing.T) {
+	detector := NewNGram()
+
+	tests := []struct {
+		text     string
+		language models.Language
+	}{
+		{"Whoever finds this, I hope the tide brings you something good this summer.", models.LanguageEnglish},
+		{"Quien encuentre esta botella, espero que tengas un buen verano junto al mar.", models.LanguageSpanish},
+		{"À celui qui trouvera cette bouteille, je te souhaite un très bel été au bord de la mer.", models.LanguageFrench},
+		{"Wer diese Flasche findet, dem wünsche ich einen schönen Sommer am Meer.", models.LanguageGerman},
+		{"Chiunque trovi questa bottiglia, ti auguro una bellissima estate al mare.", models.LanguageItalian},
+		{"Quem encontrar esta garrafa, desejo-te um verão muito feliz junto ao mar.", models.LanguagePortuguese},
+		{"Wie deze fles vindt, ik wens je een hele mooie zomer aan zee.", models.LanguageDutch},
+		// Too short to tell
+		{"hola", models.LanguageUndetermined},
+		{"ok :)", models.LanguageUndetermined},
+		{"", models.LanguageUndetermined},
+	}
+	for _, test := range tests {
+		got := detector.Detect(test.text)
+		if got.Language != test.language {
+			t.Errorf("Detect(%q) = %s (%.3f), want %s", test.text, got.Language, got.Confidence, test.language)
+		}
+		if got.Language != models.LanguageUndetermined && got.Confidence < minConfidence {
+			t.Errorf("Detect(%q) confidence = %v, want at least %v", test.text, got.Confidence, minConfidence)
+		}
+	}
+}
+
+func TestTrain(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"no samples":       {},
+		"unknown language": {"corpus/xx.txt": {Data: []byte("some text")}},
+		"empty sample":     {"corpus/en.txt": {Data: []byte("42 !")}},
+	} {
+		if _, err := train(fsys); err == nil {
+			t.Errorf("train with %s succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage describes the arguments accepted by Run.
+const Usage = `commands:
+  up          apply all pending migrations and seed system data
+  down [n]    roll back the last n applied migrations (default 1)
+  status      list migrations and whether they have been applied`
+
+// ErrUsage is returned by Run when its arguments don't name a command.
+var ErrUsage = errors.New("unknown migrate command")
+
+// Run executes a migrate command line (up, down [n] or status), reporting to w.
+func Run(ctx context.Context, migrator *Migrator, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		fmt.Fprintf(w, "Applied %d migration(s)\n", len(ran))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to roll back: %s", args[1])
+			}
+		}
+		ran, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+		fmt.Fprintf(w, "Rolled back %d migration(s)\n", len(ran))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("error reading migration status: %w", err)
+		}
+		return WriteStatus(w, statuses)
+	default:
+		return ErrUsage
+	}
+
+	return nil
+}
+
+// WriteStatus prints a migration status report as a table.
+func WriteStatus(w io.Writer, statuses []Status) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tSTATE\tAPPLIED AT\tROLLBACK")
+
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Unknown:
+			state = "applied (unknown to this build)"
+		case status.ChecksumMismatch:
+			state = "applied (MODIFIED SINCE)"
+		case status.Applied:
+			state = "applied"
+		}
+
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+
+		rollback := "no"
+		if status.Reversible {
+			rollback = "yes"
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt, rollback)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,378 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey identifies the advisory lock that serialises migration runs across instances.
+const lockKey int64 = 0x6f6365616e // "ocean"
+
+// migrationFile matches Supabase-style migration names: <version>_<name>.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+type Migration struct {
+	Version  string
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version          string     `json:"version"`
+	Name             string     `json:"name"`
+	Applied          bool       `json:"applied"`
+	AppliedAt        *time.Time `json:"applied_at,omitempty"`
+	ChecksumMismatch bool       `json:"checksum_mismatch,omitempty"`
+	Reversible       bool       `json:"reversible"`
+	Unknown          bool       `json:"unknown,omitempty"`
+}
+
+type appliedMigration struct {
+	Version   string
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded SQL migrations and tracks them in the schema_migrations table.
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+	seed       string
+}
+
+// New loads migrations/*.sql, their optional rollbacks/*.sql counterparts and seed.sql from files.
+func New(db *pgxpool.Pool, files fs.FS) (*Migrator, error) {
+	ups, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(ups))
+	for _, file := range ups {
+		match := migrationFile.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.sql", file)
+		}
+
+		up, err := fs.ReadFile(files, file)
+		if err != nil {
+			return nil, err
+		}
+
+		down, err := fs.ReadFile(files, path.Join("rollbacks", path.Base(file)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(up)
+		migrations = append(migrations, Migration{
+			Version:  match[1],
+			Name:     match[2],
+			Up:       string(up),
+			Down:     string(down),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %s", migrations[i].Version)
+		}
+	}
+
+	seed, err := fs.ReadFile(files, "seed.sql")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		seed:       string(seed),
+	}, nil
+}
+
+// Up applies every pending migration in order, then the seed data.
+// It refuses to run if an applied migration's file has changed since it was applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			slog.Info("applying migration", "version", migration.Version, "name", migration.Name)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `
+					INSERT INTO schema_migrations (version, name, checksum)
+					VALUES ($1, $2, $3)
+				`, migration.Version, migration.Name, migration.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %s_%s: %w", migration.Version, migration.Name, err)
+			}
+			ran = append(ran, migration)
+		}
+
+		return m.seedWith(ctx, conn)
+	})
+
+	return ran, err
+}
+
+// Down rolls back the most recently applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var ran []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("migration %s_%s has no rollback", migration.Version, migration.Name)
+			}
+
+			slog.Info("rolling back migration", "version", migration.Version, "name", migration.Name)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error rolling back migration %s_%s: %w", migration.Version, migration.Name, err)
+			}
+			ran = append(ran, migration)
+		}
+
+		return nil
+	})
+
+	return ran, err
+}
+
+// Status reports every known migration and whether it has been applied, plus any applied
+// migration this binary doesn't know about.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{
+				Version:    migration.Version,
+				Name:       migration.Name,
+				Reversible: strings.TrimSpace(migration.Down) != "",
+			}
+			if record, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = &record.AppliedAt
+				status.ChecksumMismatch = record.Checksum != migration.Checksum
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		for _, record := range applied {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, Status{
+				Version:   record.Version,
+				Name:      record.Name,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Seed inserts the system tags and default ocean if they are missing.
+func (m *Migrator) Seed(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return m.seedWith(ctx, conn)
+	})
+}
+
+func (m *Migrator) seedWith(ctx context.Context, conn *pgxpool.Conn) error {
+	if strings.TrimSpace(m.seed) == "" {
+		return nil
+	}
+	if _, err := conn.Exec(ctx, m.seed); err != nil {
+		return fmt.Errorf("error seeding database: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock, so that
+// instances starting together take turns instead of racing each other.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			slog.Error("failed to release migration lock", "error", err)
+		}
+	}()
+
+	if err := m.ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// ensureVersionTable creates schema_migrations on first use. A database that was previously
+// managed with the Supabase CLI has its migration history adopted rather than re-applied.
+func (m *Migrator) ensureVersionTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(32) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	var tracked bool
+	if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations)`).Scan(&tracked); err != nil {
+		return fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	if tracked {
+		return nil
+	}
+
+	var supabaseHistory bool
+	err = conn.QueryRow(ctx, `SELECT to_regclass('supabase_migrations.schema_migrations') IS NOT NULL`).Scan(&supabaseHistory)
+	if err != nil || !supabaseHistory {
+		return err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version FROM supabase_migrations.schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("error reading Supabase migration history: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("error reading Supabase migration history: %w", err)
+	}
+
+	adopted := map[string]bool{}
+	for _, version := range versions {
+		adopted[version] = true
+	}
+
+	for _, migration := range m.migrations {
+		if !adopted[migration.Version] {
+			continue
+		}
+		slog.Info("adopting migration applied by the Supabase CLI", "version", migration.Version, "name", migration.Name)
+		_, err := conn.Exec(ctx, `
+			INSERT INTO schema_migrations (version, name, checksum)
+			VALUES ($1, $2, $3)
+		`, migration.Version, migration.Name, migration.Checksum)
+		if err != nil {
+			return fmt.Errorf("error adopting migration %s: %w", migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[string]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByName[appliedMigration])
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	applied := make(map[string]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// verify checks that no applied migration has been edited since it ran.
+func (m *Migrator) verify(applied map[string]appliedMigration) error {
+	var changed []string
+	for _, migration := range m.migrations {
+		if record, ok := applied[migration.Version]; ok && record.Checksum != migration.Checksum {
+			changed = append(changed, migration.Version+"_"+migration.Name)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("applied migrations have been modified: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletion is a pending request to delete an account, which can be cancelled until
+// DeleteAfter.
+type AccountDeletion struct {
+	UserID uuid.UUID `json:"user_id"`
+	// KeepBottles leaves the user's bottles afloat, stripped of author and user, instead of
+	// deleting them.
+	KeepBottles bool      `json:"keep_bottles"`
+	RequestedAt time.Time `json:"requested_at"`
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+type DeleteAccountRequest struct {
+	KeepBottles bool `json:"keep_bottles"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is what an audit entry records being done.
+type AuditAction string
+
+const (
+	// AuditBottleScreened is the content checker's verdict on a bottle being thrown.
+	AuditBottleScreened AuditAction = "bottle.screened"
+	// AuditBottleHidden is a bottle being taken out of the ocean because enough readers reported it.
+	AuditBottleHidden AuditAction = "bottle.hidden"
+	// AuditBottleModerated is an operator approving or rejecting a bottle.
+	AuditBottleModerated AuditAction = "bottle.moderated"
+	// AuditBottleDeleted is a moderator deleting someone else's bottle outright.
+	AuditBottleDeleted   AuditAction = "bottle.deleted"
+	AuditReportResolved  AuditAction = "report.resolved"
+	AuditUserStruck      AuditAction = "user.struck"
+	AuditUserRoleChanged AuditAction = "user.role_changed"
+	AuditUserDeleted     AuditAction = "user.deleted"
+	AuditUserAnonymized  AuditAction = "user.anonymized"
+	AuditTagsSeeded      AuditAction = "tag.seeded"
+	AuditOceanUpdated    AuditAction = "ocean.updated"
+)
+
+// AuditTarget is the kind of thing an audit entry is about.
+type AuditTarget string
+
+const (
+	AuditTargetBottle AuditTarget = "bottle"
+	AuditTargetUser   AuditTarget = "user"
+	AuditTargetTag    AuditTarget = "tag"
+	AuditTargetOcean  AuditTarget = "ocean"
+)
+
+// AuditEntry records a moderation verdict, a moderator's decision or an admin action. Entries
+// are never changed once written.
+type AuditEntry struct {
+	ID     int64       `json:"id"`
+	Action AuditAction `json:"action"`
+	// ActorID is who made the decision; it is empty for automated verdicts and for operators
+	// using the admin command.
+	ActorID    *uuid.UUID  `json:"actor_id,omitempty"`
+	TargetType AuditTarget `json:"target_type"`
+	TargetID   *string     `json:"target_id,omitempty"`
+	// Reason is why a person made the decision, in their own words.
+	Reason    *string        `json:"reason,omitempty"`
+	Details   map[string]any `json:"details"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type ListAuditRequest struct {
+	Action     *AuditAction `query:"action"`
+	TargetType *AuditTarget `query:"target_type"`
+	TargetID   *string      `query:"target_id"`
+	// ActorID, Since and Until are parsed by the handler, since the query parser reads neither
+	// UUIDs nor times.
+	ActorID *uuid.UUID `query:"-"`
+	Since   *time.Time `query:"-"`
+	Until   *time.Time `query:"-"`
+}
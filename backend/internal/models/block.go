@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Block hides the blocked user's bottles and replies from the blocker. Bottles are anonymous, so
+// an author can be blocked from one of their bottles without the blocker learning who they are.
+type Block struct {
+	BlockerID uuid.UUID `json:"-"`
+	BlockedID uuid.UUID `json:"-"`
+	// UserID is the blocked user, shown only when they were blocked by their ID.
+	UserID *uuid.UUID `json:"user_id,omitempty" db:"-"`
+	// BottleID is the bottle the author was blocked from, if they were blocked that way.
+	BottleID  *int      `json:"bottle_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -6,14 +6,55 @@ import (
 	"github.com/google/uuid"
 )
 
+// BottleStatus is where a bottle is in its lifecycle.
+type BottleStatus string
+
+const (
+	BottleStatusPending  BottleStatus = "pending"  // held back until a moderator reviews it
+	BottleStatusAfloat   BottleStatus = "afloat"   // drifting in its oceans and catchable
+	BottleStatusRejected BottleStatus = "rejected" // turned down by a moderator
+	BottleStatusRemoved  BottleStatus = "removed"  // pulled out of the ocean after being afloat
+)
+
 type Bottle struct {
-	ID           int        `json:"id"`
-	Content      string     `json:"content"`
-	Author       *string    `json:"author,omitempty"`
-	TagID        int        `json:"tag_id"`
-	UserID       *uuid.UUID `json:"user_id,omitempty"`
-	LocationFrom *string    `json:"location_from,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID      int     `json:"id"`
+	Content string  `json:"content"`
+	Author  *string `json:"author,omitempty"`
+	TagID   int     `json:"tag_id"`
+	// UserID is who threw the bottle. Readers never see it, so that bottles stay anonymous;
+	// moderators get it through ModeratedBottle.
+	UserID       *uuid.UUID   `json:"-"`
+	LocationFrom *string      `json:"location_from,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	Status       BottleStatus `json:"status"`
+	// Mood is unset on bottles thrown before moods were read.
+	Mood      *Mood    `json:"mood,omitempty"`
+	MoodScore *float64 `json:"mood_score,omitempty"`
+	// Language is unset on bottles thrown before languages were detected.
+	Language *Language `json:"language,omitempty"`
+	// TagSource tells tags writers chose from those the classifier or the Default fallback gave.
+	TagSource TagSource `json:"tag_source"`
+}
+
+// ModeratedBottle is a bottle as moderators and operators see it, with who threw it.
+type ModeratedBottle struct {
+	Bottle
+	AuthorID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// Moderated returns the bottle as moderators see it.
+func (b Bottle) Moderated() ModeratedBottle {
+	return ModeratedBottle{b, b.UserID}
+}
+
+// AuthoredBottle is a bottle as its author sees it on their dashboard.
+type AuthoredBottle struct {
+	Bottle
+	CatchCount    int        `json:"catch_count"`
+	FirstCaughtAt *time.Time `json:"first_caught_at,omitempty"`
+	LastCaughtAt  *time.Time `json:"last_caught_at,omitempty"`
+	ReplyCount    int        `json:"reply_count"`
+	State         string     `json:"state"`
 }
 
 type CreateBottleRequest struct {
@@ -23,14 +64,51 @@ type CreateBottleRequest struct {
 	UserID       *uuid.UUID `json:"user_id,omitempty"`
 	LocationFrom *string    `json:"location_from,omitempty"`
 	Personal     *bool      `json:"personal,omitempty"`
+	// Status is decided by moderation, never by the client.
+	Status *BottleStatus `json:"-"`
+	// Original is what the author wrote, kept when moderation masked part of it.
+	Original *BottleOriginal `json:"-"`
+	// Mood and MoodScore are read from the content, never given by the client.
+	Mood      *Mood    `json:"-"`
+	MoodScore *float64 `json:"-"`
+	// Language is detected from the content, never given by the client.
+	Language *Language `json:"-"`
+	// TagSource is decided while the bottle is screened, never given by the client.
+	TagSource *TagSource `json:"-"`
+}
+
+// BottleOriginal is a bottle's text as its author wrote it, before profanity in it was masked.
+// Only moderators get to see it.
+type BottleOriginal struct {
+	Content      string  `json:"content"`
+	Author       *string `json:"author,omitempty"`
+	LocationFrom *string `json:"location_from,omitempty"`
 }
 
 type GetBottlesRequest struct {
-	OceanID int  `query:"ocean_id"`
-	UserID  *int `query:"user_id,omitempty"`
+	OceanID int `query:"ocean_id"`
+	// ViewerID is the signed-in reader, whose blocked authors are left out.
+	ViewerID *uuid.UUID `query:"-"`
+	// Mood limits the catch to bottles read as having that mood.
+	Mood *Mood `query:"mood"`
+}
+
+// ListBottlesRequest filters bottles for operators, whatever their status or visibility.
+type ListBottlesRequest struct {
+	OceanID *int
+	Status  *BottleStatus
 }
 
 type GetRandomBottleRequest struct {
-	OceanID      int        `query:"ocean_id"`
-	SeenByUserId *uuid.UUID `query:"seen_by_user_id,omitempty"`
+	OceanID int `query:"ocean_id"`
+	// SeenByUserId is the reader the catch is recorded for. It is the signed-in reader, never
+	// taken from the query, so that nobody can catch bottles on someone else's behalf.
+	SeenByUserId *uuid.UUID `query:"-"`
+	// ViewerID is the signed-in reader, whose blocked authors are left out.
+	ViewerID *uuid.UUID `query:"-"`
+	// Mood limits the catch to bottles read as having that mood.
+	Mood *Mood `query:"mood"`
+	// Languages limits the catch to bottles in the reader's languages, and to those whose
+	// language couldn't be told. It is taken from the reader's profile.
+	Languages []Language `query:"-"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Catch is a bottle as seen from the reader who fished it out of an ocean.
+type Catch struct {
+	Bottle
+	SeenAt     time.Time `json:"seen_at"`
+	Bookmarked bool      `json:"bookmarked"`
+	Note       *string   `json:"note,omitempty"`
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID `json:"user_id"`
+	BottleID  int       `json:"bottle_id"`
+	Note      *string   `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SaveBookmarkRequest struct {
+	Note *string `json:"note,omitempty"`
+}
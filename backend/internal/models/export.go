@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportStatus is where a data export is in being built.
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending" // waiting for the worker
+	ExportStatusRunning ExportStatus = "running" // being built
+	ExportStatusReady   ExportStatus = "ready"   // built and downloadable until it expires
+	ExportStatusFailed  ExportStatus = "failed"  // could not be built
+)
+
+// ExportJob is a user's request for an archive of their data.
+type ExportJob struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"-"`
+	Status      ExportStatus `json:"status"`
+	Error       *string      `json:"error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	// DownloadURL is a short-lived signed link to the archive, once it is ready.
+	DownloadURL string `json:"download_url,omitempty" db:"-"`
+}
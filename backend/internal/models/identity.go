@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity is an account of the local identity provider.
+type Identity struct {
+	UserID       uuid.UUID `json:"user_id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	// RecoveryTokenHash is the hash of the recovery token the password can still be reset with.
+	RecoveryTokenHash *string `json:"-"`
+}
+
+// IdentitySession is a sign-in of a local identity, kept alive by rotating its refresh token.
+type IdentitySession struct {
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+	RefreshedAt      time.Time  `json:"refreshed_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Live reports whether the session can still be used at the given time.
+func (s *IdentitySession) Live(at time.Time) bool {
+	return s.RevokedAt == nil && at.Before(s.ExpiresAt)
+}
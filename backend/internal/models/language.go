@@ -0,0 +1,26 @@
+package models
+
+import "slices"
+
+// Language is the ISO 639-1 code of the language a bottle is written in, as the language
+// detector reads it.
+type Language string
+
+const (
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+	LanguageFrench     Language = "fr"
+	LanguageGerman     Language = "de"
+	LanguageItalian    Language = "it"
+	LanguagePortuguese Language = "pt"
+	LanguageDutch      Language = "nl"
+	// LanguageUndetermined is a text too short or too mixed to tell.
+	LanguageUndetermined Language = "und"
+)
+
+// Languages lists the languages the detector can tell apart.
+var Languages = []Language{LanguageEnglish, LanguageSpanish, LanguageFrench, LanguageGerman, LanguageItalian, LanguagePortuguese, LanguageDutch}
+
+func (l Language) Valid() bool {
+	return slices.Contains(Languages, l)
+}
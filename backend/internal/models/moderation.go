@@ -0,0 +1,22 @@
+package models
+
+// ModerationCheck is the verdict a draft bottle would get if it were thrown: allowed or blocked.
+type ModerationCheck struct {
+	Verdict string `json:"verdict"`
+	// Categories are those of the harmful content found, masked or not.
+	Categories []string         `json:"categories"`
+	Spans      []ModerationSpan `json:"spans"`
+	// Language is what the content was read as, which decides the rule sets applied to it.
+	Language Language `json:"language"`
+}
+
+// ModerationSpan is a stretch of a draft's field the checker objects to, in characters from
+// the start of the field. Masked spans would be starred out rather than weighed in the verdict.
+type ModerationSpan struct {
+	Field    string `json:"field"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Category string `json:"category"`
+	Severity string `json:"severity"`
+	Masked   bool   `json:"masked"`
+}
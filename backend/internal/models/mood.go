@@ -0,0 +1,22 @@
+package models
+
+import "slices"
+
+// Mood is the tone of a bottle, as the mood classifier reads it.
+type Mood string
+
+const (
+	MoodHopeful Mood = "hopeful"
+	MoodJoyful  Mood = "joyful"
+	MoodCalm    Mood = "calm"
+	MoodSad     Mood = "sad"
+	MoodAngry   Mood = "angry"
+	MoodAnxious Mood = "anxious"
+	MoodNeutral Mood = "neutral"
+)
+
+var Moods = []Mood{MoodHopeful, MoodJoyful, MoodCalm, MoodSad, MoodAngry, MoodAnxious, MoodNeutral}
+
+func (m Mood) Valid() bool {
+	return slices.Contains(Moods, m)
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationBottleCaught    NotificationType = "bottle_caught"
+	NotificationBottleReplied   NotificationType = "bottle_replied"
+	NotificationBottleModerated NotificationType = "bottle_moderated"
+	NotificationReportResolved  NotificationType = "report_resolved"
+)
+
+// NotificationTypes lists every notification type a user can opt in or out of.
+var NotificationTypes = []NotificationType{
+	NotificationBottleCaught,
+	NotificationBottleReplied,
+	NotificationBottleModerated,
+	NotificationReportResolved,
+}
+
+func (t NotificationType) Valid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Notification struct {
+	ID        int              `json:"id"`
+	UserID    uuid.UUID        `json:"-"`
+	Type      NotificationType `json:"type"`
+	BottleID  *int             `json:"bottle_id,omitempty"`
+	Detail    *string          `json:"detail,omitempty"`
+	Count     int              `json:"count"`
+	BatchDate time.Time        `json:"batch_date"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Message   string           `json:"message" db:"-"`
+}
+
+// Describe renders the human readable message for the notification.
+func (n *Notification) Describe() {
+	switch n.Type {
+	case NotificationBottleCaught:
+		day := "on " + n.BatchDate.Format("Jan 2")
+		if n.BatchDate.Format(time.DateOnly) == time.Now().Format(time.DateOnly) {
+			day = "today"
+		}
+		if n.Count == 1 {
+			n.Message = fmt.Sprintf("Your bottle was found %s", day)
+		} else {
+			n.Message = fmt.Sprintf("Your bottle was found %d times %s", n.Count, day)
+		}
+	case NotificationBottleReplied:
+		n.Message = "Someone who caught your bottle replied to it"
+	case NotificationBottleModerated:
+		status := ""
+		if n.Detail != nil {
+			status = *n.Detail
+		}
+		switch BottleStatus(status) {
+		case BottleStatusPending:
+			n.Message = "Your bottle is being held for review before it sets sail"
+		case BottleStatusAfloat:
+			n.Message = "Your bottle was approved and is now afloat"
+		case BottleStatusRejected:
+			n.Message = "Your bottle was rejected by a moderator"
+		case BottleStatusRemoved:
+			n.Message = "Your bottle was removed from the ocean"
+		default:
+			n.Message = "A moderator reviewed your bottle"
+		}
+	case NotificationReportResolved:
+		resolution := ""
+		if n.Detail != nil {
+			resolution = *n.Detail
+		}
+		switch ReportStatus(resolution) {
+		case ReportStatusDismissed:
+			n.Message = "A moderator reviewed the bottle you reported and found it within the community guidelines"
+		case ReportStatusRemoved:
+			n.Message = "Thanks for your report: the bottle was removed from the ocean"
+		case ReportStatusStruck:
+			n.Message = "Thanks for your report: the bottle was removed and its author warned"
+		default:
+			n.Message = "A moderator reviewed the bottle you reported"
+		}
+	default:
+		n.Message = "You have a new notification"
+	}
+}
+
+type NotificationPreference struct {
+	Type    NotificationType `json:"type"`
+	Enabled bool             `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesRequest map[NotificationType]bool
@@ -7,6 +7,12 @@ type Ocean struct {
 	Name        *string    `json:"name,omitempty"`
 	Description *string    `json:"description,omitempty"`
 	UserID      *uuid.UUID `json:"user_id"`
+	// MaskProfanity stars out mild swearing in bottles drifting here instead of turning them away.
+	MaskProfanity bool `json:"mask_profanity"`
+}
+
+type UpdateOceanSettingsRequest struct {
+	MaskProfanity *bool `json:"mask_profanity,omitempty"`
 }
 
 type GetOceansRequest struct {
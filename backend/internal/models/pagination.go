@@ -0,0 +1,24 @@
+package models
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type PaginationRequest struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+// Normalize clamps the limit and offset to sane bounds.
+func (p *PaginationRequest) Normalize() {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reply is a note sent back to a bottle's author by someone who caught it.
+type Reply struct {
+	ID        int       `json:"id"`
+	BottleID  int       `json:"bottle_id"`
+	UserID    uuid.UUID `json:"-"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateReplyRequest struct {
+	Content string `json:"content"`
+}
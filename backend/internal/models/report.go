@@ -0,0 +1,89 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportReason is why a reader reported a bottle.
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonHate       ReportReason = "hate"
+	ReportReasonSexual     ReportReason = "sexual"
+	ReportReasonViolence   ReportReason = "violence"
+	ReportReasonOther      ReportReason = "other"
+)
+
+var ReportReasons = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHate,
+	ReportReasonSexual,
+	ReportReasonViolence,
+	ReportReasonOther,
+}
+
+func (r ReportReason) Valid() bool {
+	return slices.Contains(ReportReasons, r)
+}
+
+// ReportStatus is a report awaiting review, or what the review decided.
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusDismissed ReportStatus = "dismissed" // the bottle was found to be fine
+	ReportStatusRemoved   ReportStatus = "removed"   // the bottle was removed
+	ReportStatusStruck    ReportStatus = "struck"    // the bottle was removed and its author given a strike
+)
+
+// ReportResolutions lists the decisions a moderator can resolve a report with.
+var ReportResolutions = []ReportStatus{ReportStatusDismissed, ReportStatusRemoved, ReportStatusStruck}
+
+func (s ReportStatus) Valid() bool {
+	return s == ReportStatusPending || slices.Contains(ReportResolutions, s)
+}
+
+type Report struct {
+	ID         int          `json:"id"`
+	BottleID   int          `json:"bottle_id"`
+	ReporterID uuid.UUID    `json:"reporter_id"`
+	Reason     ReportReason `json:"reason"`
+	Comment    *string      `json:"comment,omitempty"`
+	Status     ReportStatus `json:"status"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty"`
+	ResolvedBy *uuid.UUID   `json:"resolved_by,omitempty"`
+}
+
+type CreateReportRequest struct {
+	Reason  ReportReason `json:"reason"`
+	Comment *string      `json:"comment,omitempty"`
+}
+
+type ResolveReportRequest struct {
+	Resolution ReportStatus `json:"resolution"`
+	// Reason is the moderator's explanation, kept in the audit log.
+	Reason *string `json:"reason,omitempty"`
+}
+
+type ListReportsRequest struct {
+	Status   *ReportStatus `query:"status"`
+	BottleID *int          `query:"bottle_id"`
+	// ReporterID limits the list to one reader's reports.
+	ReporterID *uuid.UUID `query:"-"`
+}
+
+// Strike counts against an author whose bottle was removed after being reported.
+type Strike struct {
+	ID        int        `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	BottleID  *int       `json:"bottle_id,omitempty"`
+	IssuedBy  *uuid.UUID `json:"issued_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type SearchBottlesRequest struct {
+	Query   string     `query:"q"`
+	OceanID *int       `query:"ocean_id,omitempty"`
+	TagID   *int       `query:"tag_id,omitempty"`
+	From    *time.Time `query:"-"`
+	To      *time.Time `query:"-"`
+}
+
+// SearchResult is a bottle matching a search, with its relevance and the matched terms
+// wrapped in <mark></mark> in Highlight.
+type SearchResult struct {
+	Bottle
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+}
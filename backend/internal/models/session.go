@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a sign-in, identified as the identity provider identifies it, and kept across
+// refreshes.
+type Session struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"-"`
+	RememberMe  bool       `json:"remember_me"`
+	UserAgent   *string    `json:"user_agent,omitempty"`
+	IPAddress   *string    `json:"ip_address,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RefreshedAt time.Time  `json:"refreshed_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	// Current marks the session the request listing sessions was made with.
+	Current bool `json:"current" db:"-"`
+}
+
+type CreateSessionRequest struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	RememberMe       bool
+	RefreshTokenHash string
+	UserAgent        *string
+	IPAddress        *string
+}
+
+// RefreshToken is a refresh token handed to a session, known only by its hash.
+type RefreshToken struct {
+	TokenHash string     `json:"-"`
+	SessionID uuid.UUID  `json:"session_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
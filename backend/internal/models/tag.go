@@ -1,12 +1,48 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Tag struct {
 	ID    int     `json:"id"`
 	Name  *string `json:"name,omitempty"`
 	Color *string `json:"color,omitempty"`
 }
 
+// TagSource is where a bottle's tag came from.
+type TagSource string
+
+const (
+	TagSourceWriter    TagSource = "writer"    // chosen by whoever threw the bottle
+	TagSourceSuggested TagSource = "suggested" // applied by the tag classifier
+	TagSourceDefault   TagSource = "default"   // fallen back on when neither had one
+)
+
 type GetTagsRequest struct {
 	IncludeDefault *bool   `query:"include_default,omitempty"`
 	Name           *string `query:"name,omitempty"`
 }
+
+// TagModel is a trained tag classifier, serialized by the tagging package, along with its
+// report on the bottles held out of training.
+type TagModel struct {
+	ID        int             `json:"id"`
+	Model     json.RawMessage `json:"-"`
+	Report    json.RawMessage `json:"report"`
+	Examples  int             `json:"examples"`
+	TrainedAt time.Time       `json:"trained_at"`
+}
+
+// TagSuggestion is a tag the tag classifier reads in a bottle's content.
+type TagSuggestion struct {
+	Tag
+	Score float64 `json:"score"`
+	// Confident suggestions are applied to bottles thrown without a tag.
+	Confident bool `json:"confident"`
+}
+
+type SuggestTagsRequest struct {
+	Content string `json:"content"`
+}
@@ -1,10 +1,77 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	ID        uuid.UUID `json:"id"`
 	FirstName *string   `json:"first_name,omitempty"`
 	LastName  *string   `json:"last_name,omitempty"`
 	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	// DisplayName is the pseudonym that signs the user's bottles unless they sign them otherwise.
+	DisplayName *string `json:"display_name,omitempty"`
+	Bio         *string `json:"bio,omitempty"`
+	AvatarKey   *string `json:"-"`
+	// Avatar is where the avatar named by AvatarKey is served from.
+	Avatar *Avatar `json:"avatar,omitempty" db:"-"`
+	// Languages are those the user reads; bottles they catch are limited to them unless empty.
+	Languages []Language `json:"languages,omitempty"`
+}
+
+type Avatar struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// UpdateProfileRequest changes the fields that are set; setting one to "" clears it.
+type UpdateProfileRequest struct {
+	FirstName   *string `json:"first_name,omitempty"`
+	LastName    *string `json:"last_name,omitempty"`
+	DisplayName *string `json:"display_name,omitempty"`
+	Bio         *string `json:"bio,omitempty"`
+	// Languages replaces the user's languages when set; setting it to [] reads every language.
+	Languages *[]Language `json:"languages,omitempty"`
+}
+
+// Role is what a user may do. Each role may do everything the roles before it in Roles may.
+type Role string
+
+const (
+	RoleUser      Role = "user"      // reads, throws and catches bottles
+	RoleModerator Role = "moderator" // also reviews bottles and reports
+	RoleAdmin     Role = "admin"     // also manages tags, users and their roles
+)
+
+// Roles lists the roles from least to most privileged.
+var Roles = []Role{RoleUser, RoleModerator, RoleAdmin}
+
+func (r Role) Valid() bool {
+	return slices.Contains(Roles, r)
+}
+
+// Includes reports whether the role may do everything other may.
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && slices.Index(Roles, r) >= slices.Index(Roles, other)
+}
+
+// RoleChange is an audit record of a user's role being changed.
+type RoleChange struct {
+	ID      int       `json:"id"`
+	UserID  uuid.UUID `json:"user_id"`
+	OldRole Role      `json:"old_role"`
+	NewRole Role      `json:"new_role"`
+	// ChangedBy is the admin who made the change, if it was made through the API.
+	ChangedBy *uuid.UUID `json:"changed_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type SetRoleRequest struct {
+	Role Role `json:"role"`
+	// Reason is the admin's explanation, kept in the audit log.
+	Reason *string `json:"reason,omitempty"`
 }
@@ -0,0 +1,17 @@
+// Package mood reads the tone of a bottle, so that readers can fish for something hopeful
+// rather than whatever comes up.
+package mood
+
+import "hackmit/internal/models"
+
+// Result is how a text reads: its mood, and a sentiment score from -1 (bleakest) to 1
+// (brightest).
+type Result struct {
+	Mood  models.Mood
+	Score float64
+}
+
+// Classifier reads the mood of a text. It must work offline, as it runs on every bottle thrown.
+type Classifier interface {
+	Classify(text string) Result
+}
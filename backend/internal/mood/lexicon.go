@@ -0,0 +1,184 @@
+package mood
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"hackmit/internal/models"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//go:embed lexicon.txt
+var lexiconFile string
+
+const (
+	// negationWindow is how many words after a negation it applies to, as in "not very happy".
+	negationWindow = 3
+	// negationFactor is how a negated word weighs: "not happy" is unhappy, but less so than sad.
+	negationFactor = -0.75
+	// intensifierFactor is how much an intensifier strengthens the next word with a valence.
+	intensifierFactor = 1.5
+	// normalization squashes the summed valence into (-1, 1); the larger, the more words it
+	// takes to reach either end.
+	normalization = 15
+	// leaningThreshold is how far from 0 the score of a text whose words suggest no mood must be
+	// for it to read as joyful or sad rather than neutral.
+	leaningThreshold = 0.3
+)
+
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "nothing": true, "nobody": true, "nowhere": true,
+	"without": true, "hardly": true, "cannot": true, "cant": true, "dont": true, "doesnt": true,
+	"didnt": true, "isnt": true, "arent": true, "wasnt": true, "werent": true, "wont": true,
+	"wouldnt": true, "couldnt": true, "shouldnt": true, "havent": true, "hasnt": true, "aint": true,
+}
+
+var intensifiers = map[string]bool{
+	"very": true, "so": true, "really": true, "extremely": true, "totally": true, "truly": true,
+	"incredibly": true, "super": true, "absolutely": true, "completely": true, "deeply": true,
+}
+
+type entry struct {
+	valence float64
+	mood    models.Mood
+}
+
+// Lexicon classifies a text by the words in it, from a word list built into the binary.
+type Lexicon struct {
+	words map[string]entry
+	// stems match any word starting with them, longest first.
+	stems []string
+}
+
+// NewLexicon loads the built-in word list.
+func NewLexicon() *Lexicon {
+	lexicon, err := parseLexicon(strings.NewReader(lexiconFile))
+	if err != nil {
+		panic(fmt.Sprintf("mood: built-in lexicon: %v", err))
+	}
+	return lexicon
+}
+
+func parseLexicon(r io.Reader) (*Lexicon, error) {
+	l := &Lexicon{words: map[string]entry{}}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: want word, valence and mood separated by tabs", n)
+		}
+		valence, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || valence < -4 || valence > 4 {
+			return nil, fmt.Errorf("line %d: valence %q is not a number from -4 to 4", n, fields[1])
+		}
+		mood := models.Mood(fields[2])
+		if !mood.Valid() {
+			return nil, fmt.Errorf("line %d: unknown mood %q", n, fields[2])
+		}
+
+		word := strings.ToLower(fields[0])
+		if _, ok := l.words[word]; ok {
+			return nil, fmt.Errorf("line %d: %q is listed twice", n, fields[0])
+		}
+		l.words[word] = entry{valence, mood}
+		if stem, ok := strings.CutSuffix(word, "*"); ok {
+			l.stems = append(l.stems, stem)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The longest stem is the most specific
+	slices.SortFunc(l.stems, func(a, b string) int { return len(b) - len(a) })
+	return l, nil
+}
+
+func (l *Lexicon) lookup(word string) (entry, bool) {
+	if e, ok := l.words[word]; ok && !strings.HasSuffix(word, "*") {
+		return e, true
+	}
+	for _, stem := range l.stems {
+		if strings.HasPrefix(word, stem) {
+			return l.words[stem+"*"], true
+		}
+	}
+	return entry{}, false
+}
+
+// Classify sums the valence of the words in text, turned around by negations and strengthened
+// by intensifiers. The mood is the one its words suggest most strongly; negated words suggest
+// none, so "not happy" reads as a leaning rather than as joyful.
+func (l *Lexicon) Classify(text string) Result {
+	var sum float64
+	votes := map[models.Mood]float64{}
+	negated, boost := 0, 1.0
+
+	for _, word := range words(text) {
+		switch {
+		case negations[word]:
+			negated = negationWindow
+			continue
+		case intensifiers[word]:
+			boost = intensifierFactor
+			continue
+		}
+
+		if e, ok := l.lookup(word); ok {
+			valence := e.valence * boost
+			if negated > 0 {
+				valence *= negationFactor
+			} else {
+				votes[e.mood] += math.Abs(valence)
+			}
+			sum += valence
+			boost = 1
+		}
+		if negated > 0 {
+			negated--
+		}
+	}
+
+	score := sum / math.Sqrt(sum*sum+normalization)
+	result := Result{Mood: models.MoodNeutral, Score: math.Round(score*1000) / 1000}
+
+	best := 0.0
+	for _, mood := range models.Moods {
+		if votes[mood] > best {
+			result.Mood, best = mood, votes[mood]
+		}
+	}
+	if best == 0 {
+		switch {
+		case score >= leaningThreshold:
+			result.Mood = models.MoodJoyful
+		case score <= -leaningThreshold:
+			result.Mood = models.MoodSad
+		}
+	}
+	return result
+}
+
+var apostrophes = strings.NewReplacer("'", "", "’", "")
+
+// words splits text into lowercase words, dropping apostrophes so that "don't" is "dont".
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+	for i, field := range fields {
+		fields[i] = apostrophes.Replace(field)
+	}
+	return fields
+}
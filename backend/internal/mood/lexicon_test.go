@@ -0,0 +1,73 @@
+package mood
+
+import (
+	"hackmit/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	lexicon := NewLexicon()
+
+	tests := []struct {
+		text string
+		mood models.Mood
+		sign int
+	}{
+		{"I hope tomorrow will be brighter", models.MoodHopeful, 1},
+		{"Best day ever, I'm so happy!", models.MoodJoyful, 1},
+		{"Watching the waves, feeling calm and at peace", models.MoodCalm, 1},
+		{"I miss her every day and I cried again", models.MoodSad, -1},
+		{"So angry, this is ridiculous and unfair", models.MoodAngry, -1},
+		{"Really nervous about the exam, can't stop worrying", models.MoodAnxious, -1},
+		{"The boat is blue.", models.MoodNeutral, 0},
+		{"", models.MoodNeutral, 0},
+		// Negated words lean the other way without suggesting a mood of their own
+		{"I am not happy", models.MoodSad, -1},
+		{"not bad at all", models.MoodJoyful, 1},
+		// Whole words take precedence over stems
+		{"everything feels hopeless", models.MoodSad, -1},
+		{"I painted the fence", models.MoodNeutral, 0},
+	}
+	for _, test := range tests {
+		got := lexicon.Classify(test.text)
+		if got.Mood != test.mood {
+			t.Errorf("Classify(%q) mood = %s, want %s", test.text, got.Mood, test.mood)
+		}
+		if sign(got.Score) != test.sign {
+			t.Errorf("Classify(%q) score = %v, want its sign to be %d", test.text, got.Score, test.sign)
+		}
+		if got.Score <= -1 || got.Score >= 1 {
+			t.Errorf("Classify(%q) score = %v, want it within (-1, 1)", test.text, got.Score)
+		}
+	}
+
+	// Intensifiers strengthen what follows
+	if plain, very := lexicon.Classify("good"), lexicon.Classify("very good"); very.Score <= plain.Score {
+		t.Errorf("very good scored %v, good %v", very.Score, plain.Score)
+	}
+}
+
+func TestParseLexicon(t *testing.T) {
+	for _, bad := range []string{
+		"happy\t3",
+		"happy\tvery\tjoyful",
+		"happy\t5\tjoyful",
+		"happy\t3\tecstatic",
+		"happy\t3\tjoyful\nhappy\t2\tjoyful",
+	} {
+		if _, err := parseLexicon(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseLexicon(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// Notifier turns things that happen to bottles into notifications for the people involved.
+// Notifications are best effort: failures are logged and never fail the triggering request.
+type Notifier struct {
+	notificationRepository storage.NotificationRepository
+	blockRepository        storage.BlockRepository
+}
+
+func NewNotifier(notificationRepository storage.NotificationRepository, blockRepository storage.BlockRepository) *Notifier {
+	return &Notifier{
+		notificationRepository,
+		blockRepository,
+	}
+}
+
+// BottleCaught tells the author that a reader found their bottle. Catches are batched per bottle per day.
+func (n *Notifier) BottleCaught(ctx context.Context, bottle models.Bottle, readerID uuid.UUID) {
+	if bottle.UserID == nil || *bottle.UserID == readerID || n.blocked(ctx, *bottle.UserID, readerID) {
+		return
+	}
+
+	if err := n.notificationRepository.RecordCatch(ctx, *bottle.UserID, bottle.ID); err != nil {
+		slog.Error("failed to notify author of catch", "bottle_id", bottle.ID, "error", err)
+	}
+}
+
+// BottleReplied tells the author that a reader replied to their bottle.
+func (n *Notifier) BottleReplied(ctx context.Context, bottle models.Bottle, replierID uuid.UUID) {
+	if bottle.UserID == nil || *bottle.UserID == replierID || n.blocked(ctx, *bottle.UserID, replierID) {
+		return
+	}
+
+	if err := n.notificationRepository.CreateNotification(ctx, *bottle.UserID, models.NotificationBottleReplied, &bottle.ID, nil); err != nil {
+		slog.Error("failed to notify author of reply", "bottle_id", bottle.ID, "error", err)
+	}
+}
+
+// BottleModerated tells the author about a moderation decision, reported as the bottle's new status.
+func (n *Notifier) BottleModerated(ctx context.Context, bottle models.Bottle) {
+	if bottle.UserID == nil {
+		return
+	}
+
+	status := string(bottle.Status)
+	if err := n.notificationRepository.CreateNotification(ctx, *bottle.UserID, models.NotificationBottleModerated, &bottle.ID, &status); err != nil {
+		slog.Error("failed to notify author of moderation decision", "bottle_id", bottle.ID, "error", err)
+	}
+}
+
+// ReportResolved tells a reader what came of their report.
+func (n *Notifier) ReportResolved(ctx context.Context, report models.Report) {
+	resolution := string(report.Status)
+	if err := n.notificationRepository.CreateNotification(ctx, report.ReporterID, models.NotificationReportResolved, &report.BottleID, &resolution); err != nil {
+		slog.Error("failed to notify reporter of resolution", "report_id", report.ID, "error", err)
+	}
+}
+
+// blocked reports whether either user has blocked the other, in which case neither hears
+// about the other's doings. When that can't be told, the notification is not sent.
+func (n *Notifier) blocked(ctx context.Context, userID uuid.UUID, otherID uuid.UUID) bool {
+	blocked, err := n.blockRepository.IsBlocked(ctx, userID, otherID)
+	if err != nil {
+		slog.Error("failed to check blocks before notifying", "user_id", userID, "error", err)
+		return true
+	}
+	return blocked
+}
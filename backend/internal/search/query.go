@@ -0,0 +1,227 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// Query is a parsed user search. Terms must all match, except that a term written after OR
+// starts a new alternative: `a b OR c` finds bottles matching both a and b, or c.
+type Query struct {
+	Terms []Term
+}
+
+// Term is a single word, prefix* or "quoted phrase" in a query.
+type Term struct {
+	Words  []string // lowercased; more than one means the words must appear in order
+	Prefix bool     // the last word also matches longer words starting with it
+	Negate bool     // the term must not match
+	Or     bool     // the term is joined to the previous one by OR rather than AND
+}
+
+// Parse reads a search supporting "quoted phrases", prefix* matches, -excluded words and OR
+// between terms. Only letters and digits are kept from each word.
+func Parse(search string) Query {
+	var query Query
+	rest := search
+	or := false
+
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			break
+		}
+
+		term := Term{}
+		if rest[0] == '-' {
+			term.Negate = true
+			rest = rest[1:]
+		}
+
+		if strings.HasPrefix(rest, `"`) {
+			phrase := rest[1:]
+			rest = ""
+			if end := strings.Index(phrase, `"`); end >= 0 {
+				phrase, rest = phrase[:end], phrase[end+1:]
+			}
+			term.Words = Lexemes(phrase)
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			word := rest[:end]
+			rest = rest[end:]
+
+			if word == "OR" && !term.Negate {
+				or = true
+				continue
+			}
+
+			term.Words = Lexemes(word)
+			term.Prefix = strings.HasSuffix(word, "*")
+		}
+
+		if len(term.Words) == 0 {
+			continue
+		}
+
+		term.Or = or && len(query.Terms) > 0
+		or = false
+		query.Terms = append(query.Terms, term)
+	}
+
+	return query
+}
+
+// Empty reports whether the search had no usable words.
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0
+}
+
+// TSQuery renders the query in Postgres to_tsquery syntax. Only letters and digits reach it,
+// so users can't inject tsquery operators of their own.
+func (q Query) TSQuery() string {
+	var query strings.Builder
+
+	for i, term := range q.Terms {
+		words := append([]string(nil), term.Words...)
+		if term.Prefix {
+			words[len(words)-1] += ":*"
+		}
+
+		rendered := strings.Join(words, " <-> ")
+		if len(words) > 1 {
+			rendered = "(" + rendered + ")"
+		}
+		if term.Negate {
+			rendered = "!" + rendered
+		}
+
+		if i > 0 {
+			if term.Or {
+				query.WriteString(" | ")
+			} else {
+				query.WriteString(" & ")
+			}
+		}
+		query.WriteString(rendered)
+	}
+
+	return query.String()
+}
+
+// Match reports whether text satisfies the query, and how many times its wanted terms occur
+// in it. Words are compared as written; there is no stemming as there is in Postgres.
+func (q Query) Match(text string) (bool, int) {
+	words := Lexemes(text)
+	matched := false
+	hits := 0
+
+	alternative := true
+	for i, term := range q.Terms {
+		if i > 0 && term.Or {
+			matched = matched || alternative
+			alternative = true
+		}
+
+		count := term.occurrences(words)
+		if term.Negate {
+			alternative = alternative && count == 0
+		} else {
+			alternative = alternative && count > 0
+			hits += count
+		}
+	}
+	matched = matched || alternative
+
+	if !matched {
+		return false, 0
+	}
+	return true, hits
+}
+
+// Highlight HTML-escapes text and wraps every word belonging to a wanted term in highlight markers.
+func (q Query) Highlight(text string) string {
+	var highlighted strings.Builder
+
+	for text != "" {
+		start := strings.IndexFunc(text, isWordRune)
+		if start < 0 {
+			highlighted.WriteString(html.EscapeString(text))
+			break
+		}
+		end := strings.IndexFunc(text[start:], func(r rune) bool { return !isWordRune(r) })
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += start
+		}
+
+		highlighted.WriteString(html.EscapeString(text[:start]))
+		word := text[start:end]
+		if q.wants(strings.ToLower(word)) {
+			highlighted.WriteString(HighlightStart + html.EscapeString(word) + HighlightStop)
+		} else {
+			highlighted.WriteString(html.EscapeString(word))
+		}
+		text = text[end:]
+	}
+
+	return highlighted.String()
+}
+
+func (q Query) wants(word string) bool {
+	for _, term := range q.Terms {
+		if term.Negate {
+			continue
+		}
+		for i, want := range term.Words {
+			if word == want || term.Prefix && i == len(term.Words)-1 && strings.HasPrefix(word, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t Term) occurrences(words []string) int {
+	count := 0
+	for i := 0; i+len(t.Words) <= len(words); i++ {
+		if t.matchesAt(words, i) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t Term) matchesAt(words []string, at int) bool {
+	for i, want := range t.Words {
+		word := words[at+i]
+		if t.Prefix && i == len(t.Words)-1 {
+			if !strings.HasPrefix(word, want) {
+				return false
+			}
+		} else if word != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Lexemes splits text into lowercase words of letters and digits.
+func Lexemes(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
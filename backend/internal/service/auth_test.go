@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"hackmit/internal/auth"
+	"hackmit/internal/config"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRefreshTokenStaysInItsCookie(t *testing.T) {
+	app := newTestApp(t)
+	credentials := map[string]any{"email": "castaway@example.com", "password": "correct horse battery"}
+	res := app.request(t, anonymous, http.MethodPost, "/api/v1/auth/signup", credentials)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("signing up: %d %s", res.StatusCode, res.body)
+	}
+	if strings.Contains(string(res.body), "refresh_token") {
+		t.Errorf("sign up = %s, want no refresh token in the body", res.body)
+	}
+	c := &client{cookies: res.Cookies()}
+
+	var refreshToken string
+	for _, cookie := range c.cookies {
+		if cookie.Name == auth.RefreshTokenCookie {
+			refreshToken = cookie.Value
+			if !cookie.HttpOnly {
+				t.Errorf("refresh token cookie isn't HttpOnly")
+			}
+		}
+	}
+	if refreshToken == "" {
+		t.Fatalf("signing up set no refresh token cookie")
+	}
+
+	res = app.request(t, c, http.MethodPost, "/api/v1/auth/refresh", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("refreshing: %d %s", res.StatusCode, res.body)
+	}
+	if strings.Contains(string(res.body), "refresh_token") {
+		t.Errorf("refresh = %s, want no refresh token in the body", res.body)
+	}
+	var rotated string
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == auth.RefreshTokenCookie {
+			rotated = cookie.Value
+		}
+	}
+	if rotated == "" || rotated == refreshToken {
+		t.Errorf("refresh didn't rotate the refresh token cookie")
+	}
+
+	res = app.request(t, anonymous, http.MethodPost, "/api/v1/auth/login", credentials)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("signing in: %d %s", res.StatusCode, res.body)
+	}
+	if strings.Contains(string(res.body), "refresh_token") {
+		t.Errorf("sign in = %s, want no refresh token in the body", res.body)
+	}
+}
+
+func TestUnrecordedSessionsAreTurnedAway(t *testing.T) {
+	app := newTestApp(t)
+	credentials := map[string]any{"email": "stowaway@example.com", "password": "correct horse battery"}
+	if res := app.request(t, anonymous, http.MethodPost, "/api/v1/auth/signup", credentials); res.StatusCode != http.StatusCreated {
+		t.Fatalf("signing up: %d %s", res.StatusCode, res.body)
+	}
+
+	// The identity provider hands out a session the API never recorded, so it can't be revoked
+	cfg, err := config.Load(context.Background(), false)
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	provider, err := auth.NewProvider(cfg, app.Repo.Identity)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	session, err := provider.Login(context.Background(), "stowaway@example.com", "correct horse battery")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	c := &client{cookies: []*http.Cookie{{Name: auth.AccessTokenCookie, Value: session.AccessToken}}}
+	if res := app.request(t, c, http.MethodGet, "/api/v1/me/catches", nil); res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unrecorded session: %d %s, want 401", res.StatusCode, res.body)
+	}
+}
+
+func TestSignUpErrorsKeepTheirStatus(t *testing.T) {
+	app := newTestApp(t)
+	credentials := map[string]any{"email": "castaway@example.com", "password": "correct horse battery"}
+	if res := app.request(t, anonymous, http.MethodPost, "/api/v1/auth/signup", credentials); res.StatusCode != http.StatusCreated {
+		t.Fatalf("signing up: %d %s", res.StatusCode, res.body)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		email  string
+		status int
+	}{
+		{"taken email", "castaway@example.com", http.StatusConflict},
+		{"invalid email", "castaway", http.StatusBadRequest},
+	} {
+		res := app.request(t, anonymous, http.MethodPost, "/api/v1/auth/signup", map[string]any{"email": tt.email, "password": "correct horse battery"})
+		if res.StatusCode != tt.status || strings.Contains(string(res.body), "Signup request failed") {
+			t.Errorf("signing up with a %s: %d %s, want %d", tt.name, res.StatusCode, res.body, tt.status)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackmit/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBottlesStayAnonymous(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	author, reader, moderator := app.signUp(t), app.signUp(t), app.signUp(t)
+	if _, err := app.Repo.User.SetUserRole(ctx, moderator.id, models.RoleModerator, nil); err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	ocean, err := app.Repo.Ocean.GetDefaultOcean(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultOcean: %v", err)
+	}
+
+	res := app.request(t, author, http.MethodPost, "/api/v1/bottle/", map[string]any{"content": "a message for whoever finds it"})
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("throwing a bottle: %d %s", res.StatusCode, res.body)
+	}
+	var thrown models.Bottle
+	res.decode(t, &thrown)
+
+	for _, path := range []string{
+		fmt.Sprintf("/api/v1/bottle/random?ocean_id=%d", ocean.ID),
+		fmt.Sprintf("/api/v1/bottle/?ocean_id=%d", ocean.ID),
+		"/api/v1/me/catches",
+	} {
+		res := app.request(t, reader, http.MethodGet, path, nil)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, res.StatusCode, res.body)
+		}
+		if strings.Contains(string(res.body), author.id.String()) || strings.Contains(string(res.body), "user_id") {
+			t.Errorf("GET %s = %s, which gives the author away", path, res.body)
+		}
+	}
+
+	// Nor can a reader list what one author has written
+	if res := app.request(t, moderator, http.MethodPost, "/api/v1/bottle/", map[string]any{"content": "another message"}); res.StatusCode != http.StatusOK {
+		t.Fatalf("throwing a bottle: %d %s", res.StatusCode, res.body)
+	}
+	res = app.request(t, reader, http.MethodGet, fmt.Sprintf("/api/v1/bottle/?ocean_id=%d&user_id=%s", ocean.ID, author.id), nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("listing bottles by author: %d %s", res.StatusCode, res.body)
+	}
+	var listed []models.Bottle
+	res.decode(t, &listed)
+	if len(listed) != 2 {
+		t.Errorf("listing bottles by author = %s, want the filter ignored", res.body)
+	}
+
+	res = app.request(t, moderator, http.MethodGet, fmt.Sprintf("/api/v1/admin/bottles/%d", thrown.ID), nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("moderator getting the bottle: %d %s", res.StatusCode, res.body)
+	}
+	var moderated struct{ Bottle models.ModeratedBottle }
+	res.decode(t, &moderated)
+	if moderated.Bottle.AuthorID == nil || *moderated.Bottle.AuthorID != author.id {
+		t.Errorf("moderator's view of the bottle = %s, want its author", res.body)
+	}
+}
+
+func TestCatchesAreRecordedForTheReader(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	author, reader, other := app.signUp(t), app.signUp(t), app.signUp(t)
+	ocean, err := app.Repo.Ocean.GetDefaultOcean(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultOcean: %v", err)
+	}
+	res := app.request(t, author, http.MethodPost, "/api/v1/bottle/", map[string]any{"content": "a message for whoever finds it"})
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("throwing a bottle: %d %s", res.StatusCode, res.body)
+	}
+
+	// Whoever the query says saw the bottle, it is the signed-in reader who caught it
+	for _, c := range []*client{anonymous, reader} {
+		res := app.request(t, c, http.MethodGet, fmt.Sprintf("/api/v1/bottle/random?ocean_id=%d&seen_by_user_id=%s", ocean.ID, other.id), nil)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("catching a bottle: %d %s", res.StatusCode, res.body)
+		}
+	}
+
+	catches := func(c *client) int {
+		res := app.request(t, c, http.MethodGet, "/api/v1/me/catches", nil)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("GET /me/catches: %d %s", res.StatusCode, res.body)
+		}
+		var page struct{ Catches []json.RawMessage }
+		res.decode(t, &page)
+		return len(page.Catches)
+	}
+	if got := catches(other); got != 0 {
+		t.Errorf("catches of the user named in the query = %d, want none", got)
+	}
+	if got := catches(reader); got != 1 {
+		t.Errorf("catches of the signed-in reader = %d, want 1", got)
+	}
+
+	authored, err := app.Repo.Bottle.GetBottlesByUser(ctx, author.id, models.PaginationRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetBottlesByUser: %v", err)
+	}
+	if len(authored) != 1 || authored[0].CatchCount != 1 {
+		t.Errorf("author's bottles = %+v, want one caught once", authored)
+	}
+}
+
+func TestDeletingBottles(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	author, stranger, moderator := app.signUp(t), app.signUp(t), app.signUp(t)
+	if _, err := app.Repo.User.SetUserRole(ctx, moderator.id, models.RoleModerator, nil); err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	throw := func() models.Bottle {
+		res := app.request(t, author, http.MethodPost, "/api/v1/bottle/", map[string]any{"content": "a message for whoever finds it"})
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("throwing a bottle: %d %s", res.StatusCode, res.body)
+		}
+		var bottle models.Bottle
+		res.decode(t, &bottle)
+		return bottle
+	}
+	remove := func(c *client, bottle models.Bottle) int {
+		return app.request(t, c, http.MethodDelete, fmt.Sprintf("/api/v1/bottle/%d", bottle.ID), nil).StatusCode
+	}
+	exists := func(bottle models.Bottle) bool {
+		_, err := app.Repo.Bottle.GetBottleByID(ctx, bottle.ID)
+		return err == nil
+	}
+
+	bottle := throw()
+	if got := remove(anonymous, bottle); got != http.StatusUnauthorized {
+		t.Errorf("anonymous deletion: %d, want 401", got)
+	}
+	if got := remove(stranger, bottle); got != http.StatusForbidden {
+		t.Errorf("deletion by someone else: %d, want 403", got)
+	}
+	if !exists(bottle) {
+		t.Fatal("bottle deleted by someone who may not")
+	}
+	if got := remove(author, bottle); got != http.StatusOK || exists(bottle) {
+		t.Errorf("deletion by its author: %d, still there: %v", got, exists(bottle))
+	}
+
+	bottle = throw()
+	if got := remove(moderator, bottle); got != http.StatusOK || exists(bottle) {
+		t.Errorf("deletion by a moderator: %d, still there: %v", got, exists(bottle))
+	}
+	action := models.AuditBottleDeleted
+	entries, err := app.Repo.Audit.ListAudit(ctx, models.ListAuditRequest{Action: &action}, models.PaginationRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ActorID == nil || *entries[0].ActorID != moderator.id || *entries[0].TargetID != fmt.Sprint(bottle.ID) {
+		t.Errorf("audited deletions = %+v, want the moderator's", entries)
+	}
+}
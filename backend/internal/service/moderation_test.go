@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"hackmit/internal/models"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckingADraftAgreesWithThrowingIt(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	writer := app.signUp(t)
+
+	// The writer's own ocean masks profanity, while the default ocean turns it away
+	cove, err := app.Repo.Ocean.GetOceanByUser(ctx, writer.id)
+	if err != nil {
+		t.Fatalf("GetOceanByUser: %v", err)
+	}
+	enabled := true
+	if _, err := app.Repo.Ocean.UpdateOceanSettings(ctx, cove.ID, models.UpdateOceanSettingsRequest{MaskProfanity: &enabled}); err != nil {
+		t.Fatalf("UpdateOceanSettings: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		draft   map[string]any
+		verdict string
+		masked  bool
+	}{
+		{"tagless", map[string]any{"content": "the tide came in slowly this morning"}, "allowed", false},
+		{"blocked", map[string]any{"content": "you should die"}, "blocked", false},
+		{"profanity", map[string]any{"content": "well shit, what a day"}, "allowed", false},
+		{"masking ocean", map[string]any{"content": "well shit, what a day", "personal": true}, "allowed", true},
+		{"masked author", map[string]any{"content": "a quiet evening", "author": "shit happens", "personal": true}, "allowed", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := app.request(t, writer, http.MethodPost, "/api/v1/moderation/check", tt.draft)
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("checking the draft: %d %s", res.StatusCode, res.body)
+			}
+			var check models.ModerationCheck
+			res.decode(t, &check)
+			masked := slices.ContainsFunc(check.Spans, func(span models.ModerationSpan) bool { return span.Masked })
+			if check.Verdict != tt.verdict || masked != tt.masked {
+				t.Errorf("check = %s, want verdict %s with masked spans %v", res.body, tt.verdict, tt.masked)
+			}
+
+			res = app.request(t, writer, http.MethodPost, "/api/v1/bottle/", tt.draft)
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("throwing the bottle: %d %s", res.StatusCode, res.body)
+			}
+			blocked := strings.HasPrefix(string(res.body), "Content blocked")
+			if blocked != (check.Verdict == "blocked") {
+				t.Fatalf("check said %s, but throwing the bottle gave %s", check.Verdict, res.body)
+			}
+			if blocked {
+				return
+			}
+
+			var bottle models.Bottle
+			res.decode(t, &bottle)
+			if bottle.Language == nil || *bottle.Language != check.Language {
+				t.Errorf("bottle language = %v, check said %s", bottle.Language, check.Language)
+			}
+			// Whatever the check would mask is starred out of the bottle, and nothing else is
+			author, _ := tt.draft["author"].(string)
+			changed := bottle.Content != tt.draft["content"] || author != "" && (bottle.Author == nil || *bottle.Author != author)
+			if changed != masked {
+				t.Errorf("bottle = %s, check said masked spans %v", res.body, masked)
+			}
+		})
+	}
+}
+
+func TestProfilesAreScreenedLikeBottles(t *testing.T) {
+	app := newTestApp(t)
+	writer := app.signUp(t)
+
+	for _, field := range []string{"display_name", "bio"} {
+		res := app.request(t, writer, http.MethodPatch, "/api/v1/me", map[string]any{field: "you should die"})
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("setting a hateful %s: %d %s, want 400", field, res.StatusCode, res.body)
+		}
+	}
+
+	// Display names set before they were screened are still screened as the default author
+	name := "you should die"
+	if _, err := app.Repo.User.UpdateUserProfile(context.Background(), writer.id, models.UpdateProfileRequest{DisplayName: &name}); err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	res := app.request(t, writer, http.MethodPost, "/api/v1/bottle/", map[string]any{"content": "a quiet evening"})
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(string(res.body), "Content blocked") {
+		t.Errorf("throwing under a hateful display name: %d %s, want it blocked", res.StatusCode, res.body)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPersonalOceansAreOnlyStreamedToTheirOwners(t *testing.T) {
+	app := newTestApp(t)
+	owner, stranger := app.signUp(t), app.signUp(t)
+	cove, err := app.Repo.Ocean.GetOceanByUser(context.Background(), owner.id)
+	if err != nil {
+		t.Fatalf("GetOceanByUser: %v", err)
+	}
+
+	res := app.request(t, stranger, http.MethodGet, fmt.Sprintf("/api/v1/oceans/%d/stream", cove.ID), nil)
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("streaming someone else's personal ocean: %d %s, want 403", res.StatusCode, res.body)
+	}
+}
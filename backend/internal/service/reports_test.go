@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestDismissingReports(t *testing.T) {
+	app := newTestApp(t, "MODERATION_REPORT_THRESHOLD", "1")
+	ctx := context.Background()
+	author, reader, moderator := app.signUp(t), app.signUp(t), app.signUp(t)
+	if _, err := app.Repo.User.SetUserRole(ctx, moderator.id, models.RoleModerator, nil); err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	defaultTag, err := app.Repo.Tag.GetDefaultTag(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultTag: %v", err)
+	}
+	throw := func(content string, status models.BottleStatus) *models.Bottle {
+		bottle, err := app.Repo.Bottle.CreateBottle(ctx, models.CreateBottleRequest{Content: content, TagID: &defaultTag.ID, UserID: &author.id, Status: &status})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		return bottle
+	}
+	status := func(bottle *models.Bottle) models.BottleStatus {
+		got, err := app.Repo.Bottle.GetBottleByID(ctx, bottle.ID)
+		if err != nil {
+			t.Fatalf("GetBottleByID: %v", err)
+		}
+		return got.Status
+	}
+	dismiss := func(reportID int) {
+		res := app.request(t, moderator, http.MethodPost, fmt.Sprintf("/api/v1/admin/reports/%d/resolve", reportID), map[string]any{"resolution": models.ReportStatusDismissed})
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("dismissing report %d: %d %s", reportID, res.StatusCode, res.body)
+		}
+	}
+	spam := map[string]any{"reason": models.ReportReasonSpam}
+
+	// A bottle hidden by its reports is set afloat again when they are dismissed
+	afloat := throw("a bottle readers can catch", models.BottleStatusAfloat)
+	ocean, err := app.Repo.Ocean.GetDefaultOcean(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultOcean: %v", err)
+	}
+	res := app.request(t, reader, http.MethodGet, fmt.Sprintf("/api/v1/bottle/random?ocean_id=%d", ocean.ID), nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("catching a bottle: %d %s", res.StatusCode, res.body)
+	}
+	res = app.request(t, reader, http.MethodPost, fmt.Sprintf("/api/v1/bottle/%d/report", afloat.ID), spam)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("reporting an afloat bottle: %d %s", res.StatusCode, res.body)
+	}
+	var report models.Report
+	res.decode(t, &report)
+	if got := status(afloat); got != models.BottleStatusPending {
+		t.Fatalf("reported bottle is %s, want it hidden", got)
+	}
+	dismiss(report.ID)
+	if got := status(afloat); got != models.BottleStatusAfloat {
+		t.Errorf("bottle after its reports were dismissed is %s, want afloat", got)
+	}
+
+	// A bottle held for review can't be reported, since no reader has caught it
+	held := throw("a bottle held for review", models.BottleStatusPending)
+	res = app.request(t, reader, http.MethodPost, fmt.Sprintf("/api/v1/bottle/%d/report", held.ID), spam)
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("reporting a held bottle: %d %s, want 404", res.StatusCode, res.body)
+	}
+
+	// Dismissing reports on a bottle that was held since it was reported doesn't publish it
+	caught := throw("a bottle caught before it was held", models.BottleStatusAfloat)
+	res = app.request(t, reader, http.MethodGet, fmt.Sprintf("/api/v1/bottle/random?ocean_id=%d", ocean.ID), nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("catching a bottle: %d %s", res.StatusCode, res.body)
+	}
+	filed, err := app.Repo.Report.CreateReport(ctx, caught.ID, reader.id, models.CreateReportRequest{Reason: models.ReportReasonSpam})
+	if err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+	if _, err := app.Repo.Bottle.SetBottleStatus(ctx, caught.ID, models.BottleStatusPending); err != nil {
+		t.Fatalf("SetBottleStatus: %v", err)
+	}
+	target := fmt.Sprint(caught.ID)
+	_, err = app.Repo.Audit.RecordAudit(ctx, models.AuditEntry{
+		Action:     models.AuditBottleModerated,
+		TargetType: models.AuditTargetBottle,
+		TargetID:   &target,
+		Details:    map[string]any{"status": models.BottleStatusPending},
+	})
+	if err != nil {
+		t.Fatalf("RecordAudit: %v", err)
+	}
+	dismiss(filed.ID)
+	if got := status(caught); got != models.BottleStatusPending {
+		t.Errorf("held bottle after a report on it was dismissed is %s, want it still held", got)
+	}
+}
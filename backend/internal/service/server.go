@@ -2,29 +2,58 @@ package service
 
 import (
 	"context"
+	accountDeleter "hackmit/internal/account"
+	"hackmit/internal/audit"
+	authMiddleware "hackmit/internal/auth"
+	"hackmit/internal/avatar"
 	"hackmit/internal/config"
 	errs "hackmit/internal/errs"
+	"hackmit/internal/export"
+	"hackmit/internal/handler/account"
+	"hackmit/internal/handler/admin"
 	"hackmit/internal/handler/auth"
+	"hackmit/internal/handler/block"
 	"hackmit/internal/handler/bottle"
+	"hackmit/internal/handler/notification"
 	"hackmit/internal/handler/ocean"
+	"hackmit/internal/handler/profile"
+	"hackmit/internal/handler/report"
+	"hackmit/internal/handler/session"
 	"hackmit/internal/handler/tag"
+	"hackmit/internal/migrate"
+	"hackmit/internal/models"
+	"hackmit/internal/mood"
+	"hackmit/internal/notify"
 	"hackmit/internal/storage"
+	"hackmit/internal/storage/memory"
 	"hackmit/internal/storage/postgres"
+	"hackmit/internal/stream"
+	"hackmit/internal/supabase"
+	"hackmit/internal/tagging"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	go_json "github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/favicon"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/swagger"
 )
 
 type App struct {
-	Server *fiber.App
-	Repo   *storage.Repository
+	Server      *fiber.App
+	Repo        *storage.Repository
+	Hub         *stream.Hub
+	Deleter     *accountDeleter.Deleter
+	Exporter    *export.Exporter
+	AuditPruner *audit.Pruner
+	Tags        *tagging.Suggester
 }
 
 // Initialize the App union type containing a fiber app, a repository, and a climatiq client.
@@ -32,16 +61,69 @@ func InitApp(config config.Config) *App {
 	ctx := context.Background()
 	repo := postgres.NewRepository(ctx, config.DB)
 
-	app := SetupApp(config, repo)
+	if config.DB.AutoMigrate {
+		migrator, err := migrate.New(repo.GetDB(), supabase.Files)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			log.Fatalf("Failed to migrate the database: %v", err)
+		}
+	}
+
+	hub := stream.NewHub(stream.NewPostgresBroker(repo.GetDB()))
+
+	return newApp(config, repo, hub, newIdentityProvider(config, repo))
+}
+
+// InitMemoryApp is InitApp on an in-memory store that is lost on exit, for running locally
+// without a database.
+func InitMemoryApp(config config.Config) *App {
+	broker := stream.NewLocalBroker()
+	repo := memory.NewRepository(broker)
+	hub := stream.NewHub(broker)
+
+	return newApp(config, repo, hub, newIdentityProvider(config, repo))
+}
+
+// newApp starts the hub and background jobs on the given backend and serves the API on top of
+// them, whichever backend it is.
+func newApp(config config.Config, repo *storage.Repository, hub *stream.Hub, identity authMiddleware.IdentityProvider) *App {
+	hub.Start()
+
+	avatars := avatar.NewStore(config.Uploads.Dir, config.Uploads.MaxAvatarSize)
+	deleter := accountDeleter.NewDeleter(repo.User, identity, avatars, config.Auth.DeletionGrace)
+	deleter.Start()
+	exporter := export.NewExporter(repo, config.Export.Retention)
+	exporter.Start()
+	pruner := audit.NewPruner(repo.Audit, config.Audit.Retention)
+	pruner.Start()
+	tags := tagging.NewSuggester(repo.Tag, config.Tagging.AutoApply, config.Tagging.Refresh)
+	tags.Start()
+
+	app := SetupApp(config, repo, hub, identity, deleter, exporter, avatars, tags)
 
 	return &App{
-		Server: app,
-		Repo:   repo,
+		Server:      app,
+		Repo:        repo,
+		Hub:         hub,
+		Deleter:     deleter,
+		Exporter:    exporter,
+		AuditPruner: pruner,
+		Tags:        tags,
+	}
+}
+
+func newIdentityProvider(config config.Config, repo *storage.Repository) authMiddleware.IdentityProvider {
+	identity, err := authMiddleware.NewProvider(config, repo.Identity)
+	if err != nil {
+		log.Fatalf("Failed to set up the identity provider: %v", err)
 	}
+	return identity
 }
 
 // Setup the fiber app with the specified configuration, database, and climatiq client.
-func SetupApp(config config.Config, repo *storage.Repository) *fiber.App {
+func SetupApp(config config.Config, repo *storage.Repository, hub *stream.Hub, identity authMiddleware.IdentityProvider, deleter *accountDeleter.Deleter, exporter *export.Exporter, avatars *avatar.Store, tags *tagging.Suggester) *fiber.App {
 	app := fiber.New(fiber.Config{
 		JSONEncoder:  go_json.Marshal,
 		JSONDecoder:  go_json.Unmarshal,
@@ -51,6 +133,10 @@ func SetupApp(config config.Config, repo *storage.Repository) *fiber.App {
 	app.Use(recover.New())
 	app.Use(favicon.New())
 	app.Use(compress.New(compress.Config{
+		// Compressing an event stream would buffer it, so live feeds are sent as-is
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
 		Level: compress.LevelBestSpeed,
 	}))
 
@@ -67,6 +153,7 @@ func SetupApp(config config.Config, repo *storage.Repository) *fiber.App {
 	}))
 
 	app.Static("/api", "/app/api")
+	app.Static(avatar.URLPrefix, config.Uploads.Dir)
 
 	app.Get("/swagger/*", swagger.New(swagger.Config{
 		URL:         "/api/openapi.yaml",
@@ -83,42 +170,124 @@ func SetupApp(config config.Config, repo *storage.Repository) *fiber.App {
 		return c.SendStatus(http.StatusOK)
 	})
 
-	SupabaseAuthHandler := auth.NewHandler(config.Supabase, repo.User, repo.Ocean)
+	SupabaseAuthHandler := auth.NewHandler(identity, repo.User, repo.Ocean, repo.Session, repo)
 
 	apiV1.Route("/auth", func(router fiber.Router) {
 		router.Post("/signup", SupabaseAuthHandler.SignUp)
 		router.Post("/login", SupabaseAuthHandler.Login)
+		router.Post("/refresh", SupabaseAuthHandler.Refresh)
 		router.Post("/forgot-password", SupabaseAuthHandler.ForgotPassword)
 		router.Post("/reset-password", SupabaseAuthHandler.ResetPassword)
 		router.Post("/sign-out", SupabaseAuthHandler.SignOut)
-		router.Delete("/delete-account/:id", func(c *fiber.Ctx) error {
-			id := c.Params("id")
-			return SupabaseAuthHandler.DeleteAccount(c, id)
-		})
 	})
 
-	oceanHandler := ocean.NewHandler(repo.Ocean)
+	requireAuth := authMiddleware.Middleware(identity, repo.Session, repo.User)
+	optionalAuth := authMiddleware.OptionalMiddleware(identity, repo.Session, repo.User)
+
+	oceanHandler := ocean.NewHandler(repo.Ocean, repo.Bottle, repo.User, repo.Block, hub)
 
 	apiV1.Route("/oceans", func(router fiber.Router) {
 		router.Get("/", oceanHandler.GetOceans)
 		router.Get("/default", oceanHandler.GetDefaultOcean)
 		router.Get("/personal", oceanHandler.GetRandomPersonalOcean)
 		router.Get("/personal/:id", oceanHandler.GetRandomPersonalOcean)
+		router.Get("/:id/stream", requireAuth, oceanHandler.StreamOcean)
 		router.Get("/:id", oceanHandler.GetOceanByUserID)
 
 	})
 
+	notifier := notify.NewNotifier(repo.Notification, repo.Block)
+
+	bottleHandler := bottle.NewHandler(repo.Bottle, repo.Tag, repo.Ocean, repo.User, repo.Audit, repo, notifier, mood.NewLexicon(), tags)
+
+	// Tags are suggested as writers type, so each writer gets a budget of suggestions a minute
+	suggestLimiter := perMinute(config.Tagging.SuggestLimit, "Too many suggestions, try again in a minute")
+
 	TagHandler := tag.NewHandler(repo.Tag)
 	apiV1.Route("/tags", func(router fiber.Router) {
 		router.Get("/", TagHandler.Get)
+		router.Post("/suggest", optionalAuth, suggestLimiter, bottleHandler.SuggestTags)
 	})
 
-	bottleHandler := bottle.NewHandler(repo.Bottle, repo.Tag, repo.Ocean)
+	reportHandler := report.NewHandler(repo.Report, repo.Bottle, repo, notifier, config.Moderation.ReportThreshold)
 	apiV1.Route("/bottle", func(r fiber.Router) {
-		r.Delete("/:id", bottleHandler.DeleteBottle)
-		r.Post("/", bottleHandler.CreateBottle)
-		r.Get("/", bottleHandler.GetBottles)
-		r.Get("/random", bottleHandler.GetRandom)
+		r.Delete("/:id", requireAuth, bottleHandler.DeleteBottle)
+		r.Post("/", optionalAuth, bottleHandler.CreateBottle)
+		r.Get("/", optionalAuth, bottleHandler.GetBottles)
+		r.Get("/random", optionalAuth, bottleHandler.GetRandom)
+		r.Get("/search", optionalAuth, bottleHandler.SearchBottles)
+		r.Post("/:id/replies", requireAuth, bottleHandler.CreateReply)
+		r.Get("/:id/replies", requireAuth, bottleHandler.GetReplies)
+		r.Post("/:id/report", requireAuth, reportHandler.ReportBottle)
+	})
+
+	// Drafts are checked as writers type, so each writer gets a budget of checks a minute
+	checkLimiter := perMinute(config.Moderation.CheckLimit, "Too many checks, try again in a minute")
+	apiV1.Route("/moderation", func(r fiber.Router) {
+		r.Post("/check", optionalAuth, checkLimiter, bottleHandler.CheckDraft)
+	})
+
+	notificationHandler := notification.NewHandler(repo.Notification)
+	sessionHandler := session.NewHandler(repo.Session)
+	signer, err := export.NewSigner(config.Export, config.Application)
+	if err != nil {
+		log.Fatalf("Failed to set up export links: %v", err)
+	}
+	accountHandler := account.NewHandler(deleter, exporter, signer, repo.User, repo.Export)
+	profileHandler := profile.NewHandler(repo.User, avatars, bottle.Checker())
+	blockHandler := block.NewHandler(repo.Block, repo.Bottle)
+
+	apiV1.Route("/me", func(r fiber.Router) {
+		r.Use(requireAuth)
+		r.Get("/", profileHandler.GetProfile)
+		r.Patch("/", profileHandler.UpdateProfile)
+		r.Put("/avatar", profileHandler.UploadAvatar)
+		r.Delete("/avatar", profileHandler.DeleteAvatar)
+		r.Get("/bottles", bottleHandler.GetMyBottles)
+		r.Get("/catches", bottleHandler.GetCatches)
+		r.Delete("/catches/:id", bottleHandler.ThrowBack)
+		r.Get("/bookmarks", bottleHandler.GetBookmarks)
+		r.Put("/bookmarks/:id", bottleHandler.SaveBookmark)
+		r.Delete("/bookmarks/:id", bottleHandler.DeleteBookmark)
+		r.Get("/notifications", notificationHandler.GetNotifications)
+		r.Get("/notifications/unread-count", notificationHandler.GetUnreadCount)
+		r.Post("/notifications/read-all", notificationHandler.MarkAllRead)
+		r.Post("/notifications/:id/read", notificationHandler.MarkRead)
+		r.Get("/notification-preferences", notificationHandler.GetPreferences)
+		r.Put("/notification-preferences", notificationHandler.UpdatePreferences)
+		r.Get("/sessions", sessionHandler.GetSessions)
+		r.Delete("/sessions", sessionHandler.RevokeAllSessions)
+		r.Delete("/sessions/:id", sessionHandler.RevokeSession)
+		r.Get("/reports", reportHandler.GetMyReports)
+		r.Get("/blocks", blockHandler.GetBlocks)
+		r.Post("/blocks/bottles/:bottleId", blockHandler.BlockBottleAuthor)
+		r.Delete("/blocks/bottles/:bottleId", blockHandler.UnblockBottleAuthor)
+		r.Post("/blocks/:userId", blockHandler.BlockUser)
+		r.Delete("/blocks/:userId", blockHandler.UnblockUser)
+		r.Delete("/account", accountHandler.DeleteAccount)
+		r.Get("/account/deletion", accountHandler.GetDeletion)
+		r.Delete("/account/deletion", accountHandler.CancelDeletion)
+		r.Post("/export", accountHandler.RequestExport)
+		r.Get("/export/:id", accountHandler.GetExport)
+	})
+
+	apiV1.Get("/exports/:id/download", accountHandler.DownloadExport)
+
+	adminHandler := admin.NewHandler(repo.User, repo.Bottle, repo.Ocean, repo.Audit, repo)
+
+	// Moderators review reports; managing users is left to admins
+	apiV1.Route("/admin", func(r fiber.Router) {
+		r.Use(requireAuth, authMiddleware.RequireRole(models.RoleModerator))
+		requireAdmin := authMiddleware.RequireRole(models.RoleAdmin)
+		r.Get("/users/:id", requireAdmin, adminHandler.GetUser)
+		r.Put("/users/:id/role", requireAdmin, adminHandler.SetRole)
+		r.Get("/users/:id/role-changes", requireAdmin, adminHandler.GetRoleChanges)
+		r.Get("/audit", requireAdmin, adminHandler.GetAuditLog)
+		r.Patch("/oceans/:id", requireAdmin, adminHandler.UpdateOceanSettings)
+		r.Get("/bottles/:id", adminHandler.GetBottle)
+		r.Get("/users/:id/strikes", reportHandler.GetStrikes)
+		r.Get("/reports", reportHandler.GetReports)
+		r.Post("/reports/:id/resolve", reportHandler.ResolveReport)
 	})
 
 	// Handle 404 - Route not found
@@ -131,3 +300,21 @@ func SetupApp(config config.Config, repo *storage.Repository) *fiber.App {
 
 	return app
 }
+
+// perMinute limits each signed-in user, or each address for anonymous requests, to max
+// requests a minute, answering those over the limit with message.
+func perMinute(max int, message string) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			if userID, err := authMiddleware.UserID(c); err == nil {
+				return userID.String()
+			}
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return errs.TooManyRequests(message)
+		},
+	})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hackmit/internal/config"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testApp is the API over the in-memory store, for tests that go through its routes.
+type testApp struct {
+	*App
+}
+
+// newTestApp starts the app over an empty in-memory store, with the environment set so that
+// sign-ups work without Supabase. Extra environment variables can be given as name, value pairs.
+func newTestApp(t *testing.T, env ...string) *testApp {
+	t.Helper()
+
+	t.Setenv("AUTH_PROVIDER", "local")
+	t.Setenv("AUTH_JWT_SECRET", "storage-test-secret-of-some-length")
+	t.Setenv("EXPORT_SIGNING_SECRET", "export-test-secret-of-some-length")
+	t.Setenv("UPLOADS_DIR", t.TempDir())
+	for i := 0; i+1 < len(env); i += 2 {
+		t.Setenv(env[i], env[i+1])
+	}
+
+	cfg, err := config.Load(context.Background(), false)
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	app := InitMemoryApp(cfg)
+	t.Cleanup(func() {
+		app.Hub.Close()
+		app.Deleter.Close()
+		app.Exporter.Close()
+		app.AuditPruner.Close()
+		app.Tags.Close()
+	})
+	return &testApp{app}
+}
+
+// client is someone using the API, signed in unless it has no cookies.
+type client struct {
+	id      uuid.UUID
+	cookies []*http.Cookie
+}
+
+// anonymous is a client that hasn't signed in.
+var anonymous = &client{}
+
+// signUp creates an account and returns it signed in.
+func (a *testApp) signUp(t *testing.T) *client {
+	t.Helper()
+
+	body := map[string]any{"email": uuid.NewString() + "@example.com", "password": "correct horse battery", "first_name": "Sea"}
+	res := a.request(t, anonymous, http.MethodPost, "/api/v1/auth/signup", body)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("sign up: %d %s", res.StatusCode, res.body)
+	}
+
+	c := &client{cookies: res.Cookies()}
+	for _, cookie := range c.cookies {
+		if cookie.Name == "user_id" {
+			c.id = uuid.MustParse(cookie.Value)
+		}
+	}
+	return c
+}
+
+type response struct {
+	*http.Response
+	body []byte
+}
+
+// decode reads the response body into v.
+func (r response) decode(t *testing.T, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("decoding %s: %v", r.body, err)
+	}
+}
+
+// request sends body, as JSON unless it is nil, on behalf of the client.
+func (a *testApp) request(t *testing.T, c *client, method, path string, body any) response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encoding %v: %v", body, err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+
+	res, err := a.Server.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	read, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("reading %s %s: %v", method, path, err)
+	}
+	return response{res, read}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSuggestingTagsIsRateLimited(t *testing.T) {
+	app := newTestApp(t, "TAGGING_SUGGEST_LIMIT", "2")
+	writer := app.signUp(t)
+	draft := map[string]any{"content": "waves on the beach at dawn"}
+
+	for i := range 2 {
+		res := app.request(t, writer, http.MethodPost, "/api/v1/tags/suggest", draft)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("suggestion %d: %d %s", i+1, res.StatusCode, res.body)
+		}
+	}
+	if res := app.request(t, writer, http.MethodPost, "/api/v1/tags/suggest", draft); res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("suggestion over the limit: %d %s, want 429", res.StatusCode, res.body)
+	}
+
+	// Others have budgets of their own, and the tags are still listed
+	if res := app.request(t, app.signUp(t), http.MethodPost, "/api/v1/tags/suggest", draft); res.StatusCode != http.StatusOK {
+		t.Errorf("another writer's suggestion: %d %s", res.StatusCode, res.body)
+	}
+	if res := app.request(t, anonymous, http.MethodGet, "/api/v1/tags/", nil); res.StatusCode != http.StatusOK {
+		t.Errorf("listing tags: %d %s", res.StatusCode, res.body)
+	}
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userId uuid.UUID, keepBottles bool, deleteAfter time.Time) (*models.AccountDeletion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return nil, errs.NotFound("account deletion", "user_id", userId.String())
+	}
+	if _, ok := r.store.deletions[userId]; ok {
+		return nil, errs.Conflict("account deletion", "user_id", userId.String())
+	}
+
+	deletion := models.AccountDeletion{
+		UserID:      userId,
+		KeepBottles: keepBottles,
+		RequestedAt: now(),
+		DeleteAfter: deleteAfter.UTC().Truncate(time.Microsecond),
+	}
+	r.store.deletions[userId] = deletion
+	return &deletion, nil
+}
+
+func (r *UserRepository) GetDeletion(ctx context.Context, userId uuid.UUID) (*models.AccountDeletion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deletion, ok := r.store.deletions[userId]
+	if !ok {
+		return nil, errs.NotFound("account deletion", "user_id", userId.String())
+	}
+	return &deletion, nil
+}
+
+func (r *UserRepository) CancelDeletion(ctx context.Context, userId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.deletions[userId]; !ok {
+		return errs.NotFound("account deletion", "user_id", userId.String())
+	}
+	delete(r.store.deletions, userId)
+	return nil
+}
+
+func (r *UserRepository) GetDueDeletions(ctx context.Context, at time.Time) ([]models.AccountDeletion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deletions := []models.AccountDeletion{}
+	for _, deletion := range r.store.deletions {
+		if !deletion.DeleteAfter.After(at) {
+			deletions = append(deletions, deletion)
+		}
+	}
+	slices.SortFunc(deletions, func(a, b models.AccountDeletion) int {
+		return a.DeleteAfter.Compare(b.DeleteAfter)
+	})
+	return deletions, nil
+}
+
+func (r *UserRepository) PurgeUser(ctx context.Context, userId uuid.UUID, keepBottles bool) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return 0, errs.NotFound("user", "id", userId.String())
+	}
+
+	var bottles int64
+	if keepBottles {
+		bottles = r.store.detachBottles(userId)
+	} else {
+		for bottleID, bottle := range r.store.bottles {
+			if bottle.UserID != nil && *bottle.UserID == userId {
+				r.store.deleteBottle(bottleID)
+				bottles++
+			}
+		}
+	}
+
+	for oceanID, ocean := range r.store.oceans {
+		if ocean.UserID != nil && *ocean.UserID == userId {
+			delete(r.store.oceans, oceanID)
+			for key := range r.store.tagOceans {
+				if key.oceanID == oceanID {
+					delete(r.store.tagOceans, key)
+				}
+			}
+		}
+	}
+
+	r.store.deleteUser(userId)
+	return bottles, nil
+}
+
+// detachBottles strips the user's bottles of their author and user_id, and the originals of
+// those that were masked of the author's name as written, returning how many were detached.
+func (s *Store) detachBottles(userId uuid.UUID) int64 {
+	var detached int64
+	for bottleID, bottle := range s.bottles {
+		if bottle.UserID == nil || *bottle.UserID != userId {
+			continue
+		}
+		bottle.Author, bottle.UserID = nil, nil
+		s.bottles[bottleID] = bottle
+		if original, ok := s.originals[bottleID]; ok {
+			original.Author = nil
+			s.originals[bottleID] = original
+		}
+		detached++
+	}
+	return detached
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackmit/internal/models"
+	"slices"
+	"time"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func (r *AuditRepository) RecordAudit(ctx context.Context, entry models.AuditEntry) (*models.AuditEntry, error) {
+	// Details go through JSON as they would through a jsonb column, so that what is read back
+	// doesn't share maps or slices with the caller
+	details, err := roundTrip(entry.Details)
+	if err != nil {
+		return nil, fmt.Errorf("error recording audit entry: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextAuditID++
+	entry.ID = r.store.nextAuditID
+	entry.Details = details
+	entry.CreatedAt = now()
+	r.store.audit[entry.ID] = entry
+	return &entry, nil
+}
+
+func (r *AuditRepository) ListAudit(ctx context.Context, filterParams models.ListAuditRequest, page models.PaginationRequest) ([]models.AuditEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entries := []models.AuditEntry{}
+	for _, entry := range r.store.audit {
+		if filterParams.Action != nil && entry.Action != *filterParams.Action {
+			continue
+		}
+		if filterParams.ActorID != nil && (entry.ActorID == nil || *entry.ActorID != *filterParams.ActorID) {
+			continue
+		}
+		if filterParams.TargetType != nil && entry.TargetType != *filterParams.TargetType {
+			continue
+		}
+		if filterParams.TargetID != nil && (entry.TargetID == nil || *entry.TargetID != *filterParams.TargetID) {
+			continue
+		}
+		if filterParams.Since != nil && entry.CreatedAt.Before(*filterParams.Since) {
+			continue
+		}
+		if filterParams.Until != nil && !entry.CreatedAt.Before(*filterParams.Until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b models.AuditEntry) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return paginate(entries, page), nil
+}
+
+func (r *AuditRepository) DeleteAuditBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for entryID, entry := range r.store.audit {
+		if entry.CreatedAt.Before(before) {
+			delete(r.store.audit, entryID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func roundTrip(details map[string]any) (map[string]any, error) {
+	copied := map[string]any{}
+	if details == nil {
+		return copied, nil
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{
+		store,
+	}
+}
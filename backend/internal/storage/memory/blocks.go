@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type BlockRepository struct {
+	store *Store
+}
+
+func (r *BlockRepository) BlockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID, bottleId *int) (*models.Block, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[blockerId]; !ok {
+		return nil, fmt.Errorf("error blocking user: user %s does not exist", blockerId)
+	}
+	if blockerId == blockedId {
+		return nil, fmt.Errorf("error blocking user: user %s cannot block themselves", blockerId)
+	}
+	if _, ok := r.store.users[blockedId]; !ok {
+		return nil, errs.NotFound("user", "id", blockedId.String())
+	}
+
+	key := blockKey{blockerId, blockedId}
+	if _, ok := r.store.blocks[key]; ok {
+		return nil, errs.Conflict("You have already blocked this user")
+	}
+
+	block := models.Block{BlockerID: blockerId, BlockedID: blockedId, BottleID: bottleId, CreatedAt: now()}
+	r.store.blocks[key] = block
+	return &block, nil
+}
+
+func (r *BlockRepository) UnblockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := blockKey{blockerId, blockedId}
+	if _, ok := r.store.blocks[key]; !ok {
+		return errs.NotFound("block", "user_id", blockedId.String())
+	}
+	delete(r.store.blocks, key)
+	return nil
+}
+
+func (r *BlockRepository) UnblockBottleAuthor(ctx context.Context, blockerId uuid.UUID, bottleId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	author := r.store.bottles[bottleId].UserID
+	unblocked := false
+	for key, block := range r.store.blocks {
+		if key.blockerID != blockerId {
+			continue
+		}
+		if block.BottleID != nil && *block.BottleID == bottleId || author != nil && key.blockedID == *author {
+			delete(r.store.blocks, key)
+			unblocked = true
+		}
+	}
+	if !unblocked {
+		return errs.NotFound("block", "bottle_id", fmt.Sprint(bottleId))
+	}
+	return nil
+}
+
+func (r *BlockRepository) GetBlocks(ctx context.Context, blockerId uuid.UUID) ([]models.Block, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	blocks := []models.Block{}
+	for key, block := range r.store.blocks {
+		if key.blockerID == blockerId {
+			blocks = append(blocks, block)
+		}
+	}
+	slices.SortFunc(blocks, func(a, b models.Block) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), slices.Compare(a.BlockedID[:], b.BlockedID[:]))
+	})
+	return blocks, nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, userId uuid.UUID, otherId uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.isBlocked(userId, otherId), nil
+}
+
+// isBlocked reports whether either user has blocked the other.
+func (s *Store) isBlocked(userID uuid.UUID, otherID uuid.UUID) bool {
+	_, blocked := s.blocks[blockKey{userID, otherID}]
+	_, blockedBy := s.blocks[blockKey{otherID, userID}]
+	return blocked || blockedBy
+}
+
+// hasBlocked reports whether the viewer, if any, has blocked the author, if any.
+func (s *Store) hasBlocked(viewerID *uuid.UUID, authorID *uuid.UUID) bool {
+	if viewerID == nil || authorID == nil {
+		return false
+	}
+	_, blocked := s.blocks[blockKey{*viewerID, *authorID}]
+	return blocked
+}
+
+func NewBlockRepository(store *Store) *BlockRepository {
+	return &BlockRepository{
+		store,
+	}
+}
@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"math/rand/v2"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxBottleText is the length of the bottle table's VARCHAR(100) columns.
+const maxBottleText = 100
+
+type BottleRepository struct {
+	store *Store
+}
+
+func (r *BottleRepository) CreateBottle(ctx context.Context, req models.CreateBottleRequest) (*models.Bottle, error) {
+	if req.TagID == nil {
+		return nil, errs.BadRequest("Missing tag_id")
+	}
+
+	for _, text := range []*string{&req.Content, req.Author, req.LocationFrom} {
+		if text != nil && utf8.RuneCountInString(*text) > maxBottleText {
+			return nil, fmt.Errorf("error querying database: value too long for type character varying(%d)", maxBottleText)
+		}
+	}
+
+	r.store.mu.Lock()
+
+	if _, ok := r.store.tags[*req.TagID]; !ok {
+		r.store.mu.Unlock()
+		return nil, fmt.Errorf("error querying database: tag %d does not exist", *req.TagID)
+	}
+	if req.UserID != nil {
+		if _, ok := r.store.users[*req.UserID]; !ok {
+			r.store.mu.Unlock()
+			return nil, fmt.Errorf("error querying database: user %s does not exist", *req.UserID)
+		}
+	}
+
+	status := models.BottleStatusAfloat
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	tagSource := models.TagSourceWriter
+	if req.TagSource != nil {
+		tagSource = *req.TagSource
+	}
+
+	r.store.nextBottleID++
+	bottle := models.Bottle{
+		ID:           r.store.nextBottleID,
+		Content:      req.Content,
+		Author:       req.Author,
+		TagID:        *req.TagID,
+		UserID:       req.UserID,
+		LocationFrom: req.LocationFrom,
+		CreatedAt:    now(),
+		Status:       status,
+		Mood:         req.Mood,
+		MoodScore:    req.MoodScore,
+		Language:     req.Language,
+		TagSource:    tagSource,
+	}
+	r.store.bottles[bottle.ID] = bottle
+	if req.Original != nil {
+		r.store.originals[bottle.ID] = *req.Original
+	}
+
+	var events []string
+	if bottle.Status == models.BottleStatusAfloat {
+		events = r.store.afloatEvents(bottle)
+	}
+	r.store.mu.Unlock()
+
+	r.store.publish(ctx, events)
+	return &bottle, nil
+}
+
+func (r *BottleRepository) GetBottleByID(ctx context.Context, bottleId int) (*models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottle, ok := r.store.bottles[bottleId]
+	if !ok {
+		return nil, errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+	return &bottle, nil
+}
+
+func (r *BottleRepository) GetBottleOriginal(ctx context.Context, bottleId int) (*models.BottleOriginal, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	original, ok := r.store.originals[bottleId]
+	if !ok {
+		return nil, errs.NotFound("bottle original", "bottle_id", fmt.Sprint(bottleId))
+	}
+	return &original, nil
+}
+
+// DeleteBottle removes the bottle and everything the database would cascade with it.
+func (r *BottleRepository) DeleteBottle(ctx context.Context, bottleId int) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deleteBottle(bottleId)
+	return "Bottle Deleted Successfully", nil
+}
+
+func (r *BottleRepository) GetBottles(ctx context.Context, filterParams models.GetBottlesRequest) ([]models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottles := []models.Bottle{}
+	for _, bottle := range r.store.bottles {
+		if bottle.Status != models.BottleStatusAfloat || !r.store.tagOceans[tagOceanKey{bottle.TagID, filterParams.OceanID}] {
+			continue
+		}
+		if r.store.hasBlocked(filterParams.ViewerID, bottle.UserID) {
+			continue
+		}
+		if filterParams.Mood != nil && (bottle.Mood == nil || *bottle.Mood != *filterParams.Mood) {
+			continue
+		}
+		bottles = append(bottles, bottle)
+	}
+
+	rand.Shuffle(len(bottles), func(i, j int) {
+		bottles[i], bottles[j] = bottles[j], bottles[i]
+	})
+
+	return bottles, nil
+}
+
+// GetBottlesByUser lists an author's bottles, newest first, with how often each has been found.
+func (r *BottleRepository) GetBottlesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.AuthoredBottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var bottles []models.AuthoredBottle
+	for _, bottle := range r.store.bottles {
+		if bottle.UserID == nil || *bottle.UserID != userId {
+			continue
+		}
+
+		authored := models.AuthoredBottle{Bottle: bottle, State: string(bottle.Status)}
+		if stats, ok := r.store.stats[bottle.ID]; ok {
+			authored.CatchCount = stats.catchCount
+			authored.FirstCaughtAt = ptr(stats.firstCaughtAt)
+			authored.LastCaughtAt = ptr(stats.lastCaughtAt)
+		}
+		for _, reply := range r.store.replies {
+			if reply.BottleID == bottle.ID {
+				authored.ReplyCount++
+			}
+		}
+		if bottle.Status == models.BottleStatusAfloat && authored.CatchCount > 0 {
+			authored.State = "found"
+		}
+
+		bottles = append(bottles, authored)
+	}
+
+	slices.SortFunc(bottles, func(a, b models.AuthoredBottle) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return paginate(bottles, page), nil
+}
+
+// GetRandomBottle fishes an afloat bottle the reader hasn't seen out of the ocean and records
+// the catch. A personal ocean only yields its owner's Personal bottles.
+func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams models.GetRandomBottleRequest, ocean models.Ocean) (*models.Bottle, error) {
+	r.store.mu.Lock()
+
+	var candidates []models.Bottle
+	for _, bottle := range r.store.bottles {
+		if bottle.Status != models.BottleStatusAfloat || !r.store.tagOceans[tagOceanKey{bottle.TagID, filterParams.OceanID}] {
+			continue
+		}
+		if ocean.UserID != nil && (!r.store.isPersonal(bottle.TagID) || bottle.UserID == nil || *bottle.UserID != *ocean.UserID) {
+			continue
+		}
+		if filterParams.SeenByUserId != nil {
+			if _, seen := r.store.seen[seenKey{*filterParams.SeenByUserId, bottle.ID}]; seen {
+				continue
+			}
+		}
+		if r.store.hasBlocked(filterParams.ViewerID, bottle.UserID) {
+			continue
+		}
+		if filterParams.Mood != nil && (bottle.Mood == nil || *bottle.Mood != *filterParams.Mood) {
+			continue
+		}
+		// Bottles whose language couldn't be told are caught by readers of any language
+		if len(filterParams.Languages) > 0 && bottle.Language != nil && *bottle.Language != models.LanguageUndetermined && !slices.Contains(filterParams.Languages, *bottle.Language) {
+			continue
+		}
+		candidates = append(candidates, bottle)
+	}
+
+	if len(candidates) == 0 {
+		r.store.mu.Unlock()
+		return nil, errs.NotFound("No bottles found in this ocean")
+	}
+	bottle := candidates[rand.IntN(len(candidates))]
+
+	var events []string
+	if filterParams.SeenByUserId != nil {
+		if _, ok := r.store.users[*filterParams.SeenByUserId]; !ok {
+			r.store.mu.Unlock()
+			return nil, fmt.Errorf("error querying database: user %s does not exist", *filterParams.SeenByUserId)
+		}
+
+		// A catch only counts towards the bottle's statistics if it is new to this reader
+		seenAt := now()
+		r.store.seen[seenKey{*filterParams.SeenByUserId, bottle.ID}] = seenAt
+		stats, ok := r.store.stats[bottle.ID]
+		if !ok {
+			stats.firstCaughtAt = seenAt
+		}
+		stats.catchCount++
+		stats.lastCaughtAt = seenAt
+		r.store.stats[bottle.ID] = stats
+		events = r.store.catchEvents(bottle)
+	}
+	r.store.mu.Unlock()
+
+	r.store.publish(ctx, events)
+	return &bottle, nil
+}
+
+// deleteBottle removes the bottle and everything the database would cascade with it.
+func (s *Store) deleteBottle(bottleId int) {
+	delete(s.bottles, bottleId)
+	delete(s.originals, bottleId)
+	delete(s.stats, bottleId)
+	for key := range s.seen {
+		if key.bottleID == bottleId {
+			delete(s.seen, key)
+		}
+	}
+	for key := range s.bookmarks {
+		if key.bottleID == bottleId {
+			delete(s.bookmarks, key)
+		}
+	}
+	for replyID, reply := range s.replies {
+		if reply.BottleID == bottleId {
+			delete(s.replies, replyID)
+		}
+	}
+	for notificationID, notification := range s.notifications {
+		if notification.BottleID != nil && *notification.BottleID == bottleId {
+			delete(s.notifications, notificationID)
+		}
+	}
+	for reportID, report := range s.reports {
+		if report.BottleID == bottleId {
+			delete(s.reports, reportID)
+		}
+	}
+	for strikeID, strike := range s.strikes {
+		if strike.BottleID != nil && *strike.BottleID == bottleId {
+			strike.BottleID = nil
+			s.strikes[strikeID] = strike
+		}
+	}
+}
+
+func NewBottleRepository(store *Store) *BottleRepository {
+	return &BottleRepository{
+		store,
+	}
+}
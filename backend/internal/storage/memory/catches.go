@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxBookmarkNote is the length of the bookmark table's VARCHAR(500) note.
+const maxBookmarkNote = 500
+
+func (r *BottleRepository) GetCatches(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var catches []models.Catch
+	for key := range r.store.seen {
+		if key.userID == userId && r.store.stillReadable(key) {
+			catches = append(catches, r.store.catch(key))
+		}
+	}
+
+	slices.SortFunc(catches, func(a, b models.Catch) int {
+		return cmp.Or(b.SeenAt.Compare(a.SeenAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return paginate(catches, page), nil
+}
+
+func (r *BottleRepository) GetBookmarks(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type kept struct {
+		catch   models.Catch
+		savedAt time.Time
+	}
+
+	var bookmarks []kept
+	for key, bookmark := range r.store.bookmarks {
+		if _, seen := r.store.seen[key]; key.userID == userId && seen && r.store.stillReadable(key) {
+			bookmarks = append(bookmarks, kept{r.store.catch(key), bookmark.CreatedAt})
+		}
+	}
+
+	slices.SortFunc(bookmarks, func(a, b kept) int {
+		return cmp.Or(b.savedAt.Compare(a.savedAt), cmp.Compare(b.catch.ID, a.catch.ID))
+	})
+
+	catches := make([]models.Catch, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		catches[i] = bookmark.catch
+	}
+
+	return paginate(catches, page), nil
+}
+
+// SaveBookmark keeps a caught bottle in the user's collection, updating the note if it is already kept.
+func (r *BottleRepository) SaveBookmark(ctx context.Context, userId uuid.UUID, bottleId int, note *string) (*models.Bookmark, error) {
+	if note != nil && utf8.RuneCountInString(*note) > maxBookmarkNote {
+		return nil, fmt.Errorf("error saving bookmark: value too long for type character varying(%d)", maxBookmarkNote)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := seenKey{userId, bottleId}
+	if _, seen := r.store.seen[key]; !seen {
+		return nil, errs.NotFound("You have not caught this bottle")
+	}
+
+	savedAt := now()
+	bookmark, ok := r.store.bookmarks[key]
+	if !ok {
+		bookmark = models.Bookmark{UserID: userId, BottleID: bottleId, CreatedAt: savedAt}
+	}
+	bookmark.Note = note
+	bookmark.UpdatedAt = savedAt
+	r.store.bookmarks[key] = bookmark
+
+	return &bookmark, nil
+}
+
+func (r *BottleRepository) DeleteBookmark(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := seenKey{userId, bottleId}
+	if _, ok := r.store.bookmarks[key]; !ok {
+		return errs.NotFound("bookmark", "bottle_id", fmt.Sprint(bottleId))
+	}
+	delete(r.store.bookmarks, key)
+	return nil
+}
+
+// ThrowBack removes a bottle from the user's seen set (and collection) so they can catch it again.
+func (r *BottleRepository) ThrowBack(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := seenKey{userId, bottleId}
+	if _, seen := r.store.seen[key]; !seen {
+		return errs.NotFound("You have not caught this bottle")
+	}
+	delete(r.store.seen, key)
+	delete(r.store.bookmarks, key)
+	return nil
+}
+
+// catch joins a seen_bottles row with its bottle and bookmark. The store must be locked.
+func (s *Store) catch(key seenKey) models.Catch {
+	catch := models.Catch{Bottle: s.bottles[key.bottleID], SeenAt: s.seen[key]}
+	if bookmark, ok := s.bookmarks[key]; ok {
+		catch.Bookmarked = true
+		catch.Note = bookmark.Note
+	}
+	return catch
+}
+
+// stillReadable reports whether a caught bottle is still afloat and by an author the reader
+// hasn't blocked, so that bottles taken out of the ocean leave the reader's collection too.
+func (s *Store) stillReadable(key seenKey) bool {
+	bottle := s.bottles[key.bottleID]
+	return bottle.Status == models.BottleStatusAfloat && !s.hasBlocked(&key.userID, bottle.UserID)
+}
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+)
+
+// AddTag creates a topic tag in a repository returned by NewRepository.
+func AddTag(repo *storage.Repository, name string) models.Tag {
+	store := repo.Tag.(*TagRepository).store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.insertTag(name, "#4DB6AC")
+}
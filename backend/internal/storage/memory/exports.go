@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportRepository struct {
+	store *Store
+}
+
+func (r *ExportRepository) CreateExportJob(ctx context.Context, userId uuid.UUID) (*models.ExportJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return nil, errs.NotFound("user", "id", userId.String())
+	}
+	for _, job := range r.store.exports {
+		if job.UserID == userId && inProgress(job) {
+			return nil, errs.Conflict("An export of your data is already in progress")
+		}
+	}
+
+	job := models.ExportJob{
+		ID:        uuid.New(),
+		UserID:    userId,
+		Status:    models.ExportStatusPending,
+		CreatedAt: now(),
+	}
+	r.store.exports[job.ID] = job
+	return &job, nil
+}
+
+func (r *ExportRepository) GetExportJob(ctx context.Context, jobId uuid.UUID) (*models.ExportJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.exports[jobId]
+	if !ok {
+		return nil, errs.NotFound("export", "id", jobId.String())
+	}
+	return &job, nil
+}
+
+func (r *ExportRepository) ClaimExportJob(ctx context.Context, staleBefore time.Time) (*models.ExportJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var oldest *models.ExportJob
+	for _, job := range r.store.exports {
+		stale := job.Status == models.ExportStatusRunning && job.StartedAt.Before(staleBefore)
+		if job.Status != models.ExportStatusPending && !stale {
+			continue
+		}
+		if oldest == nil || job.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = &job
+		}
+	}
+	if oldest == nil {
+		return nil, errs.NotFound("no export jobs are waiting")
+	}
+
+	oldest.Status = models.ExportStatusRunning
+	oldest.StartedAt = ptr(now())
+	r.store.exports[oldest.ID] = *oldest
+	return oldest, nil
+}
+
+func (r *ExportRepository) CompleteExportJob(ctx context.Context, jobId uuid.UUID, archive []byte, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.exports[jobId]
+	if !ok {
+		return errs.NotFound("export", "id", jobId.String())
+	}
+	job.Status = models.ExportStatusReady
+	job.Error = nil
+	job.CompletedAt = ptr(now())
+	job.ExpiresAt = ptr(expiresAt.UTC().Truncate(time.Microsecond))
+	r.store.exports[jobId] = job
+	r.store.archives[jobId] = archive
+	return nil
+}
+
+func (r *ExportRepository) FailExportJob(ctx context.Context, jobId uuid.UUID, message string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.exports[jobId]
+	if !ok {
+		return errs.NotFound("export", "id", jobId.String())
+	}
+	job.Status = models.ExportStatusFailed
+	job.Error = &message
+	job.CompletedAt = ptr(now())
+	r.store.exports[jobId] = job
+	return nil
+}
+
+func (r *ExportRepository) GetExportArchive(ctx context.Context, jobId uuid.UUID) ([]byte, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.exports[jobId]
+	if !ok || job.Status != models.ExportStatusReady || !job.ExpiresAt.After(now()) {
+		return nil, errs.NotFound("export", "id", jobId.String())
+	}
+	return r.store.archives[jobId], nil
+}
+
+func (r *ExportRepository) DeleteExpiredExports(ctx context.Context, at time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for jobID, job := range r.store.exports {
+		if job.ExpiresAt != nil && !job.ExpiresAt.After(at) {
+			delete(r.store.exports, jobID)
+			delete(r.store.archives, jobID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func inProgress(job models.ExportJob) bool {
+	return job.Status == models.ExportStatusPending || job.Status == models.ExportStatusRunning
+}
+
+func NewExportRepository(store *Store) *ExportRepository {
+	return &ExportRepository{
+		store,
+	}
+}
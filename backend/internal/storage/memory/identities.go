@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdentityRepository struct {
+	store *Store
+}
+
+func (r *IdentityRepository) CreateIdentity(ctx context.Context, email string, passwordHash string) (*models.Identity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, identity := range r.store.identities {
+		if identity.Email == email {
+			return nil, errs.Conflict("identity", "email", email)
+		}
+	}
+
+	created := now()
+	identity := models.Identity{
+		UserID:       uuid.New(),
+		Email:        email,
+		PasswordHash: passwordHash,
+		CreatedAt:    created,
+		UpdatedAt:    created,
+	}
+	r.store.identities[identity.UserID] = identity
+	return &identity, nil
+}
+
+func (r *IdentityRepository) GetIdentityByEmail(ctx context.Context, email string) (*models.Identity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, identity := range r.store.identities {
+		if identity.Email == email {
+			return &identity, nil
+		}
+	}
+	return nil, errs.NotFound("identity", "email", email)
+}
+
+func (r *IdentityRepository) UpdatePasswordHash(ctx context.Context, userId uuid.UUID, passwordHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	identity, ok := r.store.identities[userId]
+	if !ok {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	identity.PasswordHash = passwordHash
+	identity.UpdatedAt = now()
+	r.store.identities[userId] = identity
+	return nil
+}
+
+func (r *IdentityRepository) SetRecoveryTokenHash(ctx context.Context, userId uuid.UUID, tokenHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	identity, ok := r.store.identities[userId]
+	if !ok {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	identity.RecoveryTokenHash = &tokenHash
+	r.store.identities[userId] = identity
+	return nil
+}
+
+func (r *IdentityRepository) ResetPassword(ctx context.Context, userId uuid.UUID, tokenHash string, passwordHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	identity, ok := r.store.identities[userId]
+	if !ok || identity.RecoveryTokenHash == nil || *identity.RecoveryTokenHash != tokenHash {
+		return errs.NotFound("recovery token not found")
+	}
+	identity.PasswordHash = passwordHash
+	identity.RecoveryTokenHash = nil
+	identity.UpdatedAt = now()
+	r.store.identities[userId] = identity
+	return nil
+}
+
+// DeleteIdentity removes the account along with its sessions, as the foreign key cascade does.
+func (r *IdentityRepository) DeleteIdentity(ctx context.Context, userId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.identities[userId]; !ok {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	delete(r.store.identities, userId)
+	for id, session := range r.store.sessions {
+		if session.UserID == userId {
+			delete(r.store.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (r *IdentityRepository) CreateSession(ctx context.Context, userId uuid.UUID, refreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.identities[userId]; !ok {
+		return nil, errs.NotFound("identity", "user_id", userId.String())
+	}
+
+	created := now()
+	session := models.IdentitySession{
+		ID:               uuid.New(),
+		UserID:           userId,
+		RefreshTokenHash: refreshTokenHash,
+		CreatedAt:        created,
+		RefreshedAt:      created,
+		ExpiresAt:        expiresAt.UTC().Truncate(time.Microsecond),
+	}
+	r.store.sessions[session.ID] = session
+	return &session, nil
+}
+
+func (r *IdentityRepository) RotateSession(ctx context.Context, refreshTokenHash string, newRefreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rotated := now()
+	for id, session := range r.store.sessions {
+		if session.RefreshTokenHash != refreshTokenHash || !session.Live(rotated) {
+			continue
+		}
+		session.RefreshTokenHash = newRefreshTokenHash
+		session.ExpiresAt = expiresAt.UTC().Truncate(time.Microsecond)
+		session.RefreshedAt = rotated
+		r.store.sessions[id] = session
+		return &session, nil
+	}
+	return nil, errs.NotFound("session not found")
+}
+
+func (r *IdentityRepository) GetSession(ctx context.Context, sessionId uuid.UUID) (*models.IdentitySession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.sessions[sessionId]
+	if !ok {
+		return nil, errs.NotFound("session", "id", sessionId.String())
+	}
+	return &session, nil
+}
+
+func (r *IdentityRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.sessions[sessionId]
+	if !ok {
+		return errs.NotFound("session", "id", sessionId.String())
+	}
+	if session.RevokedAt == nil {
+		session.RevokedAt = ptr(now())
+		r.store.sessions[sessionId] = session
+	}
+	return nil
+}
+
+func (r *IdentityRepository) RevokeSessions(ctx context.Context, userId uuid.UUID, except uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var revoked int64
+	for id, session := range r.store.sessions {
+		if session.UserID != userId || id == except || session.RevokedAt != nil {
+			continue
+		}
+		session.RevokedAt = ptr(now())
+		r.store.sessions[id] = session
+		revoked++
+	}
+	return revoked, nil
+}
+
+func NewIdentityRepository(store *Store) *IdentityRepository {
+	return &IdentityRepository{
+		store,
+	}
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+)
+
+func (r *BottleRepository) GetUndetectedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottles := []models.Bottle{}
+	for _, id := range sortedKeys(r.store.bottles) {
+		if len(bottles) == limit {
+			break
+		}
+		if bottle := r.store.bottles[id]; bottle.Language == nil {
+			bottles = append(bottles, bottle)
+		}
+	}
+	return bottles, nil
+}
+
+func (r *BottleRepository) SetBottleLanguage(ctx context.Context, bottleId int, language models.Language) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottle, ok := r.store.bottles[bottleId]
+	if !ok {
+		return errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+	bottle.Language = &language
+	r.store.bottles[bottleId] = bottle
+	return nil
+}
@@ -0,0 +1,20 @@
+package memory_test
+
+import (
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"hackmit/internal/storage/memory"
+	"hackmit/internal/storage/storagetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, storagetest.Harness{
+		New: func(t *testing.T) *storage.Repository {
+			return memory.NewRepository(nil)
+		},
+		AddTag: func(t *testing.T, repo *storage.Repository, name string) models.Tag {
+			return memory.AddTag(repo, name)
+		},
+	})
+}
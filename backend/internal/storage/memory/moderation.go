@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+)
+
+// ListBottles returns bottles in any state, oldest first, for operators reviewing or exporting them.
+func (r *BottleRepository) ListBottles(ctx context.Context, filterParams models.ListBottlesRequest, page models.PaginationRequest) ([]models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottles := []models.Bottle{}
+	for _, bottle := range r.store.bottles {
+		if filterParams.Status != nil && bottle.Status != *filterParams.Status {
+			continue
+		}
+		if filterParams.OceanID != nil {
+			ocean, ok := r.store.oceans[*filterParams.OceanID]
+			if !ok || !r.store.driftsIn(bottle, ocean) {
+				continue
+			}
+		}
+		bottles = append(bottles, bottle)
+	}
+
+	slices.SortFunc(bottles, func(a, b models.Bottle) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return paginate(bottles, page), nil
+}
+
+// SetBottleStatus moves a bottle to a new lifecycle state and returns it as updated.
+func (r *BottleRepository) SetBottleStatus(ctx context.Context, bottleId int, status models.BottleStatus) (*models.Bottle, error) {
+	r.store.mu.Lock()
+
+	bottle, ok := r.store.bottles[bottleId]
+	if !ok {
+		r.store.mu.Unlock()
+		return nil, errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+
+	var events []string
+	if status == models.BottleStatusAfloat && bottle.Status != models.BottleStatusAfloat {
+		bottle.Status = status
+		events = r.store.afloatEvents(bottle)
+	}
+	bottle.Status = status
+	r.store.bottles[bottleId] = bottle
+	r.store.mu.Unlock()
+
+	r.store.publish(ctx, events)
+	return &bottle, nil
+}
+
+// RecomputeStats rebuilds the catch statistics from the seen set and returns how many bottles
+// changed. As in Postgres, counts and dates are only ever raised or widened.
+func (r *BottleRepository) RecomputeStats(ctx context.Context) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rebuilt := map[int]bottleStats{}
+	for key, seenAt := range r.store.seen {
+		stats, ok := rebuilt[key.bottleID]
+		if !ok || seenAt.Before(stats.firstCaughtAt) {
+			stats.firstCaughtAt = seenAt
+		}
+		if seenAt.After(stats.lastCaughtAt) {
+			stats.lastCaughtAt = seenAt
+		}
+		stats.catchCount++
+		rebuilt[key.bottleID] = stats
+	}
+
+	var changed int64
+	for bottleID, fresh := range rebuilt {
+		stats, ok := r.store.stats[bottleID]
+		if !ok {
+			r.store.stats[bottleID] = fresh
+			changed++
+			continue
+		}
+
+		updated := bottleStats{
+			catchCount:    max(stats.catchCount, fresh.catchCount),
+			firstCaughtAt: stats.firstCaughtAt,
+			lastCaughtAt:  stats.lastCaughtAt,
+		}
+		if fresh.firstCaughtAt.Before(updated.firstCaughtAt) {
+			updated.firstCaughtAt = fresh.firstCaughtAt
+		}
+		if fresh.lastCaughtAt.After(updated.lastCaughtAt) {
+			updated.lastCaughtAt = fresh.lastCaughtAt
+		}
+		if updated != stats {
+			r.store.stats[bottleID] = updated
+			changed++
+		}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+)
+
+func (r *BottleRepository) GetUnclassifiedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottles := []models.Bottle{}
+	for _, id := range sortedKeys(r.store.bottles) {
+		if len(bottles) == limit {
+			break
+		}
+		if bottle := r.store.bottles[id]; bottle.Mood == nil {
+			bottles = append(bottles, bottle)
+		}
+	}
+	return bottles, nil
+}
+
+func (r *BottleRepository) SetBottleMood(ctx context.Context, bottleId int, mood models.Mood, score float64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottle, ok := r.store.bottles[bottleId]
+	if !ok {
+		return errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+	bottle.Mood, bottle.MoodScore = &mood, &score
+	r.store.bottles[bottleId] = bottle
+	return nil
+}
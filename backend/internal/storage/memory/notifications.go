@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository struct {
+	store *Store
+}
+
+// RecordCatch folds a catch into the author's unread "found N times today" notification for the bottle.
+func (r *NotificationRepository) RecordCatch(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.wants(userId, models.NotificationBottleCaught) {
+		return nil
+	}
+
+	createdAt := now()
+	batchDate := createdAt.Truncate(24 * time.Hour)
+	for id, notification := range r.store.notifications {
+		if notification.UserID == userId && notification.Type == models.NotificationBottleCaught &&
+			notification.BottleID != nil && *notification.BottleID == bottleId &&
+			notification.BatchDate.Equal(batchDate) && notification.ReadAt == nil {
+			notification.Count++
+			notification.UpdatedAt = createdAt
+			r.store.notifications[id] = notification
+			return nil
+		}
+	}
+
+	if err := r.store.insertNotification(userId, models.NotificationBottleCaught, &bottleId, nil); err != nil {
+		return fmt.Errorf("error recording catch notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) CreateNotification(ctx context.Context, userId uuid.UUID, notificationType models.NotificationType, bottleId *int, detail *string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.wants(userId, notificationType) {
+		return nil
+	}
+
+	if err := r.store.insertNotification(userId, notificationType, bottleId, detail); err != nil {
+		return fmt.Errorf("error creating notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, page models.PaginationRequest) ([]models.Notification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notifications := []models.Notification{}
+	for _, notification := range r.store.notifications {
+		if notification.UserID != userId || unreadOnly && notification.ReadAt != nil {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	slices.SortFunc(notifications, func(a, b models.Notification) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	notifications = paginate(notifications, page)
+	for i := range notifications {
+		notifications[i].Describe()
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userId uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, notification := range r.store.notifications {
+		if notification.UserID == userId && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userId uuid.UUID, notificationId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notification, ok := r.store.notifications[notificationId]
+	if !ok || notification.UserID != userId {
+		return errs.NotFound("notification", "id", fmt.Sprint(notificationId))
+	}
+	if notification.ReadAt == nil {
+		notification.ReadAt = ptr(now())
+		r.store.notifications[notificationId] = notification
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userId uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	readAt := now()
+	marked := 0
+	for id, notification := range r.store.notifications {
+		if notification.UserID == userId && notification.ReadAt == nil {
+			notification.ReadAt = &readAt
+			r.store.notifications[id] = notification
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// GetPreferences returns the user's setting for every notification type, defaulting to enabled.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userId uuid.UUID) ([]models.NotificationPreference, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		preferences = append(preferences, models.NotificationPreference{
+			Type:    notificationType,
+			Enabled: r.store.wants(userId, notificationType),
+		})
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences models.UpdateNotificationPreferencesRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return fmt.Errorf("error updating notification preferences: user %s does not exist", userId)
+	}
+
+	if r.store.preferences[userId] == nil {
+		r.store.preferences[userId] = map[models.NotificationType]bool{}
+	}
+	for notificationType, enabled := range preferences {
+		r.store.preferences[userId][notificationType] = enabled
+	}
+	return nil
+}
+
+// wants reports whether the user hasn't opted out of a notification type. The store must be locked.
+func (s *Store) wants(userID uuid.UUID, notificationType models.NotificationType) bool {
+	enabled, ok := s.preferences[userID][notificationType]
+	return !ok || enabled
+}
+
+// insertNotification adds a new unread notification. The store must be locked.
+func (s *Store) insertNotification(userID uuid.UUID, notificationType models.NotificationType, bottleID *int, detail *string) error {
+	if _, ok := s.users[userID]; !ok {
+		return fmt.Errorf("user %s does not exist", userID)
+	}
+	if bottleID != nil {
+		if _, ok := s.bottles[*bottleID]; !ok {
+			return fmt.Errorf("bottle %d does not exist", *bottleID)
+		}
+	}
+
+	createdAt := now()
+	s.nextNotificationID++
+	s.notifications[s.nextNotificationID] = models.Notification{
+		ID:        s.nextNotificationID,
+		UserID:    userID,
+		Type:      notificationType,
+		BottleID:  bottleID,
+		Detail:    detail,
+		Count:     1,
+		BatchDate: createdAt.Truncate(24 * time.Hour),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	return nil
+}
+
+func NewNotificationRepository(store *Store) *NotificationRepository {
+	return &NotificationRepository{
+		store,
+	}
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type OceanRepository struct {
+	store *Store
+}
+
+func (r *OceanRepository) GetOceans(ctx context.Context, filterParams models.GetOceansRequest) ([]models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	oceans := []models.Ocean{}
+	for _, id := range sortedKeys(r.store.oceans) {
+		ocean := r.store.oceans[id]
+
+		if len(filterParams.IncludeTags) > 0 && !slices.ContainsFunc(filterParams.IncludeTags, func(tagID int) bool {
+			return r.store.tagOceans[tagOceanKey{tagID, ocean.ID}]
+		}) {
+			continue
+		}
+		if filterParams.Name != nil && *filterParams.Name != "" && !containsFold(ocean.Name, *filterParams.Name) {
+			continue
+		}
+		if filterParams.Description != nil && *filterParams.Description != "" && !containsFold(ocean.Description, *filterParams.Description) {
+			continue
+		}
+
+		oceans = append(oceans, ocean)
+	}
+
+	return oceans, nil
+}
+
+func (r *OceanRepository) GetDefaultOcean(ctx context.Context) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range sortedKeys(r.store.oceans) {
+		ocean := r.store.oceans[id]
+		if ocean.UserID == nil && ocean.Name != nil && *ocean.Name == "Default" {
+			return &ocean, nil
+		}
+	}
+	return nil, fmt.Errorf("no default ocean found")
+}
+
+func (r *OceanRepository) GetRandomPersonalOcean(ctx context.Context, currentUserId *uuid.UUID) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var candidates []models.Ocean
+	for _, ocean := range r.store.oceans {
+		if ocean.UserID == nil || currentUserId != nil && *ocean.UserID == *currentUserId {
+			continue
+		}
+		candidates = append(candidates, ocean)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no personal oceans found")
+	}
+	ocean := candidates[rand.IntN(len(candidates))]
+	return &ocean, nil
+}
+
+func (r *OceanRepository) GetOceanByUser(ctx context.Context, userId uuid.UUID) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range sortedKeys(r.store.oceans) {
+		ocean := r.store.oceans[id]
+		if ocean.UserID != nil && *ocean.UserID == userId {
+			return &ocean, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// CreateOcean makes a personal ocean and maps the Personal tag to it.
+func (r *OceanRepository) CreateOcean(ctx context.Context, name *string, description *string, userId uuid.UUID) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return nil, fmt.Errorf("error creating ocean: user %s does not exist", userId)
+	}
+	personal, ok := r.store.tagNamed("Personal")
+	if !ok {
+		return nil, fmt.Errorf("error associating tags with new ocean: no Personal tag")
+	}
+
+	ocean := r.store.insertOcean(name, description, &userId)
+	r.store.tagOceans[tagOceanKey{personal.ID, ocean.ID}] = true
+
+	return &ocean, nil
+}
+
+func (r *OceanRepository) GetOceanById(ctx context.Context, oceanId int) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ocean, ok := r.store.oceans[oceanId]
+	if !ok {
+		return nil, errs.BadRequest(fmt.Sprintf("Error finding ocean with ocean_id: %d, %s", oceanId, sql.ErrNoRows))
+	}
+	return &ocean, nil
+}
+
+func (r *OceanRepository) UpdateOceanSettings(ctx context.Context, oceanId int, req models.UpdateOceanSettingsRequest) (*models.Ocean, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ocean, ok := r.store.oceans[oceanId]
+	if !ok {
+		return nil, errs.NotFound("ocean", "id", fmt.Sprint(oceanId))
+	}
+	if req.MaskProfanity != nil {
+		ocean.MaskProfanity = *req.MaskProfanity
+	}
+	r.store.oceans[oceanId] = ocean
+	return &ocean, nil
+}
+
+func (r *OceanRepository) MasksProfanity(ctx context.Context, tagId int, authorId *uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottle := models.Bottle{TagID: tagId, UserID: authorId}
+	drifts := false
+	for _, ocean := range r.store.oceans {
+		if !r.store.driftsIn(bottle, ocean) {
+			continue
+		}
+		if !ocean.MaskProfanity {
+			return false, nil
+		}
+		drifts = true
+	}
+	return drifts, nil
+}
+
+// containsFold is ILIKE '%substr%' on a nullable column.
+func containsFold(value *string, substr string) bool {
+	return value != nil && strings.Contains(strings.ToLower(*value), strings.ToLower(substr))
+}
+
+func NewOceanRepository(store *Store) *OceanRepository {
+	return &OceanRepository{
+		store,
+	}
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// CreateReply sends a reply to a bottle's author. Only readers who caught the bottle may reply.
+func (r *BottleRepository) CreateReply(ctx context.Context, bottleId int, userId uuid.UUID, content string) (*models.Reply, error) {
+	if utf8.RuneCountInString(content) > maxBottleText {
+		return nil, fmt.Errorf("error creating reply: value too long for type character varying(%d)", maxBottleText)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, seen := r.store.seen[seenKey{userId, bottleId}]; !seen {
+		return nil, errs.Forbidden("You can only reply to bottles you have caught")
+	}
+
+	r.store.nextReplyID++
+	reply := models.Reply{
+		ID:        r.store.nextReplyID,
+		BottleID:  bottleId,
+		UserID:    userId,
+		Content:   content,
+		CreatedAt: now(),
+	}
+	r.store.replies[reply.ID] = reply
+
+	return &reply, nil
+}
+
+// GetReplies returns the replies on a bottle visible to the viewer: all of them for the
+// bottle's author, except those from users they have blocked or been blocked by, and only
+// their own for anyone else.
+func (r *BottleRepository) GetReplies(ctx context.Context, bottleId int, viewerId uuid.UUID) ([]models.Reply, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottle, ok := r.store.bottles[bottleId]
+	if !ok {
+		return []models.Reply{}, nil
+	}
+	isAuthor := bottle.UserID != nil && *bottle.UserID == viewerId
+
+	replies := []models.Reply{}
+	for _, reply := range r.store.replies {
+		if reply.BottleID == bottleId && (reply.UserID == viewerId || isAuthor && !r.store.isBlocked(viewerId, reply.UserID)) {
+			replies = append(replies, reply)
+		}
+	}
+
+	slices.SortFunc(replies, func(a, b models.Reply) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return replies, nil
+}
+
+func (r *BottleRepository) GetRepliesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Reply, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var replies []models.Reply
+	for _, reply := range r.store.replies {
+		if reply.UserID == userId {
+			replies = append(replies, reply)
+		}
+	}
+
+	slices.SortFunc(replies, func(a, b models.Reply) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return paginate(replies, page), nil
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type ReportRepository struct {
+	store *Store
+}
+
+func (r *ReportRepository) CreateReport(ctx context.Context, bottleId int, reporterId uuid.UUID, req models.CreateReportRequest) (*models.Report, error) {
+	if !req.Reason.Valid() {
+		return nil, fmt.Errorf("error creating report: invalid reason %q", req.Reason)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, report := range r.store.reports {
+		if report.BottleID == bottleId && report.ReporterID == reporterId {
+			return nil, errs.Conflict("You have already reported this bottle")
+		}
+	}
+	if _, caught := r.store.seen[seenKey{reporterId, bottleId}]; !caught {
+		return nil, errs.Forbidden("You can only report bottles you have caught")
+	}
+
+	r.store.nextReportID++
+	report := models.Report{
+		ID:         r.store.nextReportID,
+		BottleID:   bottleId,
+		ReporterID: reporterId,
+		Reason:     req.Reason,
+		Comment:    req.Comment,
+		Status:     models.ReportStatusPending,
+		CreatedAt:  now(),
+	}
+	r.store.reports[report.ID] = report
+	return &report, nil
+}
+
+func (r *ReportRepository) CountPendingReports(ctx context.Context, bottleId int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, report := range r.store.reports {
+		if report.BottleID == bottleId && report.Status == models.ReportStatusPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ReportRepository) GetReport(ctx context.Context, reportId int) (*models.Report, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	report, ok := r.store.reports[reportId]
+	if !ok {
+		return nil, errs.NotFound("report", "id", fmt.Sprint(reportId))
+	}
+	return &report, nil
+}
+
+func (r *ReportRepository) ListReports(ctx context.Context, filterParams models.ListReportsRequest, page models.PaginationRequest) ([]models.Report, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reports := []models.Report{}
+	for _, report := range r.store.reports {
+		if filterParams.Status != nil && report.Status != *filterParams.Status {
+			continue
+		}
+		if filterParams.BottleID != nil && report.BottleID != *filterParams.BottleID {
+			continue
+		}
+		if filterParams.ReporterID != nil && report.ReporterID != *filterParams.ReporterID {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b models.Report) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return paginate(reports, page), nil
+}
+
+func (r *ReportRepository) ResolveReports(ctx context.Context, bottleId int, resolution models.ReportStatus, resolvedBy *uuid.UUID) ([]models.Report, error) {
+	if !resolution.Valid() {
+		return nil, fmt.Errorf("error resolving reports: invalid status %q", resolution)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	resolvedAt := now()
+	resolved := []models.Report{}
+	for reportID, report := range r.store.reports {
+		if report.BottleID != bottleId || report.Status != models.ReportStatusPending {
+			continue
+		}
+		report.Status = resolution
+		report.ResolvedAt = &resolvedAt
+		report.ResolvedBy = resolvedBy
+		r.store.reports[reportID] = report
+		resolved = append(resolved, report)
+	}
+	slices.SortFunc(resolved, func(a, b models.Report) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return resolved, nil
+}
+
+func (r *ReportRepository) AddStrike(ctx context.Context, userId uuid.UUID, bottleId *int, issuedBy *uuid.UUID) (*models.Strike, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return nil, errs.NotFound("user", "id", userId.String())
+	}
+
+	r.store.nextStrikeID++
+	strike := models.Strike{
+		ID:        r.store.nextStrikeID,
+		UserID:    userId,
+		BottleID:  bottleId,
+		IssuedBy:  issuedBy,
+		CreatedAt: now(),
+	}
+	r.store.strikes[strike.ID] = strike
+	return &strike, nil
+}
+
+func (r *ReportRepository) GetStrikes(ctx context.Context, userId uuid.UUID) ([]models.Strike, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	strikes := []models.Strike{}
+	for _, strike := range r.store.strikes {
+		if strike.UserID == userId {
+			strikes = append(strikes, strike)
+		}
+	}
+	slices.SortFunc(strikes, func(a, b models.Strike) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return strikes, nil
+}
+
+func NewReportRepository(store *Store) *ReportRepository {
+	return &ReportRepository{
+		store,
+	}
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/search"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// SearchBottles finds afloat bottles matching a search query, best matches first.
+//
+// Matching follows the same query syntax as Postgres, but compares words as written: there is
+// no stemming or stop word list, and rank is simply how often the wanted terms occur.
+func (r *BottleRepository) SearchBottles(ctx context.Context, filterParams models.SearchBottlesRequest, viewerId *uuid.UUID, page models.PaginationRequest) ([]models.SearchResult, error) {
+	query := search.Parse(filterParams.Query)
+	if query.Empty() {
+		return nil, errs.BadRequest("Search query must contain at least one word")
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var searchOcean *models.Ocean
+	if filterParams.OceanID != nil {
+		ocean, ok := r.store.oceans[*filterParams.OceanID]
+		if !ok || ocean.UserID != nil && (viewerId == nil || *ocean.UserID != *viewerId) {
+			return []models.SearchResult{}, nil
+		}
+		searchOcean = &ocean
+	}
+
+	results := []models.SearchResult{}
+	for _, bottle := range r.store.bottles {
+		if bottle.Status != models.BottleStatusAfloat {
+			continue
+		}
+		if r.store.isPersonal(bottle.TagID) && (viewerId == nil || bottle.UserID == nil || *bottle.UserID != *viewerId) {
+			continue
+		}
+		if r.store.hasBlocked(viewerId, bottle.UserID) {
+			continue
+		}
+		if searchOcean != nil && !r.store.tagOceans[tagOceanKey{bottle.TagID, searchOcean.ID}] {
+			continue
+		}
+		if filterParams.TagID != nil && bottle.TagID != *filterParams.TagID {
+			continue
+		}
+		if filterParams.From != nil && bottle.CreatedAt.Before(*filterParams.From) {
+			continue
+		}
+		if filterParams.To != nil && !bottle.CreatedAt.Before(*filterParams.To) {
+			continue
+		}
+
+		matched, hits := query.Match(bottle.Content)
+		if !matched {
+			continue
+		}
+
+		results = append(results, models.SearchResult{
+			Bottle:    bottle,
+			Rank:      float64(hits),
+			Highlight: query.Highlight(bottle.Content),
+		})
+	}
+
+	slices.SortFunc(results, func(a, b models.SearchResult) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return paginate(results, page), nil
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, req models.CreateSessionRequest) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[req.UserID]; !ok {
+		return nil, fmt.Errorf("error creating session: user %s does not exist", req.UserID)
+	}
+	if _, ok := r.store.userSessions[req.ID]; ok {
+		return nil, fmt.Errorf("error creating session: session %s already exists", req.ID)
+	}
+	if _, ok := r.store.refreshTokens[req.RefreshTokenHash]; ok {
+		return nil, fmt.Errorf("error creating session: refresh token already issued")
+	}
+
+	created := now()
+	session := models.Session{
+		ID:          req.ID,
+		UserID:      req.UserID,
+		RememberMe:  req.RememberMe,
+		UserAgent:   req.UserAgent,
+		IPAddress:   req.IPAddress,
+		CreatedAt:   created,
+		RefreshedAt: created,
+		LastSeenAt:  created,
+	}
+	r.store.userSessions[session.ID] = session
+	r.store.refreshTokens[req.RefreshTokenHash] = models.RefreshToken{TokenHash: req.RefreshTokenHash, SessionID: session.ID, CreatedAt: created}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, sessionId uuid.UUID) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.userSessions[sessionId]
+	if !ok {
+		return nil, errs.NotFound("session", "id", sessionId.String())
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) ListSessions(ctx context.Context, userId uuid.UUID) ([]models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	sessions := []models.Session{}
+	for _, session := range r.store.userSessions {
+		if session.UserID == userId && session.RevokedAt == nil {
+			sessions = append(sessions, session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b models.Session) int {
+		if c := b.LastSeenAt.Compare(a.LastSeenAt); c != 0 {
+			return c
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if session, ok := r.store.userSessions[sessionId]; ok {
+		session.LastSeenAt = now()
+		r.store.userSessions[sessionId] = session
+	}
+	return nil
+}
+
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.refreshTokens[tokenHash]
+	if !ok {
+		return nil, errs.NotFound("refresh token not found")
+	}
+	return &token, nil
+}
+
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, tokenHash string, newTokenHash string) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.refreshTokens[tokenHash]
+	if !ok || token.UsedAt != nil {
+		return nil, errs.Conflict("refresh token is no longer current")
+	}
+	session := r.store.userSessions[token.SessionID]
+	if session.RevokedAt != nil {
+		return nil, errs.Conflict("refresh token is no longer current")
+	}
+	if _, ok := r.store.refreshTokens[newTokenHash]; ok {
+		return nil, fmt.Errorf("error rotating refresh token: refresh token already issued")
+	}
+
+	rotated := now()
+	token.UsedAt = &rotated
+	r.store.refreshTokens[tokenHash] = token
+	r.store.refreshTokens[newTokenHash] = models.RefreshToken{TokenHash: newTokenHash, SessionID: session.ID, CreatedAt: rotated}
+
+	session.RefreshedAt = rotated
+	session.LastSeenAt = rotated
+	r.store.userSessions[session.ID] = session
+	return &session, nil
+}
+
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.userSessions[sessionId]
+	if !ok {
+		return errs.NotFound("session", "id", sessionId.String())
+	}
+	if session.RevokedAt == nil {
+		session.RevokedAt = ptr(now())
+		r.store.userSessions[sessionId] = session
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userId uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	revoked := now()
+	var count int64
+	for id, session := range r.store.userSessions {
+		if session.UserID == userId && session.RevokedAt == nil {
+			session.RevokedAt = &revoked
+			r.store.userSessions[id] = session
+			count++
+		}
+	}
+	return count, nil
+}
+
+// deleteSession removes a session and its refresh tokens, as the foreign key cascade does.
+// The caller holds s.mu.
+func (s *Store) deleteSession(sessionId uuid.UUID) {
+	delete(s.userSessions, sessionId)
+	for hash, token := range s.refreshTokens {
+		if token.SessionID == sessionId {
+			delete(s.refreshTokens, hash)
+		}
+	}
+}
+
+func NewSessionRepository(store *Store) *SessionRepository {
+	return &SessionRepository{
+		store,
+	}
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"hackmit/internal/stream"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seenKey identifies a reader's catch of a bottle, as in the seen_bottles and bookmark tables.
+type seenKey struct {
+	userID   uuid.UUID
+	bottleID int
+}
+
+type blockKey struct {
+	blockerID uuid.UUID
+	blockedID uuid.UUID
+}
+
+type tagOceanKey struct {
+	tagID   int
+	oceanID int
+}
+
+type bottleStats struct {
+	catchCount    int
+	firstCaughtAt time.Time
+	lastCaughtAt  time.Time
+}
+
+// Store holds every table of the in-memory repositories. All repositories built on the same
+// store share its data, the way the Postgres repositories share a database.
+type Store struct {
+	mu sync.Mutex
+	// txMu runs top-level units of work one at a time.
+	txMu sync.Mutex
+
+	users         map[uuid.UUID]models.User
+	oceans        map[int]models.Ocean
+	tags          map[int]models.Tag
+	tagOceans     map[tagOceanKey]bool
+	bottles       map[int]models.Bottle
+	originals     map[int]models.BottleOriginal
+	seen          map[seenKey]time.Time
+	stats         map[int]bottleStats
+	bookmarks     map[seenKey]models.Bookmark
+	replies       map[int]models.Reply
+	notifications map[int]models.Notification
+	preferences   map[uuid.UUID]map[models.NotificationType]bool
+	identities    map[uuid.UUID]models.Identity
+	sessions      map[uuid.UUID]models.IdentitySession
+	userSessions  map[uuid.UUID]models.Session
+	refreshTokens map[string]models.RefreshToken
+	roleChanges   map[int]models.RoleChange
+	deletions     map[uuid.UUID]models.AccountDeletion
+	exports       map[uuid.UUID]models.ExportJob
+	archives      map[uuid.UUID][]byte
+	blocks        map[blockKey]models.Block
+	reports       map[int]models.Report
+	strikes       map[int]models.Strike
+	audit         map[int64]models.AuditEntry
+	tagModels     map[int]models.TagModel
+
+	nextOceanID        int
+	nextTagID          int
+	nextBottleID       int
+	nextReplyID        int
+	nextNotificationID int
+	nextRoleChangeID   int
+	nextReportID       int
+	nextStrikeID       int
+	nextAuditID        int64
+	nextTagModelID     int
+
+	// broker receives the ocean events the database triggers would publish, if set.
+	broker stream.Broker
+}
+
+// NewStore returns a store holding the same system tags and default ocean as seed.sql.
+// Ocean activity is published to broker, which may be nil.
+func NewStore(broker stream.Broker) *Store {
+	s := &Store{
+		users:         map[uuid.UUID]models.User{},
+		oceans:        map[int]models.Ocean{},
+		tags:          map[int]models.Tag{},
+		tagOceans:     map[tagOceanKey]bool{},
+		bottles:       map[int]models.Bottle{},
+		originals:     map[int]models.BottleOriginal{},
+		seen:          map[seenKey]time.Time{},
+		stats:         map[int]bottleStats{},
+		bookmarks:     map[seenKey]models.Bookmark{},
+		replies:       map[int]models.Reply{},
+		notifications: map[int]models.Notification{},
+		preferences:   map[uuid.UUID]map[models.NotificationType]bool{},
+		identities:    map[uuid.UUID]models.Identity{},
+		sessions:      map[uuid.UUID]models.IdentitySession{},
+		userSessions:  map[uuid.UUID]models.Session{},
+		refreshTokens: map[string]models.RefreshToken{},
+		roleChanges:   map[int]models.RoleChange{},
+		deletions:     map[uuid.UUID]models.AccountDeletion{},
+		exports:       map[uuid.UUID]models.ExportJob{},
+		archives:      map[uuid.UUID][]byte{},
+		blocks:        map[blockKey]models.Block{},
+		reports:       map[int]models.Report{},
+		strikes:       map[int]models.Strike{},
+		audit:         map[int64]models.AuditEntry{},
+		tagModels:     map[int]models.TagModel{},
+		broker:        broker,
+	}
+
+	defaultTag := s.insertTag("Default", "#90A4AE")
+	s.insertTag("Personal", "#7986CB")
+	name, description := "Default", "The open ocean, where every untagged bottle drifts"
+	defaultOcean := s.insertOcean(&name, &description, nil)
+	s.tagOceans[tagOceanKey{defaultTag.ID, defaultOcean.ID}] = true
+
+	return s
+}
+
+// NewRepository returns a storage.Repository backed by a fresh in-memory store.
+func NewRepository(broker stream.Broker) *storage.Repository {
+	return newRepository(NewStore(broker))
+}
+
+func newRepository(store *Store) *storage.Repository {
+	return &storage.Repository{
+		User:         NewUserRepository(store),
+		Ocean:        NewOceanRepository(store),
+		Tag:          NewTagRepository(store),
+		Bottle:       NewBottleRepository(store),
+		Notification: NewNotificationRepository(store),
+		Identity:     NewIdentityRepository(store),
+		Session:      NewSessionRepository(store),
+		Export:       NewExportRepository(store),
+		Block:        NewBlockRepository(store),
+		Report:       NewReportRepository(store),
+		Audit:        NewAuditRepository(store),
+		Transactor:   &transactor{store: store},
+	}
+}
+
+func (s *Store) insertTag(name string, color string) models.Tag {
+	s.nextTagID++
+	tag := models.Tag{ID: s.nextTagID, Name: &name, Color: &color}
+	s.tags[tag.ID] = tag
+	return tag
+}
+
+func (s *Store) insertOcean(name *string, description *string, userID *uuid.UUID) models.Ocean {
+	s.nextOceanID++
+	ocean := models.Ocean{ID: s.nextOceanID, Name: name, Description: description, UserID: userID}
+	s.oceans[ocean.ID] = ocean
+	return ocean
+}
+
+// tagNamed finds a tag by name, the way the SQL subqueries on tag.name do.
+func (s *Store) tagNamed(name string) (models.Tag, bool) {
+	for _, id := range sortedKeys(s.tags) {
+		if tag := s.tags[id]; tag.Name != nil && *tag.Name == name {
+			return tag, true
+		}
+	}
+	return models.Tag{}, false
+}
+
+func (s *Store) isPersonal(tagID int) bool {
+	personal, ok := s.tagNamed("Personal")
+	return ok && personal.ID == tagID
+}
+
+// driftsIn reports whether a bottle is in an ocean: its tag is mapped to the ocean, and a
+// personal ocean only holds its owner's bottles.
+func (s *Store) driftsIn(bottle models.Bottle, ocean models.Ocean) bool {
+	if !s.tagOceans[tagOceanKey{bottle.TagID, ocean.ID}] {
+		return false
+	}
+	return ocean.UserID == nil || bottle.UserID != nil && *bottle.UserID == *ocean.UserID
+}
+
+// afloatEvents builds the messages the bottle_afloat_notify trigger sends when a bottle sets sail.
+func (s *Store) afloatEvents(bottle models.Bottle) []string {
+	var events []string
+	for _, oceanID := range sortedKeys(s.oceans) {
+		if !s.driftsIn(bottle, s.oceans[oceanID]) {
+			continue
+		}
+		events = append(events, encodeEvent(map[string]any{
+			"type":     "bottle",
+			"ocean_id": oceanID,
+			"bottle": map[string]any{
+				"id":            bottle.ID,
+				"content":       bottle.Content,
+				"author":        bottle.Author,
+				"tag_id":        bottle.TagID,
+				"location_from": bottle.LocationFrom,
+				"created_at":    bottle.CreatedAt,
+				"status":        bottle.Status,
+			},
+		}))
+	}
+	return events
+}
+
+// catchEvents builds the messages the bottle_stats_notify trigger sends when a catch is counted.
+func (s *Store) catchEvents(bottle models.Bottle) []string {
+	if bottle.Status != models.BottleStatusAfloat {
+		return nil
+	}
+
+	var events []string
+	for _, oceanID := range sortedKeys(s.oceans) {
+		if !s.driftsIn(bottle, s.oceans[oceanID]) {
+			continue
+		}
+		events = append(events, encodeEvent(map[string]any{
+			"type":        "catch",
+			"ocean_id":    oceanID,
+			"bottle_id":   bottle.ID,
+			"catch_count": s.stats[bottle.ID].catchCount,
+		}))
+	}
+	return events
+}
+
+// publish sends ocean events once the store is unlocked, so that listeners never wait on it.
+func (s *Store) publish(ctx context.Context, events []string) {
+	if s.broker == nil {
+		return
+	}
+	for _, event := range events {
+		if err := s.broker.Notify(ctx, stream.EventsChannel, event); err != nil {
+			slog.Error("failed to publish ocean event", "error", err)
+		}
+	}
+}
+
+func encodeEvent(event map[string]any) string {
+	payload, _ := json.Marshal(event)
+	return string(payload)
+}
+
+// now matches the precision of Postgres timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func sortedKeys[V any](table map[int]V) []int {
+	keys := make([]int, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// paginate returns the page of items that LIMIT and OFFSET would.
+func paginate[T any](items []T, page models.PaginationRequest) []T {
+	if page.Offset >= len(items) {
+		return []T{}
+	}
+	items = items[page.Offset:]
+	if page.Limit < len(items) {
+		items = items[:page.Limit]
+	}
+	return items
+}
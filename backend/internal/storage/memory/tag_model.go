@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+)
+
+// GetTaggedBottles returns up to limit of the newest afloat bottles their writers tagged with
+// something other than the Default or Personal tags. Tags the classifier applied are left out.
+func (r *BottleRepository) GetTaggedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bottles := []models.Bottle{}
+	for _, bottle := range r.store.bottles {
+		tag := r.store.tags[bottle.TagID]
+		if bottle.Status != models.BottleStatusAfloat || bottle.TagSource != models.TagSourceWriter || tag.Name == nil || *tag.Name == "Default" || *tag.Name == "Personal" {
+			continue
+		}
+		bottles = append(bottles, bottle)
+	}
+
+	slices.SortFunc(bottles, func(a, b models.Bottle) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return bottles[:min(limit, len(bottles))], nil
+}
+
+func (r *TagRepository) SaveTagModel(ctx context.Context, model models.TagModel) (*models.TagModel, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextTagModelID++
+	model.ID = r.store.nextTagModelID
+	model.TrainedAt = now()
+	r.store.tagModels[model.ID] = model
+	return &model, nil
+}
+
+func (r *TagRepository) GetLatestTagModel(ctx context.Context) (*models.TagModel, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	model, ok := r.store.tagModels[r.store.nextTagModelID]
+	if !ok {
+		return nil, errs.NotFound("no tag model has been trained")
+	}
+	return &model, nil
+}
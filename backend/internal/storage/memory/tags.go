@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/models"
+)
+
+type TagRepository struct {
+	store *Store
+}
+
+func (r *TagRepository) GetTags(ctx context.Context, filterParams models.GetTagsRequest) ([]models.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	includeDefault := filterParams.IncludeDefault != nil && *filterParams.IncludeDefault
+
+	tags := []models.Tag{}
+	for _, id := range sortedKeys(r.store.tags) {
+		tag := r.store.tags[id]
+		if filterParams.Name != nil && (tag.Name == nil || *tag.Name != *filterParams.Name) {
+			continue
+		}
+		if !includeDefault && tag.Name != nil && *tag.Name == "Default" {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) GetDefaultTag(ctx context.Context) (*models.Tag, error) {
+	return r.named("Default")
+}
+
+func (r *TagRepository) GetPersonalTag(ctx context.Context) (*models.Tag, error) {
+	return r.named("Personal")
+}
+
+func (r *TagRepository) GetTagsByOcean(ctx context.Context, oceanId int) ([]models.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tags := []models.Tag{}
+	for _, id := range sortedKeys(r.store.tags) {
+		if r.store.tagOceans[tagOceanKey{id, oceanId}] {
+			tags = append(tags, r.store.tags[id])
+		}
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) named(name string) (*models.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tag, ok := r.store.tagNamed(name)
+	if !ok {
+		return nil, fmt.Errorf("error querying database for tag: no %s tag", name)
+	}
+	return &tag, nil
+}
+
+func NewTagRepository(store *Store) *TagRepository {
+	return &TagRepository{
+		store,
+	}
+}
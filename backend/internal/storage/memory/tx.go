@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// snapshot is a copy of every table, taken at the start of a unit of work.
+type snapshot struct {
+	users         map[uuid.UUID]models.User
+	oceans        map[int]models.Ocean
+	tags          map[int]models.Tag
+	tagOceans     map[tagOceanKey]bool
+	bottles       map[int]models.Bottle
+	originals     map[int]models.BottleOriginal
+	seen          map[seenKey]time.Time
+	stats         map[int]bottleStats
+	bookmarks     map[seenKey]models.Bookmark
+	replies       map[int]models.Reply
+	notifications map[int]models.Notification
+	preferences   map[uuid.UUID]map[models.NotificationType]bool
+	identities    map[uuid.UUID]models.Identity
+	sessions      map[uuid.UUID]models.IdentitySession
+	userSessions  map[uuid.UUID]models.Session
+	refreshTokens map[string]models.RefreshToken
+	roleChanges   map[int]models.RoleChange
+	deletions     map[uuid.UUID]models.AccountDeletion
+	exports       map[uuid.UUID]models.ExportJob
+	archives      map[uuid.UUID][]byte
+	blocks        map[blockKey]models.Block
+	reports       map[int]models.Report
+	strikes       map[int]models.Strike
+	audit         map[int64]models.AuditEntry
+	tagModels     map[int]models.TagModel
+
+	nextOceanID        int
+	nextTagID          int
+	nextBottleID       int
+	nextReplyID        int
+	nextNotificationID int
+	nextRoleChangeID   int
+	nextReportID       int
+	nextStrikeID       int
+	nextAuditID        int64
+	nextTagModelID     int
+}
+
+// transactor runs units of work by restoring a snapshot of the store if they fail. Top-level
+// units of work run one at a time, but calls made outside of one are not isolated from it:
+// a rollback also undoes whatever they changed in the meantime.
+type transactor struct {
+	store  *Store
+	nested bool
+}
+
+func (t *transactor) WithTx(ctx context.Context, fn func(tx *storage.Repository) error) error {
+	if !t.nested {
+		t.store.txMu.Lock()
+		defer t.store.txMu.Unlock()
+	}
+
+	before := t.store.snapshot()
+
+	repo := newRepository(t.store)
+	repo.Transactor = &transactor{t.store, true}
+
+	if err := fn(repo); err != nil {
+		t.store.restore(before)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) snapshot() snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preferences := make(map[uuid.UUID]map[models.NotificationType]bool, len(s.preferences))
+	for userID, enabled := range s.preferences {
+		preferences[userID] = maps.Clone(enabled)
+	}
+
+	return snapshot{
+		users:              maps.Clone(s.users),
+		oceans:             maps.Clone(s.oceans),
+		tags:               maps.Clone(s.tags),
+		tagOceans:          maps.Clone(s.tagOceans),
+		bottles:            maps.Clone(s.bottles),
+		originals:          maps.Clone(s.originals),
+		seen:               maps.Clone(s.seen),
+		stats:              maps.Clone(s.stats),
+		bookmarks:          maps.Clone(s.bookmarks),
+		replies:            maps.Clone(s.replies),
+		notifications:      maps.Clone(s.notifications),
+		preferences:        preferences,
+		identities:         maps.Clone(s.identities),
+		sessions:           maps.Clone(s.sessions),
+		userSessions:       maps.Clone(s.userSessions),
+		refreshTokens:      maps.Clone(s.refreshTokens),
+		roleChanges:        maps.Clone(s.roleChanges),
+		deletions:          maps.Clone(s.deletions),
+		exports:            maps.Clone(s.exports),
+		archives:           maps.Clone(s.archives),
+		blocks:             maps.Clone(s.blocks),
+		reports:            maps.Clone(s.reports),
+		strikes:            maps.Clone(s.strikes),
+		audit:              maps.Clone(s.audit),
+		tagModels:          maps.Clone(s.tagModels),
+		nextOceanID:        s.nextOceanID,
+		nextTagID:          s.nextTagID,
+		nextBottleID:       s.nextBottleID,
+		nextReplyID:        s.nextReplyID,
+		nextNotificationID: s.nextNotificationID,
+		nextRoleChangeID:   s.nextRoleChangeID,
+		nextReportID:       s.nextReportID,
+		nextStrikeID:       s.nextStrikeID,
+		nextAuditID:        s.nextAuditID,
+		nextTagModelID:     s.nextTagModelID,
+	}
+}
+
+func (s *Store) restore(before snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = before.users
+	s.oceans = before.oceans
+	s.tags = before.tags
+	s.tagOceans = before.tagOceans
+	s.bottles = before.bottles
+	s.originals = before.originals
+	s.seen = before.seen
+	s.stats = before.stats
+	s.bookmarks = before.bookmarks
+	s.replies = before.replies
+	s.notifications = before.notifications
+	s.preferences = before.preferences
+	s.identities = before.identities
+	s.sessions = before.sessions
+	s.userSessions = before.userSessions
+	s.refreshTokens = before.refreshTokens
+	s.roleChanges = before.roleChanges
+	s.deletions = before.deletions
+	s.exports = before.exports
+	s.archives = before.archives
+	s.blocks = before.blocks
+	s.reports = before.reports
+	s.strikes = before.strikes
+	s.audit = before.audit
+	s.tagModels = before.tagModels
+	s.nextOceanID = before.nextOceanID
+	s.nextTagID = before.nextTagID
+	s.nextBottleID = before.nextBottleID
+	s.nextReplyID = before.nextReplyID
+	s.nextNotificationID = before.nextNotificationID
+	s.nextRoleChangeID = before.nextRoleChangeID
+	s.nextReportID = before.nextReportID
+	s.nextStrikeID = before.nextStrikeID
+	s.nextAuditID = before.nextAuditID
+	s.nextTagModelID = before.nextTagModelID
+}
@@ -0,0 +1,281 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func (r *UserRepository) AddUser(ctx context.Context, userID string, firstName *string, lastName *string, email string) (*models.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying database: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; ok {
+		return nil, fmt.Errorf("error querying database: user %s already exists", id)
+	}
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return nil, fmt.Errorf("error querying database: email %s is already in use", email)
+		}
+	}
+
+	user := models.User{ID: id, FirstName: firstName, LastName: lastName, Email: email, Role: models.RoleUser}
+	r.store.users[id] = user
+	return &user, nil
+}
+
+func (r *UserRepository) GetUserProfile(ctx context.Context, userId string) (*models.User, error) {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying database for user: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, errs.NotFound("user", "id", userId)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdateUserProfile(ctx context.Context, userId uuid.UUID, req models.UpdateProfileRequest) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userId]
+	if !ok {
+		return nil, errs.NotFound("user", "id", userId.String())
+	}
+	if req.DisplayName != nil && *req.DisplayName != "" {
+		for id, other := range r.store.users {
+			if id != userId && other.DisplayName != nil && strings.EqualFold(*other.DisplayName, *req.DisplayName) {
+				return nil, errs.Conflict("user", "display_name", *req.DisplayName)
+			}
+		}
+	}
+
+	update := func(field **string, value *string) {
+		if value == nil {
+			return
+		}
+		if *value == "" {
+			*field = nil
+		} else {
+			*field = ptr(*value)
+		}
+	}
+	update(&user.FirstName, req.FirstName)
+	update(&user.LastName, req.LastName)
+	update(&user.DisplayName, req.DisplayName)
+	update(&user.Bio, req.Bio)
+	if req.Languages != nil {
+		user.Languages = slices.Clone(*req.Languages)
+	}
+
+	r.store.users[userId] = user
+	return &user, nil
+}
+
+func (r *UserRepository) SetUserAvatar(ctx context.Context, userId uuid.UUID, avatarKey *string) (*string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userId]
+	if !ok {
+		return nil, errs.NotFound("user", "id", userId.String())
+	}
+	previous := user.AvatarKey
+	user.AvatarKey = avatarKey
+	r.store.users[userId] = user
+	return previous, nil
+}
+
+// DeleteUser removes the user with everything the database would cascade. Their bottles and
+// ocean are kept without an owner.
+func (r *UserRepository) DeleteUser(ctx context.Context, userId string) (string, error) {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return "", fmt.Errorf("error querying database for user: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deleteUser(id)
+	return "User Deleted Successfully", nil
+}
+
+func (r *UserRepository) AnonymizeUser(ctx context.Context, userId uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userId]
+	if !ok {
+		return 0, errs.NotFound("user", "id", userId.String())
+	}
+	user.FirstName, user.LastName, user.DisplayName, user.Bio = nil, nil, nil, nil
+	r.store.users[userId] = user
+
+	return r.store.detachBottles(userId), nil
+}
+
+func (r *UserRepository) GetUserRole(ctx context.Context, userId uuid.UUID) (models.Role, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userId]
+	if !ok {
+		return "", errs.NotFound("user", "id", userId.String())
+	}
+	return user.Role, nil
+}
+
+func (r *UserRepository) SetUserRole(ctx context.Context, userId uuid.UUID, role models.Role, changedBy *uuid.UUID) (*models.RoleChange, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("error setting role: invalid role %q", role)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userId]
+	if !ok {
+		return nil, errs.NotFound("user", "id", userId.String())
+	}
+
+	r.store.nextRoleChangeID++
+	change := models.RoleChange{
+		ID:        r.store.nextRoleChangeID,
+		UserID:    userId,
+		OldRole:   user.Role,
+		NewRole:   role,
+		ChangedBy: changedBy,
+		CreatedAt: now(),
+	}
+	r.store.roleChanges[change.ID] = change
+
+	user.Role = role
+	r.store.users[userId] = user
+	return &change, nil
+}
+
+func (r *UserRepository) GetRoleChanges(ctx context.Context, userId uuid.UUID) ([]models.RoleChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	changes := []models.RoleChange{}
+	for _, change := range r.store.roleChanges {
+		if change.UserID == userId {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortFunc(changes, func(a, b models.RoleChange) int {
+		return b.ID - a.ID
+	})
+	return changes, nil
+}
+
+// deleteUser removes the user with everything the database would cascade: their catches,
+// bookmarks, replies, notifications, sessions, role changes, pending deletion, exports,
+// blocks either way, reports and strikes.
+// Their bottles and ocean are kept without an owner.
+func (s *Store) deleteUser(id uuid.UUID) {
+	delete(s.users, id)
+	delete(s.preferences, id)
+	delete(s.deletions, id)
+	for jobID, job := range s.exports {
+		if job.UserID == id {
+			delete(s.exports, jobID)
+			delete(s.archives, jobID)
+		}
+	}
+	for key := range s.seen {
+		if key.userID == id {
+			delete(s.seen, key)
+		}
+	}
+	for key := range s.bookmarks {
+		if key.userID == id {
+			delete(s.bookmarks, key)
+		}
+	}
+	for replyID, reply := range s.replies {
+		if reply.UserID == id {
+			delete(s.replies, replyID)
+		}
+	}
+	for sessionID, session := range s.userSessions {
+		if session.UserID == id {
+			s.deleteSession(sessionID)
+		}
+	}
+	for notificationID, notification := range s.notifications {
+		if notification.UserID == id {
+			delete(s.notifications, notificationID)
+		}
+	}
+	for key := range s.blocks {
+		if key.blockerID == id || key.blockedID == id {
+			delete(s.blocks, key)
+		}
+	}
+	for reportID, report := range s.reports {
+		if report.ReporterID == id {
+			delete(s.reports, reportID)
+		} else if report.ResolvedBy != nil && *report.ResolvedBy == id {
+			report.ResolvedBy = nil
+			s.reports[reportID] = report
+		}
+	}
+	for strikeID, strike := range s.strikes {
+		if strike.UserID == id {
+			delete(s.strikes, strikeID)
+		} else if strike.IssuedBy != nil && *strike.IssuedBy == id {
+			strike.IssuedBy = nil
+			s.strikes[strikeID] = strike
+		}
+	}
+	for changeID, change := range s.roleChanges {
+		if change.UserID == id {
+			delete(s.roleChanges, changeID)
+		} else if change.ChangedBy != nil && *change.ChangedBy == id {
+			change.ChangedBy = nil
+			s.roleChanges[changeID] = change
+		}
+	}
+	for bottleID, bottle := range s.bottles {
+		if bottle.UserID != nil && *bottle.UserID == id {
+			bottle.UserID = nil
+			s.bottles[bottleID] = bottle
+		}
+	}
+	for oceanID, ocean := range s.oceans {
+		if ocean.UserID != nil && *ocean.UserID == id {
+			ocean.UserID = nil
+			s.oceans[oceanID] = ocean
+		}
+	}
+
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{
+		store,
+	}
+}
@@ -0,0 +1,95 @@
+package postgres_test
+
+import (
+	"context"
+	"hackmit/internal/config"
+	"hackmit/internal/migrate"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"hackmit/internal/storage/postgres"
+	"hackmit/internal/storage/storagetest"
+	"hackmit/internal/supabase"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sethvargo/go-envconfig"
+)
+
+// TestConformance runs the storage suite against a Supabase Postgres configured with TEST_DB_HOST,
+// TEST_DB_PORT, TEST_DB_USER, TEST_DB_PASSWORD and TEST_DB_NAME. Every table is emptied between
+// tests, so it must never point at a database whose data matters.
+func TestConformance(t *testing.T) {
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST is not set; skipping the Postgres conformance suite")
+	}
+
+	ctx := context.Background()
+
+	var cfg config.DB
+	err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   &cfg,
+		Lookuper: envconfig.PrefixLookuper("TEST_", envconfig.OsLookuper()),
+	})
+	if err != nil {
+		t.Fatalf("reading test database settings: %v", err)
+	}
+
+	db, err := postgres.ConnectDatabase(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	migrator, err := migrate.New(db, supabase.Files)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating the test database: %v", err)
+	}
+
+	storagetest.Run(t, storagetest.Harness{
+		New: func(t *testing.T) *storage.Repository {
+			reset(t, db, migrator)
+			return storage.NewRepository(db)
+		},
+		AddIdentity: func(t *testing.T, userID uuid.UUID, email string) {
+			if _, err := db.Exec(ctx, `INSERT INTO auth.users (id, email) VALUES ($1, $2)`, userID, email); err != nil {
+				t.Fatalf("creating auth user: %v", err)
+			}
+		},
+		AddTag: func(t *testing.T, repo *storage.Repository, name string) models.Tag {
+			tag := models.Tag{Name: &name}
+			if err := db.QueryRow(ctx, `INSERT INTO tag (name, color) VALUES ($1, '#4DB6AC') RETURNING id`, name).Scan(&tag.ID); err != nil {
+				t.Fatalf("creating tag: %v", err)
+			}
+			return tag
+		},
+	})
+}
+
+// reset empties every application table and restores the seed data.
+func reset(t *testing.T, db *pgxpool.Pool, migrator *migrate.Migrator) {
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, `
+		TRUNCATE "user", ocean, tag, bottle, tag_ocean, seen_bottles, bottle_stats, bottle_reply,
+			bookmark, notification, notification_preference, local_identity, local_session,
+			user_session, session_refresh_token, role_change, account_deletion, export_job, user_block,
+			bottle_report, user_strike, audit_log, bottle_original, tag_model
+		RESTART IDENTITY CASCADE
+	`)
+	if err != nil {
+		t.Fatalf("emptying the test database: %v", err)
+	}
+
+	if _, err := db.Exec(ctx, `DELETE FROM auth.users WHERE email LIKE '%@storagetest.invalid'`); err != nil {
+		t.Fatalf("removing test auth users: %v", err)
+	}
+
+	if err := migrator.Seed(ctx); err != nil {
+		t.Fatalf("seeding the test database: %v", err)
+	}
+}
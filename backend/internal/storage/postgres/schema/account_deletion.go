@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const accountDeletionColumns = `user_id, keep_bottles, requested_at, delete_after`
+
+func (c *UserRepository) ScheduleDeletion(ctx context.Context, userId uuid.UUID, keepBottles bool, deleteAfter time.Time) (*models.AccountDeletion, error) {
+	query := `
+		INSERT INTO account_deletion (user_id, keep_bottles, delete_after)
+		SELECT id, $2, $3 FROM "user" WHERE id = $1
+		ON CONFLICT (user_id) DO NOTHING
+		RETURNING ` + accountDeletionColumns
+
+	rows, err := c.db.Query(ctx, query, userId, keepBottles, deleteAfter)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling deletion: %w", err)
+	}
+	defer rows.Close()
+
+	deletion, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AccountDeletion])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Either there is no such user or their deletion is already scheduled
+			if _, err := c.GetDeletion(ctx, userId); err != nil {
+				return nil, err
+			}
+			return nil, errs.Conflict("account deletion", "user_id", userId.String())
+		}
+		return nil, fmt.Errorf("error collecting deletion: %w", err)
+	}
+
+	return &deletion, nil
+}
+
+func (c *UserRepository) GetDeletion(ctx context.Context, userId uuid.UUID) (*models.AccountDeletion, error) {
+	query := `SELECT ` + accountDeletionColumns + ` FROM account_deletion WHERE user_id = $1`
+
+	rows, err := c.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying deletion: %w", err)
+	}
+	defer rows.Close()
+
+	deletion, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AccountDeletion])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("account deletion", "user_id", userId.String())
+		}
+		return nil, fmt.Errorf("error collecting deletion: %w", err)
+	}
+
+	return &deletion, nil
+}
+
+func (c *UserRepository) CancelDeletion(ctx context.Context, userId uuid.UUID) error {
+	const query = `DELETE FROM account_deletion WHERE user_id = $1`
+	tag, err := c.db.Exec(ctx, query, userId)
+	if err != nil {
+		return fmt.Errorf("error cancelling deletion: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("account deletion", "user_id", userId.String())
+	}
+	return nil
+}
+
+func (c *UserRepository) GetDueDeletions(ctx context.Context, at time.Time) ([]models.AccountDeletion, error) {
+	query := `
+		SELECT ` + accountDeletionColumns + `
+		FROM account_deletion
+		WHERE delete_after <= $1
+		ORDER BY delete_after`
+
+	rows, err := c.db.Query(ctx, query, at)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due deletions: %w", err)
+	}
+	defer rows.Close()
+
+	deletions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AccountDeletion])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting due deletions: %w", err)
+	}
+
+	return deletions, nil
+}
+
+// PurgeUser deletes the user's bottles or detaches them from the user, then their oceans and
+// profile. Seen history, bookmarks, replies, notifications, sessions and the pending deletion
+// go with the profile by cascade.
+func (c *UserRepository) PurgeUser(ctx context.Context, userId uuid.UUID, keepBottles bool) (int64, error) {
+	var bottles int64
+	err := pgx.BeginFunc(ctx, c.db, func(tx pgx.Tx) error {
+		if keepBottles {
+			detached, err := detachBottles(ctx, tx, userId)
+			if err != nil {
+				return err
+			}
+			bottles = detached
+		} else {
+			tag, err := tx.Exec(ctx, `DELETE FROM bottle WHERE user_id = $1`, userId)
+			if err != nil {
+				return err
+			}
+			bottles = tag.RowsAffected()
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM ocean WHERE user_id = $1`, userId); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `DELETE FROM "user" WHERE id = $1`, userId)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errs.NotFound("user", "id", userId.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error purging user: %w", err)
+	}
+
+	return bottles, nil
+}
+
+// detachBottles strips the user's bottles of their author and user_id, and the originals of
+// those that were masked of the author's name as written, returning how many were detached.
+func detachBottles(ctx context.Context, tx pgx.Tx, userId uuid.UUID) (int64, error) {
+	const originals = `
+		UPDATE bottle_original o SET author = NULL
+		FROM bottle b
+		WHERE b.id = o.bottle_id AND b.user_id = $1`
+	if _, err := tx.Exec(ctx, originals, userId); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE bottle SET author = NULL, user_id = NULL WHERE user_id = $1`, userId)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AuditRepository struct {
+	db DBTX
+}
+
+const auditColumns = `id, action, actor_id, target_type, target_id, reason, details, created_at`
+
+func (r *AuditRepository) RecordAudit(ctx context.Context, entry models.AuditEntry) (*models.AuditEntry, error) {
+	query := `
+		INSERT INTO audit_log (action, actor_id, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + auditColumns
+
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	rows, err := r.db.Query(ctx, query, entry.Action, entry.ActorID, entry.TargetType, entry.TargetID, entry.Reason, details)
+	if err != nil {
+		return nil, fmt.Errorf("error recording audit entry: %w", err)
+	}
+	defer rows.Close()
+
+	recorded, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.AuditEntry])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting audit entry: %w", err)
+	}
+
+	return &recorded, nil
+}
+
+func (r *AuditRepository) ListAudit(ctx context.Context, filterParams models.ListAuditRequest, page models.PaginationRequest) ([]models.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + ` FROM audit_log WHERE 1=1`
+	var args []any
+
+	if filterParams.Action != nil {
+		args = append(args, *filterParams.Action)
+		query += fmt.Sprintf(` AND action = $%d`, len(args))
+	}
+
+	if filterParams.ActorID != nil {
+		args = append(args, *filterParams.ActorID)
+		query += fmt.Sprintf(` AND actor_id = $%d`, len(args))
+	}
+
+	if filterParams.TargetType != nil {
+		args = append(args, *filterParams.TargetType)
+		query += fmt.Sprintf(` AND target_type = $%d`, len(args))
+	}
+
+	if filterParams.TargetID != nil {
+		args = append(args, *filterParams.TargetID)
+		query += fmt.Sprintf(` AND target_id = $%d`, len(args))
+	}
+
+	if filterParams.Since != nil {
+		args = append(args, *filterParams.Since)
+		query += fmt.Sprintf(` AND created_at >= $%d`, len(args))
+	}
+
+	if filterParams.Until != nil {
+		args = append(args, *filterParams.Until)
+		query += fmt.Sprintf(` AND created_at < $%d`, len(args))
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuditEntry])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *AuditRepository) DeleteAuditBefore(ctx context.Context, before time.Time) (int64, error) {
+	const query = `DELETE FROM audit_log WHERE created_at < $1`
+	tag, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting audit entries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func NewAuditRepository(db DBTX) *AuditRepository {
+	return &AuditRepository{
+		db,
+	}
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type BlockRepository struct {
+	db DBTX
+}
+
+const blockColumns = `blocker_id, blocked_id, bottle_id, created_at`
+
+func (r *BlockRepository) BlockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID, bottleId *int) (*models.Block, error) {
+	query := `
+		INSERT INTO user_block (blocker_id, blocked_id, bottle_id)
+		SELECT $1, id, $3 FROM "user" WHERE id = $2
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+		RETURNING ` + blockColumns
+
+	rows, err := r.db.Query(ctx, query, blockerId, blockedId, bottleId)
+	if err != nil {
+		return nil, fmt.Errorf("error blocking user: %w", err)
+	}
+	defer rows.Close()
+
+	block, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Block])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Either there is no such user or they are already blocked
+			var exists bool
+			if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM "user" WHERE id = $1)`, blockedId).Scan(&exists); err != nil {
+				return nil, fmt.Errorf("error blocking user: %w", err)
+			}
+			if !exists {
+				return nil, errs.NotFound("user", "id", blockedId.String())
+			}
+			return nil, errs.Conflict("You have already blocked this user")
+		}
+		return nil, fmt.Errorf("error collecting block: %w", err)
+	}
+
+	return &block, nil
+}
+
+func (r *BlockRepository) UnblockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_block WHERE blocker_id = $1 AND blocked_id = $2`, blockerId, blockedId)
+	if err != nil {
+		return fmt.Errorf("error unblocking user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("block", "user_id", blockedId.String())
+	}
+	return nil
+}
+
+func (r *BlockRepository) UnblockBottleAuthor(ctx context.Context, blockerId uuid.UUID, bottleId int) error {
+	const query = `
+		DELETE FROM user_block
+		WHERE blocker_id = $1
+		AND (bottle_id = $2 OR blocked_id = (SELECT user_id FROM bottle WHERE id = $2))
+	`
+
+	tag, err := r.db.Exec(ctx, query, blockerId, bottleId)
+	if err != nil {
+		return fmt.Errorf("error unblocking bottle author: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("block", "bottle_id", fmt.Sprint(bottleId))
+	}
+	return nil
+}
+
+// GetBlocks lists the users the blocker has blocked, most recent first.
+func (r *BlockRepository) GetBlocks(ctx context.Context, blockerId uuid.UUID) ([]models.Block, error) {
+	query := `SELECT ` + blockColumns + ` FROM user_block WHERE blocker_id = $1 ORDER BY created_at DESC, blocked_id`
+
+	rows, err := r.db.Query(ctx, query, blockerId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Block])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, userId uuid.UUID, otherId uuid.UUID) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM user_block
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+
+	var blocked bool
+	if err := r.db.QueryRow(ctx, query, userId, otherId).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("error querying blocks: %w", err)
+	}
+	return blocked, nil
+}
+
+func NewBlockRepository(db DBTX) *BlockRepository {
+	return &BlockRepository{
+		db,
+	}
+}
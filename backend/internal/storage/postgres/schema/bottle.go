@@ -7,12 +7,12 @@ import (
 	"hackmit/internal/models"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type BottleRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
 func (r *BottleRepository) CreateBottle(ctx context.Context, req models.CreateBottleRequest) (*models.Bottle, error) {
@@ -41,17 +41,49 @@ func (r *BottleRepository) CreateBottle(ctx context.Context, req models.CreateBo
 		columns = append(columns, "location_from")
 	}
 
+	if req.Status != nil {
+		values = append(values, *req.Status)
+		columns = append(columns, "status")
+	}
+
+	if req.Mood != nil {
+		values = append(values, *req.Mood, req.MoodScore)
+		columns = append(columns, "mood", "mood_score")
+	}
+
+	if req.Language != nil {
+		values = append(values, *req.Language)
+		columns = append(columns, "language")
+	}
+
+	if req.TagSource != nil {
+		values = append(values, *req.TagSource)
+		columns = append(columns, "tag_source")
+	}
+
 	var numInputs []string
 	for i := 1; i <= len(columns); i++ {
 		numInputs = append(numInputs, fmt.Sprintf("$%d", i))
 	}
 
+	const returning = `id, content, author, tag_id, user_id, location_from, created_at, status, mood, mood_score, language, tag_source`
 	query := `
 		INSERT INTO bottle
 		(` + strings.Join(columns, ", ") + `)
 		VALUES (` + strings.Join(numInputs, ", ") + `)
-		RETURNING id, content, author, tag_id, user_id, location_from, created_at;
-	`
+		RETURNING ` + returning
+
+	// The original of a masked bottle is stored in the same statement, so neither exists without the other
+	if req.Original != nil {
+		values = append(values, req.Original.Content, req.Original.Author, req.Original.LocationFrom)
+		query = `
+			WITH created AS (` + query + `
+			), original AS (
+				INSERT INTO bottle_original (bottle_id, content, author, location_from)
+				SELECT id, ` + fmt.Sprintf("$%d, $%d, $%d", len(values)-2, len(values)-1, len(values)) + ` FROM created
+			)
+			SELECT ` + returning + ` FROM created`
+	}
 
 	rows, _ := r.db.Query(ctx, query, values...)
 	bottle, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Bottle])
@@ -63,6 +95,49 @@ func (r *BottleRepository) CreateBottle(ctx context.Context, req models.CreateBo
 	return &bottle, nil
 }
 
+func (r *BottleRepository) GetBottleByID(ctx context.Context, bottleId int) (*models.Bottle, error) {
+	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
+		FROM bottle b
+		WHERE b.id = $1
+	`
+
+	rows, err := r.db.Query(ctx, query, bottleId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying bottle: %w", err)
+	}
+	defer rows.Close()
+
+	bottle, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+		}
+		return nil, fmt.Errorf("error collecting bottle: %w", err)
+	}
+
+	return &bottle, nil
+}
+
+func (r *BottleRepository) GetBottleOriginal(ctx context.Context, bottleId int) (*models.BottleOriginal, error) {
+	const query = `SELECT content, author, location_from FROM bottle_original WHERE bottle_id = $1`
+
+	rows, err := r.db.Query(ctx, query, bottleId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying bottle original: %w", err)
+	}
+	defer rows.Close()
+
+	original, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.BottleOriginal])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("bottle original", "bottle_id", fmt.Sprint(bottleId))
+		}
+		return nil, fmt.Errorf("error collecting bottle original: %w", err)
+	}
+
+	return &original, nil
+}
+
 func (r *BottleRepository) DeleteBottle(ctx context.Context, bottleId int) (string, error) {
 	const query = `DELETE FROM bottle WHERE id = $1`
 	_, err := r.db.Exec(ctx, query, bottleId)
@@ -73,16 +148,29 @@ func (r *BottleRepository) DeleteBottle(ctx context.Context, bottleId int) (stri
 }
 
 func (r *BottleRepository) GetBottles(ctx context.Context, filterParams models.GetBottlesRequest) ([]models.Bottle, error) {
-	query := `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at
+	query := `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
 		FROM bottle b
 		WHERE b.tag_id in (
 			SELECT tag_id FROM tag_ocean
 			WHERE ocean_id = $1
 		)
-		ORDER BY RANDOM()
+		AND b.status = 'afloat'
 	`
-	fmt.Println(filterParams.OceanID)
-	rows, err := r.db.Query(ctx, query, filterParams.OceanID)
+	queryArgs := []any{filterParams.OceanID}
+
+	if filterParams.ViewerID != nil {
+		queryArgs = append(queryArgs, *filterParams.ViewerID)
+		query += fmt.Sprintf(` AND NOT %s`, blockedBy(len(queryArgs)))
+	}
+
+	if filterParams.Mood != nil {
+		queryArgs = append(queryArgs, *filterParams.Mood)
+		query += fmt.Sprintf(` AND b.mood = $%d`, len(queryArgs))
+	}
+
+	query += ` ORDER BY RANDOM()`
+
+	rows, err := r.db.Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -96,21 +184,33 @@ func (r *BottleRepository) GetBottles(ctx context.Context, filterParams models.G
 	return bottles, nil
 }
 
-func (r *BottleRepository) GetBottlesByUser(ctx context.Context, userId int) ([]models.Bottle, error) {
-	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at
+// GetBottlesByUser lists an author's bottles, newest first, with how often each has been found.
+func (r *BottleRepository) GetBottlesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.AuthoredBottle, error) {
+	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source,
+			COALESCE(st.catch_count, 0) AS catch_count,
+			st.first_caught_at,
+			st.last_caught_at,
+			(SELECT count(*) FROM bottle_reply r WHERE r.bottle_id = b.id) AS reply_count,
+			CASE
+				WHEN b.status = 'afloat' AND COALESCE(st.catch_count, 0) > 0 THEN 'found'
+				ELSE b.status
+			END AS state
 		FROM bottle b
-		WHERE user_id = $1
+		LEFT JOIN bottle_stats st ON st.bottle_id = b.id
+		WHERE b.user_id = $1
+		ORDER BY b.created_at DESC, b.id DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, userId)
+	rows, err := r.db.Query(ctx, query, userId, page.Limit, page.Offset)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error querying bottles by user: %w", err)
 	}
 	defer rows.Close()
 
-	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Bottle])
+	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuthoredBottle])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error collecting bottles by user: %w", err)
 	}
 
 	return bottles, nil
@@ -122,7 +222,7 @@ func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams mod
 	var_counter := 2
 
 	if ocean.UserID != nil {
-		query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at
+		query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
 			FROM bottle b
 			WHERE b.tag_id in (
 				SELECT tag_id FROM tag_ocean
@@ -131,16 +231,18 @@ func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams mod
 				AND tag.name='Personal'
 			)
 				AND b.user_id = $2
+				AND b.status = 'afloat'
 			`
 		queryArgs = append(queryArgs, *ocean.UserID)
 		var_counter += 1
 	} else {
-		query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at
+		query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
 			FROM bottle b
 			WHERE b.tag_id in (
 				SELECT tag_id FROM tag_ocean
 				WHERE tag_ocean.ocean_id = $1
-			)`
+			)
+				AND b.status = 'afloat'`
 	}
 
 	// filter out bottles already seen by users
@@ -149,6 +251,26 @@ func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams mod
 			SELECT bottle_id from seen_bottles where user_id = $%d
 		)`, var_counter)
 		queryArgs = append(queryArgs, *filterParams.SeenByUserId)
+		var_counter += 1
+	}
+
+	// leave out authors the reader has blocked
+	if filterParams.ViewerID != nil {
+		query += ` AND NOT ` + blockedBy(var_counter)
+		queryArgs = append(queryArgs, *filterParams.ViewerID)
+		var_counter += 1
+	}
+
+	if filterParams.Mood != nil {
+		query += fmt.Sprintf(` AND b.mood = $%d`, var_counter)
+		queryArgs = append(queryArgs, *filterParams.Mood)
+		var_counter += 1
+	}
+
+	// bottles whose language couldn't be told are caught by readers of any language
+	if len(filterParams.Languages) > 0 {
+		query += fmt.Sprintf(` AND (b.language IS NULL OR b.language = 'und' OR b.language = ANY($%d))`, var_counter)
+		queryArgs = append(queryArgs, filterParams.Languages)
 	}
 
 	query += ` ORDER BY RANDOM() 
@@ -170,10 +292,19 @@ func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams mod
 	}
 
 	if filterParams.SeenByUserId != nil {
+		// A catch only counts towards the bottle's statistics if it is new to this reader
 		query = `
-			INSERT INTO seen_bottles (user_id, bottle_id)
-			VALUES ($1, $2)
-			ON CONFLICT (user_id, bottle_id) DO NOTHING;
+			WITH seen AS (
+				INSERT INTO seen_bottles (user_id, bottle_id)
+				VALUES ($1, $2)
+				ON CONFLICT (user_id, bottle_id) DO NOTHING
+				RETURNING bottle_id, seen_at
+			)
+			INSERT INTO bottle_stats (bottle_id, catch_count, first_caught_at, last_caught_at)
+			SELECT bottle_id, 1, seen_at, seen_at FROM seen
+			ON CONFLICT (bottle_id) DO UPDATE
+				SET catch_count = bottle_stats.catch_count + 1,
+					last_caught_at = EXCLUDED.last_caught_at;
 		`
 		_, err = r.db.Exec(ctx, query, filterParams.SeenByUserId, bottle.ID)
 		if err != nil {
@@ -184,7 +315,15 @@ func (r *BottleRepository) GetRandomBottle(ctx context.Context, filterParams mod
 	return &bottle, nil
 }
 
-func NewBottleRepository(db *pgxpool.Pool) *BottleRepository {
+// blockedBy is a condition on a bottle b that holds when its author has been blocked by the
+// user in the given query parameter.
+func blockedBy(param int) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM user_block ub WHERE ub.blocker_id = $%d AND ub.blocked_id = b.user_id
+	)`, param)
+}
+
+func NewBottleRepository(db DBTX) *BottleRepository {
 	return &BottleRepository{
 		db,
 	}
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const catchColumns = `b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source,
+	s.seen_at, (bm.bottle_id IS NOT NULL) AS bookmarked, bm.note`
+
+// GetCatches lists the bottles the user has caught that are still afloat, leaving out those of
+// authors they blocked.
+func (r *BottleRepository) GetCatches(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error) {
+	query := `SELECT ` + catchColumns + `
+		FROM seen_bottles s
+		JOIN bottle b ON b.id = s.bottle_id
+		LEFT JOIN bookmark bm ON bm.user_id = s.user_id AND bm.bottle_id = s.bottle_id
+		WHERE s.user_id = $1
+			AND b.status = 'afloat'
+			AND NOT ` + blockedBy(1) + `
+		ORDER BY s.seen_at DESC, b.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying catches: %w", err)
+	}
+	defer rows.Close()
+
+	catches, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Catch])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting catches: %w", err)
+	}
+
+	return catches, nil
+}
+
+// GetBookmarks lists the caught bottles the user kept, as GetCatches does.
+func (r *BottleRepository) GetBookmarks(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error) {
+	query := `SELECT ` + catchColumns + `
+		FROM bookmark bm
+		JOIN seen_bottles s ON s.user_id = bm.user_id AND s.bottle_id = bm.bottle_id
+		JOIN bottle b ON b.id = bm.bottle_id
+		WHERE bm.user_id = $1
+			AND b.status = 'afloat'
+			AND NOT ` + blockedBy(1) + `
+		ORDER BY bm.created_at DESC, b.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Catch])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// SaveBookmark keeps a caught bottle in the user's collection, updating the note if it is already kept.
+func (r *BottleRepository) SaveBookmark(ctx context.Context, userId uuid.UUID, bottleId int, note *string) (*models.Bookmark, error) {
+	const query = `
+		INSERT INTO bookmark (user_id, bottle_id, note)
+		SELECT $1::uuid, $2::int, $3::varchar
+		WHERE EXISTS (
+			SELECT 1 FROM seen_bottles WHERE user_id = $1 AND bottle_id = $2
+		)
+		ON CONFLICT (user_id, bottle_id) DO UPDATE
+			SET note = EXCLUDED.note, updated_at = CURRENT_TIMESTAMP
+		RETURNING user_id, bottle_id, note, created_at, updated_at
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, bottleId, note)
+	if err != nil {
+		return nil, fmt.Errorf("error saving bookmark: %w", err)
+	}
+	defer rows.Close()
+
+	bookmark, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Bookmark])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("You have not caught this bottle")
+		}
+		return nil, fmt.Errorf("error collecting bookmark: %w", err)
+	}
+
+	return &bookmark, nil
+}
+
+func (r *BottleRepository) DeleteBookmark(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	const query = `DELETE FROM bookmark WHERE user_id = $1 AND bottle_id = $2`
+	tag, err := r.db.Exec(ctx, query, userId, bottleId)
+	if err != nil {
+		return fmt.Errorf("error deleting bookmark: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("bookmark", "bottle_id", fmt.Sprint(bottleId))
+	}
+	return nil
+}
+
+// ThrowBack removes a bottle from the user's seen set (and collection) so they can catch it again.
+func (r *BottleRepository) ThrowBack(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	const query = `
+		WITH released AS (
+			DELETE FROM seen_bottles WHERE user_id = $1 AND bottle_id = $2
+			RETURNING bottle_id
+		), unkept AS (
+			DELETE FROM bookmark WHERE user_id = $1 AND bottle_id IN (SELECT bottle_id FROM released)
+		)
+		SELECT count(*) FROM released
+	`
+
+	var released int64
+	if err := r.db.QueryRow(ctx, query, userId, bottleId).Scan(&released); err != nil {
+		return fmt.Errorf("error throwing bottle back: %w", err)
+	}
+	if released == 0 {
+		return errs.NotFound("You have not caught this bottle")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ExportRepository struct {
+	db DBTX
+}
+
+// exportJobColumns leaves out the archive, which is only read to be downloaded.
+const exportJobColumns = `id, user_id, status, error, created_at, started_at, completed_at, expires_at`
+
+func (r *ExportRepository) CreateExportJob(ctx context.Context, userId uuid.UUID) (*models.ExportJob, error) {
+	query := `
+		INSERT INTO export_job (user_id)
+		VALUES ($1)
+		ON CONFLICT DO NOTHING
+		RETURNING ` + exportJobColumns
+
+	job, err := r.collectJob(ctx, query, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.Conflict("An export of your data is already in progress")
+		}
+		return nil, fmt.Errorf("error creating export job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *ExportRepository) GetExportJob(ctx context.Context, jobId uuid.UUID) (*models.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_job WHERE id = $1`
+
+	job, err := r.collectJob(ctx, query, jobId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("export", "id", jobId.String())
+		}
+		return nil, fmt.Errorf("error querying export job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimExportJob skips jobs other workers have locked, so that each job is built once.
+func (r *ExportRepository) ClaimExportJob(ctx context.Context, staleBefore time.Time) (*models.ExportJob, error) {
+	query := `
+		UPDATE export_job
+		SET status = 'running', started_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM export_job
+			WHERE status = 'pending' OR (status = 'running' AND started_at < $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportJobColumns
+
+	job, err := r.collectJob(ctx, query, staleBefore)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("no export jobs are waiting")
+		}
+		return nil, fmt.Errorf("error claiming export job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *ExportRepository) CompleteExportJob(ctx context.Context, jobId uuid.UUID, archive []byte, expiresAt time.Time) error {
+	const query = `
+		UPDATE export_job
+		SET status = 'ready', archive = $2, expires_at = $3, error = NULL, completed_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, jobId, archive, expiresAt)
+	if err != nil {
+		return fmt.Errorf("error completing export job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("export", "id", jobId.String())
+	}
+	return nil
+}
+
+func (r *ExportRepository) FailExportJob(ctx context.Context, jobId uuid.UUID, message string) error {
+	const query = `
+		UPDATE export_job
+		SET status = 'failed', error = $2, completed_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, jobId, message)
+	if err != nil {
+		return fmt.Errorf("error failing export job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("export", "id", jobId.String())
+	}
+	return nil
+}
+
+func (r *ExportRepository) GetExportArchive(ctx context.Context, jobId uuid.UUID) ([]byte, error) {
+	const query = `
+		SELECT archive FROM export_job
+		WHERE id = $1 AND status = 'ready' AND expires_at > CURRENT_TIMESTAMP`
+
+	var archive []byte
+	if err := r.db.QueryRow(ctx, query, jobId).Scan(&archive); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("export", "id", jobId.String())
+		}
+		return nil, fmt.Errorf("error querying export archive: %w", err)
+	}
+	return archive, nil
+}
+
+func (r *ExportRepository) DeleteExpiredExports(ctx context.Context, at time.Time) (int64, error) {
+	const query = `DELETE FROM export_job WHERE expires_at <= $1`
+	tag, err := r.db.Exec(ctx, query, at)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting expired exports: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *ExportRepository) collectJob(ctx context.Context, query string, args ...any) (*models.ExportJob, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	job, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.ExportJob])
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func NewExportRepository(db DBTX) *ExportRepository {
+	return &ExportRepository{
+		db,
+	}
+}
@@ -0,0 +1,214 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type IdentityRepository struct {
+	db DBTX
+}
+
+const identityColumns = `user_id, email, password_hash, created_at, updated_at, recovery_token_hash`
+
+const sessionColumns = `id, user_id, refresh_token_hash, created_at, refreshed_at, expires_at, revoked_at`
+
+func (r *IdentityRepository) CreateIdentity(ctx context.Context, email string, passwordHash string) (*models.Identity, error) {
+	query := `
+		INSERT INTO local_identity (email, password_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (email) DO NOTHING
+		RETURNING ` + identityColumns
+
+	rows, err := r.db.Query(ctx, query, email, passwordHash)
+	if err != nil {
+		return nil, fmt.Errorf("error creating identity: %w", err)
+	}
+	defer rows.Close()
+
+	identity, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Identity])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.Conflict("identity", "email", email)
+		}
+		return nil, fmt.Errorf("error collecting identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *IdentityRepository) GetIdentityByEmail(ctx context.Context, email string) (*models.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM local_identity WHERE email = $1`
+
+	rows, err := r.db.Query(ctx, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("error querying identity: %w", err)
+	}
+	defer rows.Close()
+
+	identity, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Identity])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("identity", "email", email)
+		}
+		return nil, fmt.Errorf("error collecting identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *IdentityRepository) UpdatePasswordHash(ctx context.Context, userId uuid.UUID, passwordHash string) error {
+	const query = `UPDATE local_identity SET password_hash = $2, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1`
+	tag, err := r.db.Exec(ctx, query, userId, passwordHash)
+	if err != nil {
+		return fmt.Errorf("error updating password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	return nil
+}
+
+func (r *IdentityRepository) SetRecoveryTokenHash(ctx context.Context, userId uuid.UUID, tokenHash string) error {
+	const query = `UPDATE local_identity SET recovery_token_hash = $2 WHERE user_id = $1`
+	tag, err := r.db.Exec(ctx, query, userId, tokenHash)
+	if err != nil {
+		return fmt.Errorf("error setting recovery token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	return nil
+}
+
+func (r *IdentityRepository) ResetPassword(ctx context.Context, userId uuid.UUID, tokenHash string, passwordHash string) error {
+	const query = `
+		UPDATE local_identity
+		SET password_hash = $3, recovery_token_hash = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND recovery_token_hash = $2
+	`
+	tag, err := r.db.Exec(ctx, query, userId, tokenHash, passwordHash)
+	if err != nil {
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("recovery token not found")
+	}
+	return nil
+}
+
+// DeleteIdentity removes the account and, by cascade, its sessions.
+func (r *IdentityRepository) DeleteIdentity(ctx context.Context, userId uuid.UUID) error {
+	const query = `DELETE FROM local_identity WHERE user_id = $1`
+	tag, err := r.db.Exec(ctx, query, userId)
+	if err != nil {
+		return fmt.Errorf("error deleting identity: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("identity", "user_id", userId.String())
+	}
+	return nil
+}
+
+func (r *IdentityRepository) CreateSession(ctx context.Context, userId uuid.UUID, refreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error) {
+	query := `
+		INSERT INTO local_session (user_id, refresh_token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING ` + sessionColumns
+
+	rows, err := r.db.Query(ctx, query, userId, refreshTokenHash, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.IdentitySession])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RotateSession swaps the refresh token of the live session holding refreshTokenHash for a new one,
+// extending the session to expiresAt. Revoked and expired sessions are not found.
+func (r *IdentityRepository) RotateSession(ctx context.Context, refreshTokenHash string, newRefreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error) {
+	query := `
+		UPDATE local_session
+		SET refresh_token_hash = $2, expires_at = $3, refreshed_at = CURRENT_TIMESTAMP
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING ` + sessionColumns
+
+	rows, err := r.db.Query(ctx, query, refreshTokenHash, newRefreshTokenHash, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error rotating session: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.IdentitySession])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("session not found")
+		}
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *IdentityRepository) GetSession(ctx context.Context, sessionId uuid.UUID) (*models.IdentitySession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM local_session WHERE id = $1`
+
+	rows, err := r.db.Query(ctx, query, sessionId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying session: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.IdentitySession])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("session", "id", sessionId.String())
+		}
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RevokeSession ends a session. Revoking it again keeps the original revocation time.
+func (r *IdentityRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	const query = `UPDATE local_session SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP) WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, sessionId)
+	if err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("session", "id", sessionId.String())
+	}
+	return nil
+}
+
+func (r *IdentityRepository) RevokeSessions(ctx context.Context, userId uuid.UUID, except uuid.UUID) (int64, error) {
+	const query = `
+		UPDATE local_session SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, userId, except)
+	if err != nil {
+		return 0, fmt.Errorf("error revoking sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func NewIdentityRepository(db DBTX) *IdentityRepository {
+	return &IdentityRepository{
+		db,
+	}
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *BottleRepository) GetUndetectedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
+		FROM bottle b
+		WHERE b.language IS NULL
+		ORDER BY b.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying undetected bottles: %w", err)
+	}
+	defer rows.Close()
+
+	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting undetected bottles: %w", err)
+	}
+
+	return bottles, nil
+}
+
+func (r *BottleRepository) SetBottleLanguage(ctx context.Context, bottleId int, language models.Language) error {
+	const query = `UPDATE bottle SET language = $2 WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, bottleId, language)
+	if err != nil {
+		return fmt.Errorf("error setting bottle language: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListBottles returns bottles in any state, oldest first, for operators reviewing or exporting them.
+// Unlike GetBottles it ignores who may see a bottle, except that an ocean only holds the
+// personal bottles of its own owner.
+func (r *BottleRepository) ListBottles(ctx context.Context, filterParams models.ListBottlesRequest, page models.PaginationRequest) ([]models.Bottle, error) {
+	query := `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
+		FROM bottle b
+		WHERE 1=1
+	`
+	var args []any
+
+	if filterParams.OceanID != nil {
+		args = append(args, *filterParams.OceanID)
+		query += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM tag_ocean t
+			JOIN ocean o ON o.id = t.ocean_id
+			WHERE t.tag_id = b.tag_id
+			AND o.id = $%d
+			AND (o.user_id IS NULL OR o.user_id = b.user_id)
+		)`, len(args))
+	}
+
+	if filterParams.Status != nil {
+		args = append(args, *filterParams.Status)
+		query += fmt.Sprintf(` AND b.status = $%d`, len(args))
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	query += fmt.Sprintf(` ORDER BY b.created_at, b.id LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing bottles: %w", err)
+	}
+	defer rows.Close()
+
+	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting bottles: %w", err)
+	}
+
+	return bottles, nil
+}
+
+// SetBottleStatus moves a bottle to a new lifecycle state and returns it as updated.
+func (r *BottleRepository) SetBottleStatus(ctx context.Context, bottleId int, status models.BottleStatus) (*models.Bottle, error) {
+	const query = `UPDATE bottle SET status = $2
+		WHERE id = $1
+		RETURNING id, content, author, tag_id, user_id, location_from, created_at, status, mood, mood_score, language, tag_source
+	`
+
+	rows, err := r.db.Query(ctx, query, bottleId, status)
+	if err != nil {
+		return nil, fmt.Errorf("error updating bottle status: %w", err)
+	}
+	defer rows.Close()
+
+	bottle, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+		}
+		return nil, fmt.Errorf("error collecting bottle: %w", err)
+	}
+
+	return &bottle, nil
+}
+
+// RecomputeStats rebuilds bottle_stats from seen_bottles and returns how many bottles changed.
+//
+// Throwing a bottle back removes its seen_bottles row but not the catch, so recorded counts
+// and dates are only ever raised or widened here, never lowered.
+func (r *BottleRepository) RecomputeStats(ctx context.Context) (int64, error) {
+	const query = `
+		INSERT INTO bottle_stats (bottle_id, catch_count, first_caught_at, last_caught_at)
+		SELECT bottle_id, count(*), min(seen_at), max(seen_at)
+		FROM seen_bottles
+		GROUP BY bottle_id
+		ON CONFLICT (bottle_id) DO UPDATE
+			SET catch_count = GREATEST(bottle_stats.catch_count, EXCLUDED.catch_count),
+				first_caught_at = LEAST(bottle_stats.first_caught_at, EXCLUDED.first_caught_at),
+				last_caught_at = GREATEST(bottle_stats.last_caught_at, EXCLUDED.last_caught_at)
+			WHERE bottle_stats.catch_count < EXCLUDED.catch_count
+				OR bottle_stats.first_caught_at IS DISTINCT FROM LEAST(bottle_stats.first_caught_at, EXCLUDED.first_caught_at)
+				OR bottle_stats.last_caught_at IS DISTINCT FROM GREATEST(bottle_stats.last_caught_at, EXCLUDED.last_caught_at)
+	`
+
+	tag, err := r.db.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("error recomputing bottle stats: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *BottleRepository) GetUnclassifiedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
+		FROM bottle b
+		WHERE b.mood IS NULL
+		ORDER BY b.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying unclassified bottles: %w", err)
+	}
+	defer rows.Close()
+
+	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting unclassified bottles: %w", err)
+	}
+
+	return bottles, nil
+}
+
+func (r *BottleRepository) SetBottleMood(ctx context.Context, bottleId int, mood models.Mood, score float64) error {
+	const query = `UPDATE bottle SET mood = $2, mood_score = $3 WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, bottleId, mood, score)
+	if err != nil {
+		return fmt.Errorf("error setting bottle mood: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("bottle", "id", fmt.Sprint(bottleId))
+	}
+
+	return nil
+}
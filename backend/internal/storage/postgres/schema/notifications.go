@@ -0,0 +1,178 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type NotificationRepository struct {
+	db DBTX
+}
+
+const notificationColumns = `id, user_id, type, bottle_id, detail, count, batch_date, read_at, created_at, updated_at`
+
+// unlessDisabled guards notification inserts on the recipient ($1) not having opted out of the type ($2).
+const unlessDisabled = `
+	WHERE NOT EXISTS (
+		SELECT 1 FROM notification_preference
+		WHERE user_id = $1 AND type = $2 AND NOT enabled
+	)
+`
+
+// RecordCatch folds a catch into the author's unread "found N times today" notification for the bottle.
+func (r *NotificationRepository) RecordCatch(ctx context.Context, userId uuid.UUID, bottleId int) error {
+	query := `
+		INSERT INTO notification (user_id, type, bottle_id)
+		SELECT $1::uuid, $2::varchar, $3::int
+		` + unlessDisabled + `
+		ON CONFLICT (user_id, bottle_id, batch_date) WHERE type = 'bottle_caught' AND read_at IS NULL
+		DO UPDATE SET count = notification.count + 1, updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Exec(ctx, query, userId, models.NotificationBottleCaught, bottleId)
+	if err != nil {
+		return fmt.Errorf("error recording catch notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) CreateNotification(ctx context.Context, userId uuid.UUID, notificationType models.NotificationType, bottleId *int, detail *string) error {
+	query := `
+		INSERT INTO notification (user_id, type, bottle_id, detail)
+		SELECT $1::uuid, $2::varchar, $3::int, $4::varchar
+		` + unlessDisabled
+
+	_, err := r.db.Exec(ctx, query, userId, notificationType, bottleId, detail)
+	if err != nil {
+		return fmt.Errorf("error creating notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, page models.PaginationRequest) ([]models.Notification, error) {
+	query := `SELECT ` + notificationColumns + `
+		FROM notification
+		WHERE user_id = $1
+	`
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += ` ORDER BY updated_at DESC, id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(ctx, query, userId, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Notification])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting notifications: %w", err)
+	}
+
+	for i := range notifications {
+		notifications[i].Describe()
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userId uuid.UUID) (int, error) {
+	const query = `SELECT count(*) FROM notification WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userId).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userId uuid.UUID, notificationId int) error {
+	const query = `
+		UPDATE notification SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, notificationId, userId)
+	if err != nil {
+		return fmt.Errorf("error marking notification read: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("notification", "id", fmt.Sprint(notificationId))
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userId uuid.UUID) (int, error) {
+	const query = `
+		UPDATE notification SET read_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, userId)
+	if err != nil {
+		return 0, fmt.Errorf("error marking notifications read: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// GetPreferences returns the user's setting for every notification type, defaulting to enabled.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userId uuid.UUID) ([]models.NotificationPreference, error) {
+	const query = `SELECT type, enabled FROM notification_preference WHERE user_id = $1`
+
+	rows, err := r.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	stored, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.NotificationPreference])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting notification preferences: %w", err)
+	}
+
+	enabled := make(map[models.NotificationType]bool, len(stored))
+	for _, pref := range stored {
+		enabled[pref.Type] = pref.Enabled
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		isEnabled, ok := enabled[notificationType]
+		preferences = append(preferences, models.NotificationPreference{
+			Type:    notificationType,
+			Enabled: !ok || isEnabled,
+		})
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences models.UpdateNotificationPreferencesRequest) error {
+	const query = `
+		INSERT INTO notification_preference (user_id, type, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	batch := &pgx.Batch{}
+	for notificationType, enabled := range preferences {
+		batch.Queue(query, userId, notificationType, enabled)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("error updating notification preferences: %w", err)
+	}
+	return nil
+}
+
+func NewNotificationRepository(db DBTX) *NotificationRepository {
+	return &NotificationRepository{
+		db,
+	}
+}
@@ -10,16 +10,15 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OceanRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
 func (r *OceanRepository) GetOceans(ctx context.Context, filterParams models.GetOceansRequest) ([]models.Ocean, error) {
 	query := `
-        SELECT DISTINCT o.id, o.name, o.description, o.user_id
+        SELECT DISTINCT o.id, o.name, o.description, o.user_id, o.mask_profanity
         FROM ocean o
     `
 
@@ -76,7 +75,7 @@ func (r *OceanRepository) GetOceans(ctx context.Context, filterParams models.Get
 
 func (r *OceanRepository) GetDefaultOcean(ctx context.Context) (*models.Ocean, error) {
 	const query = `
-        SELECT id, name, description, user_id
+        SELECT id, name, description, user_id, mask_profanity
         FROM ocean
         WHERE user_id IS NULL and name = 'Default'
         ORDER BY id ASC
@@ -109,7 +108,7 @@ func (r *OceanRepository) GetRandomPersonalOcean(ctx context.Context, currentUse
 	if currentUserId != nil {
 		// Exclude oceans from the specified user
 		query = `
-			SELECT id, name, description, user_id
+			SELECT id, name, description, user_id, mask_profanity
 			FROM ocean
 			WHERE user_id IS NOT NULL
 			  AND user_id != $1::uuid
@@ -120,7 +119,7 @@ func (r *OceanRepository) GetRandomPersonalOcean(ctx context.Context, currentUse
 	} else {
 		// Get any random personal ocean
 		query = `
-			SELECT id, name, description, user_id
+			SELECT id, name, description, user_id, mask_profanity
 			FROM ocean
 			WHERE user_id IS NOT NULL
 			ORDER BY RANDOM()
@@ -148,7 +147,7 @@ func (r *OceanRepository) GetRandomPersonalOcean(ctx context.Context, currentUse
 
 func (r *OceanRepository) GetOceanByUser(ctx context.Context, userId uuid.UUID) (*models.Ocean, error) {
 	const query = `
-        SELECT id, name, description, user_id
+        SELECT id, name, description, user_id, mask_profanity
         FROM ocean
         WHERE user_id = $1::uuid
         LIMIT 1
@@ -171,11 +170,19 @@ func (r *OceanRepository) GetOceanByUser(ctx context.Context, userId uuid.UUID)
 	return &ocean, nil
 }
 
+// CreateOcean makes a personal ocean and maps the Personal tag to it in a single statement,
+// so an ocean never exists without its mapping.
 func (r *OceanRepository) CreateOcean(ctx context.Context, name *string, description *string, userId uuid.UUID) (*models.Ocean, error) {
 	const query = `
-				INSERT INTO ocean (name, description, user_id)
-				VALUES ($1, $2, $3::uuid)
-				RETURNING id, name, description, user_id
+				WITH created AS (
+					INSERT INTO ocean (name, description, user_id)
+					VALUES ($1, $2, $3::uuid)
+					RETURNING id, name, description, user_id, mask_profanity
+				), mapped AS (
+					INSERT INTO tag_ocean (tag_id, ocean_id)
+					SELECT (SELECT id FROM tag WHERE name = 'Personal' LIMIT 1), id FROM created
+				)
+				SELECT id, name, description, user_id, mask_profanity FROM created
 			`
 
 	var ocean models.Ocean
@@ -184,28 +191,19 @@ func (r *OceanRepository) CreateOcean(ctx context.Context, name *string, descrip
 		&ocean.Name,
 		&ocean.Description,
 		&ocean.UserID,
+		&ocean.MaskProfanity,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating ocean: %w", err)
 	}
 
-	tagOceanQuery := `
-		INSERT INTO tag_ocean (tag_id, ocean_id)
-		VALUES
-			((SELECT id FROM tag WHERE name = 'Personal' LIMIT 1), $1)
-	`
-	_, err = r.db.Exec(ctx, tagOceanQuery, ocean.ID)
-	if err != nil {
-		return nil, fmt.Errorf("error associating tags with new ocean: %w", err)
-	}
-
 	return &ocean, nil
 }
 
 func (r *OceanRepository) GetOceanById(ctx context.Context, oceanId int) (*models.Ocean, error) {
 	query := `
-		SELECT id, name, description, user_id
+		SELECT id, name, description, user_id, mask_profanity
 		FROM ocean
 		WHERE id=$1
 		LIMIT 1
@@ -219,12 +217,55 @@ func (r *OceanRepository) GetOceanById(ctx context.Context, oceanId int) (*model
 	company, err := pgx.CollectOneRow(companyRows, pgx.RowToStructByName[models.Ocean])
 
 	if err != nil {
-		return nil, errs.BadRequest(fmt.Sprintf("Error finding ocean with ocean_id: %s, %s", oceanId, err))
+		return nil, errs.BadRequest(fmt.Sprintf("Error finding ocean with ocean_id: %d, %s", oceanId, err))
 	}
 	return &company, nil
 }
 
-func NewOceanRepository(db *pgxpool.Pool) *OceanRepository {
+func (r *OceanRepository) UpdateOceanSettings(ctx context.Context, oceanId int, req models.UpdateOceanSettingsRequest) (*models.Ocean, error) {
+	const query = `
+		UPDATE ocean
+		SET mask_profanity = COALESCE($2, mask_profanity)
+		WHERE id = $1
+		RETURNING id, name, description, user_id, mask_profanity
+	`
+
+	rows, err := r.db.Query(ctx, query, oceanId, req.MaskProfanity)
+	if err != nil {
+		return nil, fmt.Errorf("error updating ocean: %w", err)
+	}
+	defer rows.Close()
+
+	ocean, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Ocean])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("ocean", "id", fmt.Sprint(oceanId))
+		}
+		return nil, fmt.Errorf("error collecting ocean row: %w", err)
+	}
+
+	return &ocean, nil
+}
+
+// MasksProfanity looks through the oceans a bottle drifts in, as the ocean_events triggers do:
+// those its tag is in, other than other users' personal oceans.
+func (r *OceanRepository) MasksProfanity(ctx context.Context, tagId int, authorId *uuid.UUID) (bool, error) {
+	const query = `
+		SELECT COALESCE(bool_and(o.mask_profanity), false)
+		FROM tag_ocean t
+		JOIN ocean o ON o.id = t.ocean_id
+		WHERE t.tag_id = $1
+		AND (o.user_id IS NULL OR o.user_id = $2)
+	`
+
+	var masks bool
+	if err := r.db.QueryRow(ctx, query, tagId, authorId).Scan(&masks); err != nil {
+		return false, fmt.Errorf("error querying ocean settings: %w", err)
+	}
+	return masks, nil
+}
+
+func NewOceanRepository(db DBTX) *OceanRepository {
 	return &OceanRepository{
 		db: db,
 	}
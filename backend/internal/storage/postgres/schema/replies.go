@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateReply sends a reply to a bottle's author. Only readers who caught the bottle may reply.
+func (r *BottleRepository) CreateReply(ctx context.Context, bottleId int, userId uuid.UUID, content string) (*models.Reply, error) {
+	const query = `
+		INSERT INTO bottle_reply (bottle_id, user_id, content)
+		SELECT $1::int, $2::uuid, $3::varchar
+		WHERE EXISTS (
+			SELECT 1 FROM seen_bottles WHERE bottle_id = $1 AND user_id = $2
+		)
+		RETURNING id, bottle_id, user_id, content, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, bottleId, userId, content)
+	if err != nil {
+		return nil, fmt.Errorf("error creating reply: %w", err)
+	}
+	defer rows.Close()
+
+	reply, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Reply])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.Forbidden("You can only reply to bottles you have caught")
+		}
+		return nil, fmt.Errorf("error collecting reply: %w", err)
+	}
+
+	return &reply, nil
+}
+
+// GetReplies returns the replies on a bottle visible to the viewer: all of them for the
+// bottle's author, except those from users they have blocked or been blocked by, and only
+// their own for anyone else.
+func (r *BottleRepository) GetReplies(ctx context.Context, bottleId int, viewerId uuid.UUID) ([]models.Reply, error) {
+	const query = `
+		SELECT r.id, r.bottle_id, r.user_id, r.content, r.created_at
+		FROM bottle_reply r
+		JOIN bottle b ON b.id = r.bottle_id
+		WHERE r.bottle_id = $1
+		AND (
+			r.user_id = $2
+			OR b.user_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM user_block ub
+				WHERE (ub.blocker_id = r.user_id AND ub.blocked_id = $2)
+				OR (ub.blocker_id = $2 AND ub.blocked_id = r.user_id)
+			)
+		)
+		ORDER BY r.created_at ASC, r.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, bottleId, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying replies: %w", err)
+	}
+	defer rows.Close()
+
+	replies, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Reply])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting replies: %w", err)
+	}
+
+	return replies, nil
+}
+
+// GetRepliesByUser returns the replies the user has sent, oldest first.
+func (r *BottleRepository) GetRepliesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Reply, error) {
+	const query = `
+		SELECT id, bottle_id, user_id, content, created_at
+		FROM bottle_reply
+		WHERE user_id = $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying replies: %w", err)
+	}
+	defer rows.Close()
+
+	replies, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Reply])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting replies: %w", err)
+	}
+
+	return replies, nil
+}
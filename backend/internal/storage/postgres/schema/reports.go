@@ -0,0 +1,192 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportRepository struct {
+	db DBTX
+}
+
+const (
+	reportColumns = `id, bottle_id, reporter_id, reason, comment, status, created_at, resolved_at, resolved_by`
+	strikeColumns = `id, user_id, bottle_id, issued_by, created_at`
+)
+
+// CreateReport files a report, as long as the reporter caught the bottle and hasn't reported it before.
+func (r *ReportRepository) CreateReport(ctx context.Context, bottleId int, reporterId uuid.UUID, req models.CreateReportRequest) (*models.Report, error) {
+	query := `
+		INSERT INTO bottle_report (bottle_id, reporter_id, reason, comment)
+		SELECT $1::int, $2::uuid, $3, $4
+		WHERE EXISTS (
+			SELECT 1 FROM seen_bottles WHERE bottle_id = $1 AND user_id = $2
+		)
+		ON CONFLICT (bottle_id, reporter_id) DO NOTHING
+		RETURNING ` + reportColumns
+
+	rows, err := r.db.Query(ctx, query, bottleId, reporterId, req.Reason, req.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("error creating report: %w", err)
+	}
+	defer rows.Close()
+
+	report, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Report])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Either the reporter never caught the bottle or they already reported it
+			var reported bool
+			err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bottle_report WHERE bottle_id = $1 AND reporter_id = $2)`, bottleId, reporterId).Scan(&reported)
+			if err != nil {
+				return nil, fmt.Errorf("error creating report: %w", err)
+			}
+			if reported {
+				return nil, errs.Conflict("You have already reported this bottle")
+			}
+			return nil, errs.Forbidden("You can only report bottles you have caught")
+		}
+		return nil, fmt.Errorf("error collecting report: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *ReportRepository) CountPendingReports(ctx context.Context, bottleId int) (int, error) {
+	const query = `SELECT count(DISTINCT reporter_id) FROM bottle_report WHERE bottle_id = $1 AND status = 'pending'`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, bottleId).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting reports: %w", err)
+	}
+	return count, nil
+}
+
+func (r *ReportRepository) GetReport(ctx context.Context, reportId int) (*models.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM bottle_report WHERE id = $1`
+
+	rows, err := r.db.Query(ctx, query, reportId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying report: %w", err)
+	}
+	defer rows.Close()
+
+	report, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Report])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("report", "id", fmt.Sprint(reportId))
+		}
+		return nil, fmt.Errorf("error collecting report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// ListReports returns reports oldest first, so that the longest waiting are reviewed first.
+func (r *ReportRepository) ListReports(ctx context.Context, filterParams models.ListReportsRequest, page models.PaginationRequest) ([]models.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM bottle_report WHERE 1=1`
+	var args []any
+
+	if filterParams.Status != nil {
+		args = append(args, *filterParams.Status)
+		query += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	if filterParams.BottleID != nil {
+		args = append(args, *filterParams.BottleID)
+		query += fmt.Sprintf(` AND bottle_id = $%d`, len(args))
+	}
+
+	if filterParams.ReporterID != nil {
+		args = append(args, *filterParams.ReporterID)
+		query += fmt.Sprintf(` AND reporter_id = $%d`, len(args))
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Report])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r *ReportRepository) ResolveReports(ctx context.Context, bottleId int, resolution models.ReportStatus, resolvedBy *uuid.UUID) ([]models.Report, error) {
+	query := `
+		UPDATE bottle_report
+		SET status = $2, resolved_at = CURRENT_TIMESTAMP, resolved_by = $3
+		WHERE bottle_id = $1 AND status = 'pending'
+		RETURNING ` + reportColumns
+
+	rows, err := r.db.Query(ctx, query, bottleId, resolution, resolvedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Report])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r *ReportRepository) AddStrike(ctx context.Context, userId uuid.UUID, bottleId *int, issuedBy *uuid.UUID) (*models.Strike, error) {
+	query := `
+		INSERT INTO user_strike (user_id, bottle_id, issued_by)
+		SELECT id, $2, $3 FROM "user" WHERE id = $1
+		RETURNING ` + strikeColumns
+
+	rows, err := r.db.Query(ctx, query, userId, bottleId, issuedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error adding strike: %w", err)
+	}
+	defer rows.Close()
+
+	strike, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Strike])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("user", "id", userId.String())
+		}
+		return nil, fmt.Errorf("error collecting strike: %w", err)
+	}
+
+	return &strike, nil
+}
+
+// GetStrikes returns the user's strikes, most recent first.
+func (r *ReportRepository) GetStrikes(ctx context.Context, userId uuid.UUID) ([]models.Strike, error) {
+	query := `SELECT ` + strikeColumns + ` FROM user_strike WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying strikes: %w", err)
+	}
+	defer rows.Close()
+
+	strikes, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Strike])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting strikes: %w", err)
+	}
+
+	return strikes, nil
+}
+
+func NewReportRepository(db DBTX) *ReportRepository {
+	return &ReportRepository{
+		db,
+	}
+}
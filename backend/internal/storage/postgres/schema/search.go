@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/search"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SearchBottles finds afloat bottles matching a search query, best matches first.
+//
+// The query supports "quoted phrases", prefix* matches, -excluded words and OR between terms;
+// all other terms must match. Personal bottles are only ever found by their own author, and
+// personal oceans can only be searched by their owner. Authors the viewer has blocked are
+// left out.
+func (r *BottleRepository) SearchBottles(ctx context.Context, filterParams models.SearchBottlesRequest, viewerId *uuid.UUID, page models.PaginationRequest) ([]models.SearchResult, error) {
+	searchQuery := search.Parse(filterParams.Query)
+	if searchQuery.Empty() {
+		return nil, errs.BadRequest("Search query must contain at least one word")
+	}
+
+	query := `
+		WITH q AS (SELECT to_tsquery('english', $1) AS query)
+		SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source,
+			ts_rank_cd(b.search_vector, q.query) AS rank,
+			ts_headline('english', b.content, q.query, 'StartSel=` + search.HighlightStart + `, StopSel=` + search.HighlightStop + `, HighlightAll=true') AS highlight
+		FROM bottle b, q
+		WHERE b.search_vector @@ q.query
+		AND b.status = 'afloat'
+		AND (
+			b.tag_id NOT IN (SELECT id FROM tag WHERE name = 'Personal')
+			OR b.user_id = $2
+		)
+		AND NOT ` + blockedBy(2) + `
+	`
+	args := []any{searchQuery.TSQuery(), viewerId}
+	conditions := []string{}
+
+	if filterParams.OceanID != nil {
+		args = append(args, *filterParams.OceanID)
+		conditions = append(conditions, fmt.Sprintf(`b.tag_id IN (
+			SELECT tag_id FROM tag_ocean WHERE ocean_id = $%d
+		)`, len(args)))
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM ocean o WHERE o.id = $%d AND (o.user_id IS NULL OR o.user_id = $2)
+		)`, len(args)))
+	}
+
+	if filterParams.TagID != nil {
+		args = append(args, *filterParams.TagID)
+		conditions = append(conditions, fmt.Sprintf("b.tag_id = $%d", len(args)))
+	}
+
+	if filterParams.From != nil {
+		args = append(args, *filterParams.From)
+		conditions = append(conditions, fmt.Sprintf("b.created_at >= $%d", len(args)))
+	}
+
+	if filterParams.To != nil {
+		args = append(args, *filterParams.To)
+		conditions = append(conditions, fmt.Sprintf("b.created_at < $%d", len(args)))
+	}
+
+	for _, condition := range conditions {
+		query += " AND " + condition
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	query += fmt.Sprintf(` ORDER BY rank DESC, b.created_at DESC, b.id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching bottles: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.SearchResult])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting search results: %w", err)
+	}
+
+	for i := range results {
+		results[i].Highlight = escapeHighlight(results[i].Highlight)
+	}
+
+	return results, nil
+}
+
+// escapeHighlight HTML-escapes a headline while keeping the highlight markers, so that
+// clients can render it without trusting the bottle's content.
+func escapeHighlight(headline string) string {
+	var escaped strings.Builder
+	for _, part := range strings.SplitAfter(headline, search.HighlightStop) {
+		marked := strings.TrimSuffix(part, search.HighlightStop)
+		before, inside, found := strings.Cut(marked, search.HighlightStart)
+		escaped.WriteString(html.EscapeString(before))
+		if found {
+			escaped.WriteString(search.HighlightStart + html.EscapeString(inside))
+		}
+		if len(marked) != len(part) {
+			escaped.WriteString(search.HighlightStop)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,182 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionRepository struct {
+	db DBTX
+}
+
+const userSessionColumns = `id, user_id, remember_me, user_agent, ip_address, created_at, refreshed_at, last_seen_at, revoked_at`
+
+// CreateSession starts tracking a sign-in along with the first refresh token handed to it.
+func (r *SessionRepository) CreateSession(ctx context.Context, req models.CreateSessionRequest) (*models.Session, error) {
+	query := `
+		WITH created AS (
+			INSERT INTO user_session (id, user_id, remember_me, user_agent, ip_address)
+			VALUES ($1, $2, $3, $5, $6)
+			RETURNING ` + userSessionColumns + `
+		), issued AS (
+			INSERT INTO session_refresh_token (token_hash, session_id)
+			SELECT $4::text, id FROM created
+		)
+		SELECT ` + userSessionColumns + ` FROM created
+	`
+
+	rows, err := r.db.Query(ctx, query, req.ID, req.UserID, req.RememberMe, req.RefreshTokenHash, req.UserAgent, req.IPAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Session])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, sessionId uuid.UUID) (*models.Session, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE id = $1`
+
+	rows, err := r.db.Query(ctx, query, sessionId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying session: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Session])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("session", "id", sessionId.String())
+		}
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ListSessions returns the user's sessions that haven't been revoked, most recently used first.
+func (r *SessionRepository) ListSessions(ctx context.Context, userId uuid.UUID) ([]models.Session, error) {
+	query := `SELECT ` + userSessionColumns + `
+		FROM user_session
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC, created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Session])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// TouchSession records that the session was just used.
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionId uuid.UUID) error {
+	const query = `UPDATE user_session SET last_seen_at = CURRENT_TIMESTAMP WHERE id = $1`
+	if _, err := r.db.Exec(ctx, query, sessionId); err != nil {
+		return fmt.Errorf("error touching session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	const query = `SELECT token_hash, session_id, created_at, used_at FROM session_refresh_token WHERE token_hash = $1`
+
+	rows, err := r.db.Query(ctx, query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error querying refresh token: %w", err)
+	}
+	defer rows.Close()
+
+	token, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.RefreshToken])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("refresh token not found")
+		}
+		return nil, fmt.Errorf("error collecting refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken marks an unused refresh token of a live session used and hands the session
+// its successor. It is a conflict if the token was used or the session revoked in the meantime.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, tokenHash string, newTokenHash string) (*models.Session, error) {
+	query := `
+		WITH used AS (
+			UPDATE session_refresh_token AS t
+			SET used_at = CURRENT_TIMESTAMP
+			FROM user_session AS s
+			WHERE t.token_hash = $1 AND t.used_at IS NULL
+				AND s.id = t.session_id AND s.revoked_at IS NULL
+			RETURNING t.session_id
+		), issued AS (
+			INSERT INTO session_refresh_token (token_hash, session_id)
+			SELECT $2::text, session_id FROM used
+		)
+		UPDATE user_session
+		SET refreshed_at = CURRENT_TIMESTAMP, last_seen_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT session_id FROM used)
+		RETURNING ` + userSessionColumns
+
+	rows, err := r.db.Query(ctx, query, tokenHash, newTokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error rotating refresh token: %w", err)
+	}
+	defer rows.Close()
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Session])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.Conflict("refresh token is no longer current")
+		}
+		return nil, fmt.Errorf("error collecting session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RevokeSession ends a session. Revoking it again keeps the original revocation time.
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	const query = `UPDATE user_session SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP) WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, sessionId)
+	if err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound("session", "id", sessionId.String())
+	}
+	return nil
+}
+
+// RevokeUserSessions ends every session of the user, returning how many were still live.
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userId uuid.UUID) (int64, error) {
+	const query = `UPDATE user_session SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, userId)
+	if err != nil {
+		return 0, fmt.Errorf("error revoking sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func NewSessionRepository(db DBTX) *SessionRepository {
+	return &SessionRepository{
+		db,
+	}
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetTaggedBottles returns up to limit of the newest afloat bottles their writers tagged with
+// something other than the Default or Personal tags. Tags the classifier applied are left out.
+func (r *BottleRepository) GetTaggedBottles(ctx context.Context, limit int) ([]models.Bottle, error) {
+	const query = `SELECT b.id, b.content, b.author, b.tag_id, b.user_id, b.location_from, b.created_at, b.status, b.mood, b.mood_score, b.language, b.tag_source
+		FROM bottle b
+		JOIN tag t ON t.id = b.tag_id
+		WHERE b.status = 'afloat'
+			AND b.tag_source = 'writer'
+			AND t.name NOT IN ('Default', 'Personal')
+		ORDER BY b.id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tagged bottles: %w", err)
+	}
+	defer rows.Close()
+
+	bottles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Bottle])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting tagged bottles: %w", err)
+	}
+
+	return bottles, nil
+}
+
+func (r *TagRepository) SaveTagModel(ctx context.Context, model models.TagModel) (*models.TagModel, error) {
+	const query = `
+		INSERT INTO tag_model (model, report, examples)
+		VALUES ($1, $2, $3)
+		RETURNING id, model, report, examples, trained_at
+	`
+
+	rows, err := r.db.Query(ctx, query, model.Model, model.Report, model.Examples)
+	if err != nil {
+		return nil, fmt.Errorf("error saving tag model: %w", err)
+	}
+	defer rows.Close()
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.TagModel])
+	if err != nil {
+		return nil, fmt.Errorf("error saving tag model: %w", err)
+	}
+
+	return &saved, nil
+}
+
+func (r *TagRepository) GetLatestTagModel(ctx context.Context) (*models.TagModel, error) {
+	const query = `
+		SELECT id, model, report, examples, trained_at
+		FROM tag_model
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tag model: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.TagModel])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("no tag model has been trained")
+		}
+		return nil, fmt.Errorf("error collecting tag model: %w", err)
+	}
+
+	return &model, nil
+}
@@ -6,11 +6,10 @@ import (
 	"hackmit/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type TagRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
 func (r *TagRepository) GetTags(ctx context.Context, filterParams models.GetTagsRequest) ([]models.Tag, error) {
@@ -94,7 +93,30 @@ func (r *TagRepository) GetPersonalTag(ctx context.Context) (*models.Tag, error)
 
 }
 
-func NewTagRepository(db *pgxpool.Pool) *TagRepository {
+func (r *TagRepository) GetTagsByOcean(ctx context.Context, oceanId int) ([]models.Tag, error) {
+	query := `
+	SELECT tag.id, name, color
+	FROM tag
+	JOIN tag_ocean ON tag_ocean.tag_id = tag.id
+	WHERE tag_ocean.ocean_id = $1
+	ORDER BY tag.id
+	`
+
+	rows, err := r.db.Query(ctx, query, oceanId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Tag])
+
+	if err != nil {
+		return nil, fmt.Errorf("error querying database for ocean tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func NewTagRepository(db DBTX) *TagRepository {
 	return &TagRepository{
 		db,
 	}
@@ -3,25 +3,29 @@ package schema
 import (
 	"context"
 	"fmt"
+	"hackmit/internal/errs"
 	"hackmit/internal/models"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
+const roleChangeColumns = `id, user_id, old_role, new_role, changed_by, created_at`
+
 func (r *UserRepository) AddUser(ctx context.Context, userID string, firstName *string, lastName *string, email string) (*models.User, error) {
-	const query = `Insert into public.user (id, first_name, last_name, email) Values ($1, $2, $3, $4)RETURNING id, first_name, last_name, email;
+	const query = `Insert into public.user (id, first_name, last_name, email) Values ($1, $2, $3, $4)RETURNING id, first_name, last_name, email, role;
 	`
 	var user models.User
 	err := r.db.QueryRow(ctx, query, userID, firstName, lastName, email).Scan(
 		&user.ID,
 		&user.FirstName,
 		&user.LastName,
-		&user.Email)
+		&user.Email,
+		&user.Role)
 
 	if err != nil {
 		return nil, fmt.Errorf("error querying database: %w", err)
@@ -33,9 +37,12 @@ func (r *UserRepository) AddUser(ctx context.Context, userID string, firstName *
 func (c *UserRepository) GetUserProfile(ctx context.Context, userId string) (*models.User, error) {
 
 	const query = `
-		SELECT user.id, first_name, last_name,  email
-		FROM user join auth.users as u on user.id = u.id
-		WHERE user.id = $1
+		SELECT p.id, p.first_name, p.last_name, p.email, p.role, p.display_name, p.bio, p.avatar_key, p.languages
+		FROM "user" AS p
+		WHERE p.id = $1 AND (
+			EXISTS (SELECT 1 FROM auth.users AS u WHERE u.id = p.id)
+			OR EXISTS (SELECT 1 FROM local_identity AS l WHERE l.user_id = p.id)
+		)
 		LIMIT 1
 	`
 
@@ -54,8 +61,60 @@ func (c *UserRepository) GetUserProfile(ctx context.Context, userId string) (*mo
 
 }
 
+// UpdateUserProfile leaves fields the request doesn't set alone, and clears those set to "".
+func (c *UserRepository) UpdateUserProfile(ctx context.Context, userId uuid.UUID, req models.UpdateProfileRequest) (*models.User, error) {
+	const query = `
+		UPDATE "user" SET
+			first_name = CASE WHEN $2::text IS NULL THEN first_name ELSE NULLIF($2, '') END,
+			last_name = CASE WHEN $3::text IS NULL THEN last_name ELSE NULLIF($3, '') END,
+			display_name = CASE WHEN $4::text IS NULL THEN display_name ELSE NULLIF($4, '') END,
+			bio = CASE WHEN $5::text IS NULL THEN bio ELSE NULLIF($5, '') END,
+			languages = COALESCE($6::text[], languages)
+		WHERE id = $1
+		RETURNING id, first_name, last_name, email, role, display_name, bio, avatar_key, languages
+	`
+
+	rows, err := c.db.Query(ctx, query, userId, req.FirstName, req.LastName, req.DisplayName, req.Bio, req.Languages)
+	if err != nil {
+		return nil, fmt.Errorf("error updating profile: %w", err)
+	}
+	defer rows.Close()
+
+	user, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.User])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("user", "id", userId.String())
+		}
+		if isUniqueViolation(err) {
+			return nil, errs.Conflict("user", "display_name", *req.DisplayName)
+		}
+		return nil, fmt.Errorf("error updating profile: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (c *UserRepository) SetUserAvatar(ctx context.Context, userId uuid.UUID, avatarKey *string) (*string, error) {
+	const query = `
+		UPDATE "user" AS u SET avatar_key = $2
+		FROM (SELECT id, avatar_key FROM "user" WHERE id = $1 FOR UPDATE) AS old
+		WHERE u.id = old.id
+		RETURNING old.avatar_key
+	`
+
+	var previous *string
+	if err := c.db.QueryRow(ctx, query, userId, avatarKey).Scan(&previous); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("user", "id", userId.String())
+		}
+		return nil, fmt.Errorf("error setting avatar: %w", err)
+	}
+
+	return previous, nil
+}
+
 func (c *UserRepository) DeleteUser(ctx context.Context, userId string) (string, error) {
-	const query = `DELETE FROM user WHERE id = $1`
+	const query = `DELETE FROM "user" WHERE id = $1`
 	_, err := c.db.Exec(ctx, query, userId)
 	if err != nil {
 		return "", fmt.Errorf("error querying database for user: %w", err)
@@ -63,7 +122,100 @@ func (c *UserRepository) DeleteUser(ctx context.Context, userId string) (string,
 	return "User Deleted Successfully", nil
 }
 
-func NewUserRepository(db *pgxpool.Pool) *UserRepository {
+// AnonymizeUser detaches a user's bottles from them and clears their names, returning how many
+// bottles were detached. The bottles stay afloat with neither author nor user_id.
+func (c *UserRepository) AnonymizeUser(ctx context.Context, userId uuid.UUID) (int64, error) {
+	var detached int64
+	err := pgx.BeginFunc(ctx, c.db, func(tx pgx.Tx) error {
+		var err error
+		if detached, err = detachBottles(ctx, tx, userId); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `UPDATE "user" SET first_name = NULL, last_name = NULL, display_name = NULL, bio = NULL WHERE id = $1`, userId)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errs.NotFound("user", "id", userId.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error anonymizing user: %w", err)
+	}
+
+	return detached, nil
+}
+
+func (c *UserRepository) GetUserRole(ctx context.Context, userId uuid.UUID) (models.Role, error) {
+	const query = `SELECT role FROM "user" WHERE id = $1`
+
+	var role models.Role
+	err := c.db.QueryRow(ctx, query, userId).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", errs.NotFound("user", "id", userId.String())
+		}
+		return "", fmt.Errorf("error querying database for role: %w", err)
+	}
+
+	return role, nil
+}
+
+// SetUserRole updates the role and writes the audit record in one statement, so neither
+// happens without the other.
+func (c *UserRepository) SetUserRole(ctx context.Context, userId uuid.UUID, role models.Role, changedBy *uuid.UUID) (*models.RoleChange, error) {
+	query := `
+		WITH old AS (
+			SELECT id, role FROM "user" WHERE id = $1 FOR UPDATE
+		), updated AS (
+			UPDATE "user" AS u SET role = $2 FROM old WHERE u.id = old.id
+		)
+		INSERT INTO role_change (user_id, old_role, new_role, changed_by)
+		SELECT old.id, old.role, $2, $3 FROM old
+		RETURNING ` + roleChangeColumns
+
+	rows, err := c.db.Query(ctx, query, userId, role, changedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error setting role: %w", err)
+	}
+	defer rows.Close()
+
+	change, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.RoleChange])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NotFound("user", "id", userId.String())
+		}
+		return nil, fmt.Errorf("error collecting role change: %w", err)
+	}
+
+	return &change, nil
+}
+
+// GetRoleChanges returns the user's role changes, newest first.
+func (c *UserRepository) GetRoleChanges(ctx context.Context, userId uuid.UUID) ([]models.RoleChange, error) {
+	query := `
+		SELECT ` + roleChangeColumns + `
+		FROM role_change
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := c.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying role changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.RoleChange])
+	if err != nil {
+		return nil, fmt.Errorf("error collecting role changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+func NewUserRepository(db DBTX) *UserRepository {
 	return &UserRepository{
 		db,
 	}
@@ -4,23 +4,68 @@ import (
 	"context"
 	"hackmit/internal/models"
 	"hackmit/internal/storage/postgres/schema"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository interface {
 	AddUser(ctx context.Context, userId string, firstName *string, lastName *string, email string) (*models.User, error)
 	GetUserProfile(ctx context.Context, userID string) (*models.User, error)
+	UpdateUserProfile(ctx context.Context, userID uuid.UUID, req models.UpdateProfileRequest) (*models.User, error)
+	// SetUserAvatar names the user's avatar files, or clears them with nil, returning the
+	// name of the avatar it replaced.
+	SetUserAvatar(ctx context.Context, userID uuid.UUID, avatarKey *string) (*string, error)
 	DeleteUser(ctx context.Context, userID string) (string, error)
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetUserRole(ctx context.Context, userID uuid.UUID) (models.Role, error)
+	// SetUserRole changes the user's role and records the change, made by changedBy if it
+	// was made by a user.
+	SetUserRole(ctx context.Context, userID uuid.UUID, role models.Role, changedBy *uuid.UUID) (*models.RoleChange, error)
+	GetRoleChanges(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, keepBottles bool, deleteAfter time.Time) (*models.AccountDeletion, error)
+	GetDeletion(ctx context.Context, userID uuid.UUID) (*models.AccountDeletion, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	// GetDueDeletions returns the deletions whose grace period is over at the given time.
+	GetDueDeletions(ctx context.Context, at time.Time) ([]models.AccountDeletion, error)
+	// PurgeUser deletes the user's profile, personal ocean and everything cascading from them,
+	// and either deletes their bottles or keeps them anonymized. It returns how many bottles
+	// were deleted or anonymized.
+	PurgeUser(ctx context.Context, userID uuid.UUID, keepBottles bool) (int64, error)
 }
 
 type BottleRepository interface {
 	CreateBottle(ctx context.Context, req models.CreateBottleRequest) (*models.Bottle, error)
+	GetBottleByID(ctx context.Context, bottleId int) (*models.Bottle, error)
+	// GetBottleOriginal returns what the author wrote, for bottles that were masked.
+	GetBottleOriginal(ctx context.Context, bottleId int) (*models.BottleOriginal, error)
 	DeleteBottle(ctx context.Context, bottleId int) (string, error)
 	GetBottles(ctx context.Context, filterParams models.GetBottlesRequest) ([]models.Bottle, error)
-	GetBottlesByUser(ctx context.Context, userId int) ([]models.Bottle, error)
+	GetBottlesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.AuthoredBottle, error)
 	GetRandomBottle(ctx context.Context, filterParams models.GetRandomBottleRequest, ocean models.Ocean) (*models.Bottle, error)
+	GetCatches(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error)
+	GetBookmarks(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Catch, error)
+	SaveBookmark(ctx context.Context, userId uuid.UUID, bottleId int, note *string) (*models.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userId uuid.UUID, bottleId int) error
+	ThrowBack(ctx context.Context, userId uuid.UUID, bottleId int) error
+	CreateReply(ctx context.Context, bottleId int, userId uuid.UUID, content string) (*models.Reply, error)
+	GetReplies(ctx context.Context, bottleId int, viewerId uuid.UUID) ([]models.Reply, error)
+	GetRepliesByUser(ctx context.Context, userId uuid.UUID, page models.PaginationRequest) ([]models.Reply, error)
+	SearchBottles(ctx context.Context, filterParams models.SearchBottlesRequest, viewerId *uuid.UUID, page models.PaginationRequest) ([]models.SearchResult, error)
+	ListBottles(ctx context.Context, filterParams models.ListBottlesRequest, page models.PaginationRequest) ([]models.Bottle, error)
+	SetBottleStatus(ctx context.Context, bottleId int, status models.BottleStatus) (*models.Bottle, error)
+	RecomputeStats(ctx context.Context) (int64, error)
+	// GetUnclassifiedBottles returns up to limit bottles whose mood hasn't been read, oldest first.
+	GetUnclassifiedBottles(ctx context.Context, limit int) ([]models.Bottle, error)
+	SetBottleMood(ctx context.Context, bottleId int, mood models.Mood, score float64) error
+	// GetUndetectedBottles returns up to limit bottles whose language hasn't been detected, oldest first.
+	GetUndetectedBottles(ctx context.Context, limit int) ([]models.Bottle, error)
+	SetBottleLanguage(ctx context.Context, bottleId int, language models.Language) error
+	// GetTaggedBottles returns up to limit of the newest afloat bottles their writers tagged with
+	// something other than the Default or Personal tags, for the tag classifier to learn from.
+	GetTaggedBottles(ctx context.Context, limit int) ([]models.Bottle, error)
 }
 
 type OceanRepository interface {
@@ -30,24 +75,148 @@ type OceanRepository interface {
 	GetOceanByUser(ctx context.Context, userId uuid.UUID) (*models.Ocean, error)
 	CreateOcean(ctx context.Context, name *string, description *string, userId uuid.UUID) (*models.Ocean, error)
 	GetOceanById(ctx context.Context, oceanId int) (*models.Ocean, error)
+	UpdateOceanSettings(ctx context.Context, oceanId int, req models.UpdateOceanSettingsRequest) (*models.Ocean, error)
+	// MasksProfanity reports whether every ocean a bottle with the tag would drift in, thrown
+	// by the given author if any, masks profanity. Masking is left to the oceans' owners, so
+	// one that doesn't ask for it still gets to reject what the others would let through masked.
+	MasksProfanity(ctx context.Context, tagId int, authorId *uuid.UUID) (bool, error)
 }
 
 type TagRepository interface {
 	GetTags(ctx context.Context, filterParams models.GetTagsRequest) ([]models.Tag, error)
 	GetDefaultTag(ctx context.Context) (*models.Tag, error)
 	GetPersonalTag(ctx context.Context) (*models.Tag, error)
+	GetTagsByOcean(ctx context.Context, oceanId int) ([]models.Tag, error)
+	SaveTagModel(ctx context.Context, model models.TagModel) (*models.TagModel, error)
+	// GetLatestTagModel returns the tag model trained last, or NotFound if none has been.
+	GetLatestTagModel(ctx context.Context) (*models.TagModel, error)
+}
+
+type NotificationRepository interface {
+	RecordCatch(ctx context.Context, userId uuid.UUID, bottleId int) error
+	CreateNotification(ctx context.Context, userId uuid.UUID, notificationType models.NotificationType, bottleId *int, detail *string) error
+	GetNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, page models.PaginationRequest) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userId uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userId uuid.UUID, notificationId int) error
+	MarkAllRead(ctx context.Context, userId uuid.UUID) (int, error)
+	GetPreferences(ctx context.Context, userId uuid.UUID) ([]models.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences models.UpdateNotificationPreferencesRequest) error
+}
+
+// IdentityRepository holds the accounts and sessions of the local identity provider.
+type IdentityRepository interface {
+	CreateIdentity(ctx context.Context, email string, passwordHash string) (*models.Identity, error)
+	GetIdentityByEmail(ctx context.Context, email string) (*models.Identity, error)
+	UpdatePasswordHash(ctx context.Context, userId uuid.UUID, passwordHash string) error
+	// SetRecoveryTokenHash keeps the hash of the one recovery token the account's password can be
+	// reset with, replacing any earlier one.
+	SetRecoveryTokenHash(ctx context.Context, userId uuid.UUID, tokenHash string) error
+	// ResetPassword sets the password of an account with the recovery token hashing to tokenHash,
+	// using the token up. Tokens that were used or replaced are not found.
+	ResetPassword(ctx context.Context, userId uuid.UUID, tokenHash string, passwordHash string) error
+	DeleteIdentity(ctx context.Context, userId uuid.UUID) error
+	CreateSession(ctx context.Context, userId uuid.UUID, refreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error)
+	RotateSession(ctx context.Context, refreshTokenHash string, newRefreshTokenHash string, expiresAt time.Time) (*models.IdentitySession, error)
+	GetSession(ctx context.Context, sessionId uuid.UUID) (*models.IdentitySession, error)
+	RevokeSession(ctx context.Context, sessionId uuid.UUID) error
+	// RevokeSessions ends every live session of the account but except, which may be uuid.Nil.
+	RevokeSessions(ctx context.Context, userId uuid.UUID, except uuid.UUID) (int64, error)
+}
+
+// SessionRepository tracks sign-ins and the refresh tokens handed to them.
+type SessionRepository interface {
+	CreateSession(ctx context.Context, req models.CreateSessionRequest) (*models.Session, error)
+	GetSession(ctx context.Context, sessionId uuid.UUID) (*models.Session, error)
+	ListSessions(ctx context.Context, userId uuid.UUID) ([]models.Session, error)
+	TouchSession(ctx context.Context, sessionId uuid.UUID) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenHash string, newTokenHash string) (*models.Session, error)
+	RevokeSession(ctx context.Context, sessionId uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, userId uuid.UUID) (int64, error)
+}
+
+// ExportRepository queues data exports and keeps the archives they produce.
+type ExportRepository interface {
+	// CreateExportJob queues an export, unless the user already has one in progress.
+	CreateExportJob(ctx context.Context, userId uuid.UUID) (*models.ExportJob, error)
+	GetExportJob(ctx context.Context, jobId uuid.UUID) (*models.ExportJob, error)
+	// ClaimExportJob marks the oldest pending job running and returns it. Jobs started before
+	// staleBefore by a worker that never finished them are claimed again.
+	ClaimExportJob(ctx context.Context, staleBefore time.Time) (*models.ExportJob, error)
+	CompleteExportJob(ctx context.Context, jobId uuid.UUID, archive []byte, expiresAt time.Time) error
+	FailExportJob(ctx context.Context, jobId uuid.UUID, message string) error
+	// GetExportArchive returns the archive of a ready export that hasn't expired.
+	GetExportArchive(ctx context.Context, jobId uuid.UUID) ([]byte, error)
+	// DeleteExpiredExports deletes the exports that expired by the given time.
+	DeleteExpiredExports(ctx context.Context, at time.Time) (int64, error)
+}
+
+// BlockRepository keeps track of who doesn't want to hear from whom.
+type BlockRepository interface {
+	// BlockUser blocks blockedId for blockerId, from the given bottle if they were blocked as
+	// its author.
+	BlockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID, bottleId *int) (*models.Block, error)
+	UnblockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error
+	// UnblockBottleAuthor lifts the block on a bottle's author, whether they were blocked from
+	// that bottle, including one that has since been deleted, or otherwise.
+	UnblockBottleAuthor(ctx context.Context, blockerId uuid.UUID, bottleId int) error
+	GetBlocks(ctx context.Context, blockerId uuid.UUID) ([]models.Block, error)
+	// IsBlocked reports whether either user has blocked the other.
+	IsBlocked(ctx context.Context, userId uuid.UUID, otherId uuid.UUID) (bool, error)
+}
+
+// ReportRepository holds readers' reports of bottles and the strikes authors get for them.
+type ReportRepository interface {
+	// CreateReport files a reader's report of a bottle they caught.
+	CreateReport(ctx context.Context, bottleId int, reporterId uuid.UUID, req models.CreateReportRequest) (*models.Report, error)
+	// CountPendingReports returns how many readers have reported the bottle since it was last reviewed.
+	CountPendingReports(ctx context.Context, bottleId int) (int, error)
+	GetReport(ctx context.Context, reportId int) (*models.Report, error)
+	ListReports(ctx context.Context, filterParams models.ListReportsRequest, page models.PaginationRequest) ([]models.Report, error)
+	// ResolveReports resolves every pending report on the bottle and returns them.
+	ResolveReports(ctx context.Context, bottleId int, resolution models.ReportStatus, resolvedBy *uuid.UUID) ([]models.Report, error)
+	AddStrike(ctx context.Context, userId uuid.UUID, bottleId *int, issuedBy *uuid.UUID) (*models.Strike, error)
+	GetStrikes(ctx context.Context, userId uuid.UUID) ([]models.Strike, error)
+}
+
+// AuditRepository keeps the append-only record of moderation decisions and admin actions.
+type AuditRepository interface {
+	// RecordAudit appends an entry; its ID and creation time are assigned.
+	RecordAudit(ctx context.Context, entry models.AuditEntry) (*models.AuditEntry, error)
+	// ListAudit returns the entries matching the filter, most recent first.
+	ListAudit(ctx context.Context, filterParams models.ListAuditRequest, page models.PaginationRequest) ([]models.AuditEntry, error)
+	// DeleteAuditBefore deletes the entries written before the given time.
+	DeleteAuditBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Transactor runs a unit of work: every repository call made through the Repository handed
+// to fn commits together when fn returns nil, and rolls back together when it returns an error.
+// Units of work may nest; an inner one that fails rolls back only its own changes.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(tx *Repository) error) error
 }
 
 type Repository struct {
-	db     *pgxpool.Pool
-	User   UserRepository
-	Bottle BottleRepository
-	Ocean  OceanRepository
-	Tag    TagRepository
+	db           *pgxpool.Pool
+	User         UserRepository
+	Bottle       BottleRepository
+	Ocean        OceanRepository
+	Tag          TagRepository
+	Notification NotificationRepository
+	Identity     IdentityRepository
+	Session      SessionRepository
+	Export       ExportRepository
+	Block        BlockRepository
+	Report       ReportRepository
+	Audit        AuditRepository
+	Transactor   Transactor
 }
 
 func (r *Repository) Close() error {
-	r.db.Close()
+	// In-memory repositories have no database to close
+	if r.db != nil {
+		r.db.Close()
+	}
 	return nil
 }
 
@@ -55,12 +224,43 @@ func (r *Repository) GetDB() *pgxpool.Pool {
 	return r.db
 }
 
+// WithTx runs fn as a single unit of work. See Transactor.
+func (r *Repository) WithTx(ctx context.Context, fn func(tx *Repository) error) error {
+	return r.Transactor.WithTx(ctx, fn)
+}
+
 func NewRepository(db *pgxpool.Pool) *Repository {
+	repo := newRepository(db)
+	repo.db = db
+	return repo
+}
+
+// newRepository builds the Postgres repositories on a pool or transaction.
+func newRepository(db schema.DBTX) *Repository {
 	return &Repository{
-		db:     db,
-		User:   schema.NewUserRepository(db),
-		Ocean:  schema.NewOceanRepository(db),
-		Tag:    schema.NewTagRepository(db),
-		Bottle: schema.NewBottleRepository(db),
+		User:         schema.NewUserRepository(db),
+		Ocean:        schema.NewOceanRepository(db),
+		Tag:          schema.NewTagRepository(db),
+		Bottle:       schema.NewBottleRepository(db),
+		Notification: schema.NewNotificationRepository(db),
+		Identity:     schema.NewIdentityRepository(db),
+		Session:      schema.NewSessionRepository(db),
+		Export:       schema.NewExportRepository(db),
+		Block:        schema.NewBlockRepository(db),
+		Report:       schema.NewReportRepository(db),
+		Audit:        schema.NewAuditRepository(db),
+		Transactor:   &postgresTransactor{db},
 	}
 }
+
+// postgresTransactor runs units of work as Postgres transactions, or as savepoints when
+// already inside one.
+type postgresTransactor struct {
+	db schema.DBTX
+}
+
+func (t *postgresTransactor) WithTx(ctx context.Context, fn func(tx *Repository) error) error {
+	return pgx.BeginFunc(ctx, t.db, func(tx pgx.Tx) error {
+		return fn(newRepository(tx))
+	})
+}
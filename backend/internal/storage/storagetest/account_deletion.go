@@ -0,0 +1,137 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testAccountDeletion(t *testing.T, s *suite) {
+	user := s.addUser(t)
+	later := time.Now().Add(30 * 24 * time.Hour)
+
+	deletion, err := s.repo.User.ScheduleDeletion(s.ctx, user.ID, true, later)
+	if err != nil {
+		t.Fatalf("ScheduleDeletion: %v", err)
+	}
+	if deletion.UserID != user.ID || !deletion.KeepBottles || deletion.DeleteAfter.Before(time.Now()) {
+		t.Errorf("ScheduleDeletion = %+v", deletion)
+	}
+	_, err = s.repo.User.ScheduleDeletion(s.ctx, user.ID, false, later)
+	wantStatus(t, err, http.StatusConflict)
+	_, err = s.repo.User.ScheduleDeletion(s.ctx, uuid.New(), false, later)
+	wantStatus(t, err, http.StatusNotFound)
+
+	got, err := s.repo.User.GetDeletion(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetDeletion: %v", err)
+	}
+	if !got.KeepBottles || !got.DeleteAfter.Equal(deletion.DeleteAfter) {
+		t.Errorf("GetDeletion = %+v, want %+v", got, deletion)
+	}
+
+	// Nothing is due until the grace period is over
+	due, err := s.repo.User.GetDueDeletions(s.ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetDueDeletions: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("GetDueDeletions = %+v, want none yet", due)
+	}
+	due, err = s.repo.User.GetDueDeletions(s.ctx, later.Add(time.Second))
+	if err != nil {
+		t.Fatalf("GetDueDeletions: %v", err)
+	}
+	if len(due) != 1 || due[0].UserID != user.ID {
+		t.Errorf("GetDueDeletions after the grace period = %+v, want %s", due, user.ID)
+	}
+
+	if err := s.repo.User.CancelDeletion(s.ctx, user.ID); err != nil {
+		t.Fatalf("CancelDeletion: %v", err)
+	}
+	_, err = s.repo.User.GetDeletion(s.ctx, user.ID)
+	wantStatus(t, err, http.StatusNotFound)
+	wantStatus(t, s.repo.User.CancelDeletion(s.ctx, user.ID), http.StatusNotFound)
+
+	t.Run("DeleteBottles", func(t *testing.T) {
+		author, reader := s.addUser(t), s.addUser(t)
+		s.createOcean(t, author)
+		bottle := s.throw(t, "delete me", s.defaultTag(t), &author, models.BottleStatusAfloat)
+		s.catch(t, s.defaultOcean(t), author)
+
+		purged, err := s.repo.User.PurgeUser(s.ctx, author.ID, false)
+		if err != nil {
+			t.Fatalf("PurgeUser: %v", err)
+		}
+		if purged != 1 {
+			t.Errorf("PurgeUser purged %d bottles, want 1", purged)
+		}
+
+		if _, err := s.repo.Bottle.GetBottleByID(s.ctx, bottle.ID); err == nil {
+			t.Error("GetBottleByID found a purged user's bottle")
+		}
+		if _, err := s.repo.Ocean.GetOceanByUser(s.ctx, author.ID); err == nil {
+			t.Error("GetOceanByUser found a purged user's ocean")
+		}
+		if _, err := s.repo.User.GetUserProfile(s.ctx, author.ID.String()); err == nil {
+			t.Error("GetUserProfile found a purged user")
+		}
+		if _, err := s.repo.User.GetUserProfile(s.ctx, reader.ID.String()); err != nil {
+			t.Errorf("GetUserProfile of another user: %v", err)
+		}
+	})
+
+	t.Run("KeepBottles", func(t *testing.T) {
+		author := s.addUser(t)
+		s.createOcean(t, author)
+		name := "Sea Farer"
+		bottle, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{
+			Content:  "keep me, d***",
+			Author:   &name,
+			TagID:    &[]int{s.defaultTag(t).ID}[0],
+			UserID:   &author.ID,
+			Original: &models.BottleOriginal{Content: "keep me, damn", Author: &name},
+		})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		if _, err := s.repo.User.ScheduleDeletion(s.ctx, author.ID, true, time.Now()); err != nil {
+			t.Fatalf("ScheduleDeletion: %v", err)
+		}
+
+		purged, err := s.repo.User.PurgeUser(s.ctx, author.ID, true)
+		if err != nil {
+			t.Fatalf("PurgeUser: %v", err)
+		}
+		if purged != 1 {
+			t.Errorf("PurgeUser anonymized %d bottles, want 1", purged)
+		}
+
+		kept, err := s.repo.Bottle.GetBottleByID(s.ctx, bottle.ID)
+		if err != nil {
+			t.Fatalf("GetBottleByID: %v", err)
+		}
+		if kept.Author != nil || kept.UserID != nil {
+			t.Errorf("kept bottle still has author %v and user %v", kept.Author, kept.UserID)
+		}
+		original, err := s.repo.Bottle.GetBottleOriginal(s.ctx, bottle.ID)
+		if err != nil {
+			t.Fatalf("GetBottleOriginal: %v", err)
+		}
+		if original.Author != nil || original.Content != "keep me, damn" {
+			t.Errorf("kept bottle's original = %+v, want its text without the author", original)
+		}
+		if _, err := s.repo.Ocean.GetOceanByUser(s.ctx, author.ID); err == nil {
+			t.Error("GetOceanByUser found a purged user's ocean")
+		}
+		// The pending deletion goes with the user
+		_, err = s.repo.User.GetDeletion(s.ctx, author.ID)
+		wantStatus(t, err, http.StatusNotFound)
+	})
+
+	_, err = s.repo.User.PurgeUser(s.ctx, uuid.New(), false)
+	wantStatus(t, err, http.StatusNotFound)
+}
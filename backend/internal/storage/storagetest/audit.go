@@ -0,0 +1,130 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"testing"
+	"time"
+)
+
+func testAudit(t *testing.T, s *suite) {
+	moderator := s.addUser(t)
+	user := s.addUser(t)
+
+	bottleTarget, userTarget := "1", user.ID.String()
+	reason := "Repeated harassment"
+	entries := []models.AuditEntry{
+		{
+			Action:     models.AuditBottleScreened,
+			TargetType: models.AuditTargetBottle,
+			TargetID:   &bottleTarget,
+			Details:    map[string]any{"verdict": "blocked", "score": 0.75, "rules": []string{"threat.watch-your-back"}},
+		},
+		{
+			Action:     models.AuditReportResolved,
+			ActorID:    &moderator.ID,
+			TargetType: models.AuditTargetBottle,
+			TargetID:   &bottleTarget,
+			Reason:     &reason,
+		},
+		{
+			Action:     models.AuditUserStruck,
+			ActorID:    &moderator.ID,
+			TargetType: models.AuditTargetUser,
+			TargetID:   &userTarget,
+			Reason:     &reason,
+		},
+	}
+
+	var recorded []models.AuditEntry
+	for _, entry := range entries {
+		r, err := s.repo.Audit.RecordAudit(s.ctx, entry)
+		if err != nil {
+			t.Fatalf("RecordAudit(%s): %v", entry.Action, err)
+		}
+		recorded = append(recorded, *r)
+	}
+	if recorded[0].Details["verdict"] != "blocked" || recorded[0].Details["score"] != 0.75 {
+		t.Errorf("RecordAudit details = %v, want the verdict and score", recorded[0].Details)
+	}
+	if rules, _ := recorded[0].Details["rules"].([]any); len(rules) != 1 || rules[0] != "threat.watch-your-back" {
+		t.Errorf("RecordAudit rules = %v, want the matched rule", recorded[0].Details["rules"])
+	}
+	if recorded[1].Details == nil || len(recorded[1].Details) != 0 {
+		t.Errorf("RecordAudit details = %v, want an empty object", recorded[1].Details)
+	}
+	if recorded[2].ActorID == nil || *recorded[2].ActorID != moderator.ID || recorded[2].Reason == nil || *recorded[2].Reason != reason {
+		t.Errorf("RecordAudit = %+v, want the moderator and their reason", recorded[2])
+	}
+
+	list := func(filter models.ListAuditRequest) []int64 {
+		t.Helper()
+		found, err := s.repo.Audit.ListAudit(s.ctx, filter, page())
+		if err != nil {
+			t.Fatalf("ListAudit: %v", err)
+		}
+		ids := []int64{}
+		for _, entry := range found {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+	want := func(name string, got []int64, entries ...models.AuditEntry) {
+		t.Helper()
+		ids := []int64{}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		if len(got) != len(ids) {
+			t.Errorf("ListAudit(%s) = %v, want %v", name, got, ids)
+			return
+		}
+		for i := range ids {
+			if got[i] != ids[i] {
+				t.Errorf("ListAudit(%s) = %v, want %v", name, got, ids)
+				return
+			}
+		}
+	}
+
+	// Most recent first
+	want("all", list(models.ListAuditRequest{}), recorded[2], recorded[1], recorded[0])
+
+	action := models.AuditBottleScreened
+	want("action", list(models.ListAuditRequest{Action: &action}), recorded[0])
+	want("actor", list(models.ListAuditRequest{ActorID: &moderator.ID}), recorded[2], recorded[1])
+
+	targetType := models.AuditTargetBottle
+	want("target", list(models.ListAuditRequest{TargetType: &targetType, TargetID: &bottleTarget}), recorded[1], recorded[0])
+	want("user target", list(models.ListAuditRequest{TargetID: &userTarget}), recorded[2])
+
+	since, until := recorded[0].CreatedAt, recorded[2].CreatedAt.Add(time.Second)
+	want("time range", list(models.ListAuditRequest{Since: &since, Until: &until}), recorded[2], recorded[1], recorded[0])
+	want("before", list(models.ListAuditRequest{Until: &since}))
+
+	deleted, err := s.repo.Audit.DeleteAuditBefore(s.ctx, since)
+	if err != nil {
+		t.Fatalf("DeleteAuditBefore: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("DeleteAuditBefore the first entry = %d, want 0", deleted)
+	}
+	deleted, err = s.repo.Audit.DeleteAuditBefore(s.ctx, until)
+	if err != nil {
+		t.Fatalf("DeleteAuditBefore: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("DeleteAuditBefore = %d, want 3", deleted)
+	}
+	want("after retention", list(models.ListAuditRequest{}))
+
+	// The log outlives the users it mentions
+	if _, err := s.repo.Audit.RecordAudit(s.ctx, entries[2]); err != nil {
+		t.Fatalf("RecordAudit: %v", err)
+	}
+	if _, err := s.repo.User.DeleteUser(s.ctx, moderator.ID.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if got := list(models.ListAuditRequest{ActorID: &moderator.ID}); len(got) != 1 {
+		t.Errorf("ListAudit by a deleted actor = %v, want their entry kept", got)
+	}
+}
@@ -0,0 +1,171 @@
+package storagetest
+
+import (
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testBlocks(t *testing.T, s *suite) {
+	reader := s.addUser(t)
+	pest := s.addUser(t)
+	friend := s.addUser(t)
+	ocean := s.defaultOcean(t)
+	tag := s.defaultTag(t)
+
+	pestBottle := s.throw(t, "unwanted words", tag, &pest, models.BottleStatusAfloat)
+	friendBottle := s.throw(t, "kind words", tag, &friend, models.BottleStatusAfloat)
+
+	block, err := s.repo.Block.BlockUser(s.ctx, reader.ID, pest.ID, &pestBottle.ID)
+	if err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	if block.BlockedID != pest.ID || block.BottleID == nil || *block.BottleID != pestBottle.ID {
+		t.Errorf("BlockUser = %+v", block)
+	}
+
+	_, err = s.repo.Block.BlockUser(s.ctx, reader.ID, pest.ID, nil)
+	wantStatus(t, err, http.StatusConflict)
+	_, err = s.repo.Block.BlockUser(s.ctx, reader.ID, uuid.New(), nil)
+	wantStatus(t, err, http.StatusNotFound)
+
+	blocks, err := s.repo.Block.GetBlocks(s.ctx, reader.ID)
+	if err != nil {
+		t.Fatalf("GetBlocks: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].BlockedID != pest.ID {
+		t.Errorf("GetBlocks = %+v, want the pest", blocks)
+	}
+
+	// Blocks count either way
+	for _, pair := range [][2]uuid.UUID{{reader.ID, pest.ID}, {pest.ID, reader.ID}} {
+		blocked, err := s.repo.Block.IsBlocked(s.ctx, pair[0], pair[1])
+		if err != nil {
+			t.Fatalf("IsBlocked: %v", err)
+		}
+		if !blocked {
+			t.Errorf("IsBlocked(%s, %s) = false", pair[0], pair[1])
+		}
+	}
+	if blocked, err := s.repo.Block.IsBlocked(s.ctx, reader.ID, friend.ID); err != nil || blocked {
+		t.Errorf("IsBlocked of a friend = %v, %v", blocked, err)
+	}
+
+	// The blocker no longer finds the blocked author's bottles, though others still do
+	bottles, err := s.repo.Bottle.GetBottles(s.ctx, models.GetBottlesRequest{OceanID: ocean.ID, ViewerID: &reader.ID})
+	if err != nil {
+		t.Fatalf("GetBottles: %v", err)
+	}
+	found := ids(bottles, bottleID)
+	if found[pestBottle.ID] || !found[friendBottle.ID] {
+		t.Errorf("GetBottles for the blocker found the pest's bottle %v and the friend's %v", found[pestBottle.ID], found[friendBottle.ID])
+	}
+	bottles, err = s.repo.Bottle.GetBottles(s.ctx, models.GetBottlesRequest{OceanID: ocean.ID, ViewerID: &friend.ID})
+	if err != nil {
+		t.Fatalf("GetBottles: %v", err)
+	}
+	if !ids(bottles, bottleID)[pestBottle.ID] {
+		t.Error("GetBottles for someone else left out the pest's bottle")
+	}
+
+	results, err := s.repo.Bottle.SearchBottles(s.ctx, models.SearchBottlesRequest{Query: "words"}, &reader.ID, page())
+	if err != nil {
+		t.Fatalf("SearchBottles: %v", err)
+	}
+	for _, result := range results {
+		if result.ID == pestBottle.ID {
+			t.Error("SearchBottles for the blocker found the pest's bottle")
+		}
+	}
+
+	// Catching until the ocean runs dry never brings up the blocked author's bottle
+	caughtFriend := false
+	for {
+		bottle, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{
+			OceanID:      ocean.ID,
+			SeenByUserId: &reader.ID,
+			ViewerID:     &reader.ID,
+		}, ocean)
+		if errs.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("GetRandomBottle: %v", err)
+		}
+		if bottle.ID == pestBottle.ID {
+			t.Fatal("GetRandomBottle gave the blocker the pest's bottle")
+		}
+		caughtFriend = caughtFriend || bottle.ID == friendBottle.ID
+	}
+	if !caughtFriend {
+		t.Error("GetRandomBottle never gave the blocker the friend's bottle")
+	}
+
+	// Replies are hidden from the author in both directions, but still seen by whoever sent them
+	readerBottle := s.throw(t, "write to me", tag, &reader, models.BottleStatusAfloat)
+	for _, replier := range []models.User{pest, friend} {
+		s.catchBottle(t, ocean, replier, readerBottle)
+		if _, err := s.repo.Bottle.CreateReply(s.ctx, readerBottle.ID, replier.ID, "hello"); err != nil {
+			t.Fatalf("CreateReply: %v", err)
+		}
+	}
+
+	wantReplies := func(viewer models.User, want ...models.User) {
+		t.Helper()
+		replies, err := s.repo.Bottle.GetReplies(s.ctx, readerBottle.ID, viewer.ID)
+		if err != nil {
+			t.Fatalf("GetReplies: %v", err)
+		}
+		if len(replies) != len(want) {
+			t.Fatalf("GetReplies for %s = %+v, want %d", viewer.ID, replies, len(want))
+		}
+		for i, reply := range replies {
+			if reply.UserID != want[i].ID {
+				t.Errorf("GetReplies for %s = %+v, want replies from %v", viewer.ID, replies, want)
+			}
+		}
+	}
+	wantReplies(reader, friend)
+	wantReplies(pest, pest)
+
+	// Blocking the other way hides replies just the same
+	if err := s.repo.Block.UnblockBottleAuthor(s.ctx, reader.ID, pestBottle.ID); err != nil {
+		t.Fatalf("UnblockBottleAuthor: %v", err)
+	}
+	wantReplies(reader, pest, friend)
+	if _, err := s.repo.Block.BlockUser(s.ctx, pest.ID, reader.ID, nil); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	wantReplies(reader, friend)
+
+	if err := s.repo.Block.UnblockUser(s.ctx, pest.ID, reader.ID); err != nil {
+		t.Fatalf("UnblockUser: %v", err)
+	}
+	wantStatus(t, s.repo.Block.UnblockUser(s.ctx, pest.ID, reader.ID), http.StatusNotFound)
+
+	// A block made from a bottle can be lifted from it after the bottle is gone
+	if _, err := s.repo.Block.BlockUser(s.ctx, reader.ID, pest.ID, &pestBottle.ID); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	if _, err := s.repo.Bottle.DeleteBottle(s.ctx, pestBottle.ID); err != nil {
+		t.Fatalf("DeleteBottle: %v", err)
+	}
+	if err := s.repo.Block.UnblockBottleAuthor(s.ctx, reader.ID, pestBottle.ID); err != nil {
+		t.Fatalf("UnblockBottleAuthor of a deleted bottle: %v", err)
+	}
+	wantStatus(t, s.repo.Block.UnblockBottleAuthor(s.ctx, reader.ID, friendBottle.ID), http.StatusNotFound)
+
+	// Deleting either user lifts their blocks
+	if _, err := s.repo.Block.BlockUser(s.ctx, reader.ID, pest.ID, nil); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	if _, err := s.repo.User.DeleteUser(s.ctx, pest.ID.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if blocks, err := s.repo.Block.GetBlocks(s.ctx, reader.ID); err != nil || len(blocks) != 0 {
+		t.Errorf("GetBlocks after the blocked user was deleted = %+v, %v", blocks, err)
+	}
+}
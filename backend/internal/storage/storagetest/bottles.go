@@ -0,0 +1,125 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testBottles(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	ocean := s.defaultOcean(t)
+
+	_, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "no tag"})
+	wantStatus(t, err, http.StatusBadRequest)
+
+	afloat := s.throw(t, "hello out there", defaultTag, &author, models.BottleStatusAfloat)
+	pending := s.throw(t, "held for review", defaultTag, &author, models.BottleStatusPending)
+	anonymous := s.throw(t, "nobody knows", defaultTag, nil, models.BottleStatusAfloat)
+
+	if afloat.Content != "hello out there" || afloat.TagID != defaultTag.ID || afloat.UserID == nil || *afloat.UserID != author.ID {
+		t.Errorf("CreateBottle = %+v", afloat)
+	}
+	if afloat.CreatedAt.IsZero() {
+		t.Error("CreateBottle did not set created_at")
+	}
+
+	// Bottles are afloat unless created otherwise
+	unset, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "default status", TagID: &defaultTag.ID})
+	if err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+	if unset.Status != models.BottleStatusAfloat {
+		t.Errorf("new bottle status = %q, want afloat", unset.Status)
+	}
+
+	got, err := s.repo.Bottle.GetBottleByID(s.ctx, pending.ID)
+	if err != nil {
+		t.Fatalf("GetBottleByID: %v", err)
+	}
+	if got.Status != models.BottleStatusPending {
+		t.Errorf("GetBottleByID status = %q, want pending", got.Status)
+	}
+
+	_, err = s.repo.Bottle.GetBottleByID(s.ctx, unset.ID+1000)
+	wantStatus(t, err, http.StatusNotFound)
+
+	bottles, err := s.repo.Bottle.GetBottles(s.ctx, models.GetBottlesRequest{OceanID: ocean.ID})
+	if err != nil {
+		t.Fatalf("GetBottles: %v", err)
+	}
+	if got := ids(bottles, bottleID); len(got) != 3 || !got[afloat.ID] || !got[anonymous.ID] || !got[unset.ID] {
+		t.Errorf("GetBottles = %+v, want the three afloat bottles", bottles)
+	}
+
+	if _, err := s.repo.Bottle.DeleteBottle(s.ctx, anonymous.ID); err != nil {
+		t.Fatalf("DeleteBottle: %v", err)
+	}
+	_, err = s.repo.Bottle.GetBottleByID(s.ctx, anonymous.ID)
+	wantStatus(t, err, http.StatusNotFound)
+}
+
+func testRandomBottle(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	reader := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	ocean := s.defaultOcean(t)
+
+	first := s.throw(t, "first", defaultTag, &author, models.BottleStatusAfloat)
+	second := s.throw(t, "second", defaultTag, &author, models.BottleStatusAfloat)
+	pending := s.throw(t, "pending", defaultTag, &author, models.BottleStatusPending)
+
+	// A reader never catches the same bottle twice, nor one that isn't afloat
+	caught := map[int]bool{}
+	caught[s.catch(t, ocean, reader).ID] = true
+	caught[s.catch(t, ocean, reader).ID] = true
+	if !caught[first.ID] || !caught[second.ID] {
+		t.Errorf("caught %v, want bottles %d and %d", caught, first.ID, second.ID)
+	}
+
+	_, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, SeenByUserId: &reader.ID}, ocean)
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Anonymous catches aren't remembered or counted
+	if _, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID}, ocean); err != nil {
+		t.Fatalf("anonymous GetRandomBottle: %v", err)
+	}
+
+	authored := s.authored(t, author)
+	for _, id := range []int{first.ID, second.ID} {
+		if bottle := authored[id]; bottle.CatchCount != 1 || bottle.State != "found" || bottle.FirstCaughtAt == nil {
+			t.Errorf("caught bottle on dashboard = %+v, want caught once and found", bottle)
+		}
+	}
+	if bottle := authored[pending.ID]; bottle.CatchCount != 0 || bottle.State != string(models.BottleStatusPending) {
+		t.Errorf("pending bottle on dashboard = %+v, want uncaught and pending", bottle)
+	}
+
+	dashboard, err := s.repo.Bottle.GetBottlesByUser(s.ctx, author.ID, models.PaginationRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetBottlesByUser: %v", err)
+	}
+	if len(dashboard) != 2 || dashboard[0].ID != pending.ID || dashboard[1].ID != second.ID {
+		t.Errorf("GetBottlesByUser page = %+v, want the two newest bottles newest first", dashboard)
+	}
+}
+
+func testPersonalOcean(t *testing.T, s *suite) {
+	owner := s.addUser(t)
+	stranger := s.addUser(t)
+	visitor := s.addUser(t)
+	personal := s.personalTag(t)
+	cove := s.createOcean(t, owner)
+
+	mine := s.throw(t, "for my cove", personal, &owner, models.BottleStatusAfloat)
+	s.throw(t, "for someone else's cove", personal, &stranger, models.BottleStatusAfloat)
+	s.throw(t, "for the open ocean", s.defaultTag(t), &owner, models.BottleStatusAfloat)
+
+	// A personal ocean only yields its owner's Personal bottles
+	if caught := s.catch(t, cove, visitor); caught.ID != mine.ID {
+		t.Errorf("caught bottle %d in personal ocean, want %d", caught.ID, mine.ID)
+	}
+	_, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: cove.ID, SeenByUserId: &visitor.ID}, cove)
+	wantStatus(t, err, http.StatusNotFound)
+}
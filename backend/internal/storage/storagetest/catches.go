@@ -0,0 +1,186 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testCatches(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	reader := s.addUser(t)
+	ocean := s.defaultOcean(t)
+	bottle := s.throw(t, "keep me", s.defaultTag(t), &author, models.BottleStatusAfloat)
+
+	note := "lovely"
+	_, err := s.repo.Bottle.SaveBookmark(s.ctx, reader.ID, bottle.ID, &note)
+	wantStatus(t, err, http.StatusNotFound)
+
+	s.catch(t, ocean, reader)
+
+	catches, err := s.repo.Bottle.GetCatches(s.ctx, reader.ID, page())
+	if err != nil {
+		t.Fatalf("GetCatches: %v", err)
+	}
+	if len(catches) != 1 || catches[0].ID != bottle.ID || catches[0].Bookmarked || catches[0].SeenAt.IsZero() {
+		t.Fatalf("GetCatches = %+v, want one unbookmarked catch of bottle %d", catches, bottle.ID)
+	}
+
+	bookmark, err := s.repo.Bottle.SaveBookmark(s.ctx, reader.ID, bottle.ID, &note)
+	if err != nil {
+		t.Fatalf("SaveBookmark: %v", err)
+	}
+	if bookmark.Note == nil || *bookmark.Note != note || bookmark.UserID != reader.ID || bookmark.BottleID != bottle.ID {
+		t.Errorf("SaveBookmark = %+v", bookmark)
+	}
+
+	updated := "still lovely"
+	if _, err := s.repo.Bottle.SaveBookmark(s.ctx, reader.ID, bottle.ID, &updated); err != nil {
+		t.Fatalf("SaveBookmark again: %v", err)
+	}
+
+	bookmarks, err := s.repo.Bottle.GetBookmarks(s.ctx, reader.ID, page())
+	if err != nil {
+		t.Fatalf("GetBookmarks: %v", err)
+	}
+	if len(bookmarks) != 1 || !bookmarks[0].Bookmarked || bookmarks[0].Note == nil || *bookmarks[0].Note != updated {
+		t.Errorf("GetBookmarks = %+v, want one bookmark noted %q", bookmarks, updated)
+	}
+
+	if err := s.repo.Bottle.DeleteBookmark(s.ctx, reader.ID, bottle.ID); err != nil {
+		t.Fatalf("DeleteBookmark: %v", err)
+	}
+	wantStatus(t, s.repo.Bottle.DeleteBookmark(s.ctx, reader.ID, bottle.ID), http.StatusNotFound)
+
+	// Throwing a bottle back lets the reader catch it again, and the catch counts again
+	if _, err := s.repo.Bottle.SaveBookmark(s.ctx, reader.ID, bottle.ID, nil); err != nil {
+		t.Fatalf("SaveBookmark: %v", err)
+	}
+	if err := s.repo.Bottle.ThrowBack(s.ctx, reader.ID, bottle.ID); err != nil {
+		t.Fatalf("ThrowBack: %v", err)
+	}
+	wantStatus(t, s.repo.Bottle.ThrowBack(s.ctx, reader.ID, bottle.ID), http.StatusNotFound)
+
+	catches, err = s.repo.Bottle.GetCatches(s.ctx, reader.ID, page())
+	if err != nil {
+		t.Fatalf("GetCatches: %v", err)
+	}
+	bookmarks, err = s.repo.Bottle.GetBookmarks(s.ctx, reader.ID, page())
+	if err != nil {
+		t.Fatalf("GetBookmarks: %v", err)
+	}
+	if len(catches) != 0 || len(bookmarks) != 0 {
+		t.Errorf("after ThrowBack: catches %+v, bookmarks %+v; want none", catches, bookmarks)
+	}
+
+	if caught := s.catch(t, ocean, reader); caught.ID != bottle.ID {
+		t.Errorf("recaught bottle %d, want %d", caught.ID, bottle.ID)
+	}
+	if stats := s.authored(t, author)[bottle.ID]; stats.CatchCount != 2 {
+		t.Errorf("catch count after throw back and recatch = %d, want 2", stats.CatchCount)
+	}
+}
+
+func testReplies(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	reader := s.addUser(t)
+	other := s.addUser(t)
+	ocean := s.defaultOcean(t)
+	bottle := s.throw(t, "write back", s.defaultTag(t), &author, models.BottleStatusAfloat)
+
+	_, err := s.repo.Bottle.CreateReply(s.ctx, bottle.ID, reader.ID, "too soon")
+	wantStatus(t, err, http.StatusForbidden)
+
+	s.catch(t, ocean, reader)
+	s.catch(t, ocean, other)
+
+	reply, err := s.repo.Bottle.CreateReply(s.ctx, bottle.ID, reader.ID, "hello back")
+	if err != nil {
+		t.Fatalf("CreateReply: %v", err)
+	}
+	if reply.BottleID != bottle.ID || reply.UserID != reader.ID || reply.Content != "hello back" {
+		t.Errorf("CreateReply = %+v", reply)
+	}
+	if _, err := s.repo.Bottle.CreateReply(s.ctx, bottle.ID, other.ID, "me too"); err != nil {
+		t.Fatalf("CreateReply: %v", err)
+	}
+
+	// The author sees every reply, everyone else only their own
+	replies, err := s.repo.Bottle.GetReplies(s.ctx, bottle.ID, author.ID)
+	if err != nil {
+		t.Fatalf("GetReplies: %v", err)
+	}
+	if len(replies) != 2 || replies[0].Content != "hello back" || replies[1].Content != "me too" {
+		t.Errorf("author's replies = %+v, want both in order", replies)
+	}
+
+	replies, err = s.repo.Bottle.GetReplies(s.ctx, bottle.ID, reader.ID)
+	if err != nil {
+		t.Fatalf("GetReplies: %v", err)
+	}
+	if len(replies) != 1 || replies[0].ID != reply.ID {
+		t.Errorf("reader's replies = %+v, want only their own", replies)
+	}
+
+	if stats := s.authored(t, author)[bottle.ID]; stats.ReplyCount != 2 {
+		t.Errorf("reply count = %d, want 2", stats.ReplyCount)
+	}
+
+	sent, err := s.repo.Bottle.GetRepliesByUser(s.ctx, reader.ID, page())
+	if err != nil {
+		t.Fatalf("GetRepliesByUser: %v", err)
+	}
+	if len(sent) != 1 || sent[0].ID != reply.ID {
+		t.Errorf("GetRepliesByUser = %+v, want only the reader's reply", sent)
+	}
+}
+
+func testRemovedCatches(t *testing.T, s *suite) {
+	author, reader := s.addUser(t), s.addUser(t)
+	ocean := s.defaultOcean(t)
+	bottle := s.throw(t, "soon gone", s.defaultTag(t), &author, models.BottleStatusAfloat)
+	s.catchBottle(t, ocean, reader, bottle)
+	if _, err := s.repo.Bottle.SaveBookmark(s.ctx, reader.ID, bottle.ID, nil); err != nil {
+		t.Fatalf("SaveBookmark: %v", err)
+	}
+
+	kept := func() (caught, bookmarked bool) {
+		t.Helper()
+		catches, err := s.repo.Bottle.GetCatches(s.ctx, reader.ID, page())
+		if err != nil {
+			t.Fatalf("GetCatches: %v", err)
+		}
+		bookmarks, err := s.repo.Bottle.GetBookmarks(s.ctx, reader.ID, page())
+		if err != nil {
+			t.Fatalf("GetBookmarks: %v", err)
+		}
+		return len(catches) == 1 && catches[0].ID == bottle.ID, len(bookmarks) == 1 && bookmarks[0].ID == bottle.ID
+	}
+	if caught, bookmarked := kept(); !caught || !bookmarked {
+		t.Fatalf("caught %v and bookmarked %v, want the bottle in both", caught, bookmarked)
+	}
+
+	// Bottles taken out of the ocean leave readers' collections, until they are set afloat again
+	for _, status := range []models.BottleStatus{models.BottleStatusPending, models.BottleStatusRemoved} {
+		if _, err := s.repo.Bottle.SetBottleStatus(s.ctx, bottle.ID, status); err != nil {
+			t.Fatalf("SetBottleStatus: %v", err)
+		}
+		if caught, bookmarked := kept(); caught || bookmarked {
+			t.Errorf("%s bottle caught %v and bookmarked %v, want it in neither", status, caught, bookmarked)
+		}
+	}
+	if _, err := s.repo.Bottle.SetBottleStatus(s.ctx, bottle.ID, models.BottleStatusAfloat); err != nil {
+		t.Fatalf("SetBottleStatus: %v", err)
+	}
+	if caught, bookmarked := kept(); !caught || !bookmarked {
+		t.Errorf("bottle set afloat again caught %v and bookmarked %v, want it back in both", caught, bookmarked)
+	}
+
+	// So do the bottles of authors the reader blocked
+	if _, err := s.repo.Block.BlockUser(s.ctx, reader.ID, author.ID, nil); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	if caught, bookmarked := kept(); caught || bookmarked {
+		t.Errorf("blocked author's bottle caught %v and bookmarked %v, want it in neither", caught, bookmarked)
+	}
+}
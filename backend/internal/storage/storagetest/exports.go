@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testExports(t *testing.T, s *suite) {
+	user := s.addUser(t)
+
+	job, err := s.repo.Export.CreateExportJob(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("CreateExportJob: %v", err)
+	}
+	if job.UserID != user.ID || job.Status != models.ExportStatusPending {
+		t.Errorf("CreateExportJob = %+v, want a pending job", job)
+	}
+	_, err = s.repo.Export.CreateExportJob(s.ctx, user.ID)
+	wantStatus(t, err, http.StatusConflict)
+
+	claimed, err := s.repo.Export.ClaimExportJob(s.ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ClaimExportJob: %v", err)
+	}
+	if claimed.ID != job.ID || claimed.Status != models.ExportStatusRunning || claimed.StartedAt == nil {
+		t.Errorf("ClaimExportJob = %+v, want job %s running", claimed, job.ID)
+	}
+	_, err = s.repo.Export.ClaimExportJob(s.ctx, time.Now().Add(-time.Hour))
+	wantStatus(t, err, http.StatusNotFound)
+
+	// A job whose worker went quiet is claimed again
+	reclaimed, err := s.repo.Export.ClaimExportJob(s.ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ClaimExportJob of a stale job: %v", err)
+	}
+	if reclaimed.ID != job.ID {
+		t.Errorf("ClaimExportJob = %s, want stale job %s", reclaimed.ID, job.ID)
+	}
+
+	_, err = s.repo.Export.GetExportArchive(s.ctx, job.ID)
+	wantStatus(t, err, http.StatusNotFound)
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := s.repo.Export.CompleteExportJob(s.ctx, job.ID, []byte("archive"), expiresAt); err != nil {
+		t.Fatalf("CompleteExportJob: %v", err)
+	}
+	ready, err := s.repo.Export.GetExportJob(s.ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetExportJob: %v", err)
+	}
+	if ready.Status != models.ExportStatusReady || ready.CompletedAt == nil || ready.ExpiresAt == nil {
+		t.Errorf("GetExportJob = %+v, want a ready job", ready)
+	}
+	archive, err := s.repo.Export.GetExportArchive(s.ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetExportArchive: %v", err)
+	}
+	if string(archive) != "archive" {
+		t.Errorf("GetExportArchive = %q, want %q", archive, "archive")
+	}
+
+	// Once the last export is done another can be requested, and can fail
+	next, err := s.repo.Export.CreateExportJob(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("CreateExportJob after the last one finished: %v", err)
+	}
+	if err := s.repo.Export.FailExportJob(s.ctx, next.ID, "out of ink"); err != nil {
+		t.Fatalf("FailExportJob: %v", err)
+	}
+	failed, err := s.repo.Export.GetExportJob(s.ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetExportJob: %v", err)
+	}
+	if failed.Status != models.ExportStatusFailed || failed.Error == nil || *failed.Error != "out of ink" {
+		t.Errorf("GetExportJob = %+v, want a failed job", failed)
+	}
+
+	deleted, err := s.repo.Export.DeleteExpiredExports(s.ctx, expiresAt.Add(time.Second))
+	if err != nil {
+		t.Fatalf("DeleteExpiredExports: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteExpiredExports deleted %d, want 1", deleted)
+	}
+	_, err = s.repo.Export.GetExportJob(s.ctx, job.ID)
+	wantStatus(t, err, http.StatusNotFound)
+
+	_, err = s.repo.Export.GetExportJob(s.ctx, uuid.New())
+	wantStatus(t, err, http.StatusNotFound)
+	wantStatus(t, s.repo.Export.CompleteExportJob(s.ctx, uuid.New(), nil, expiresAt), http.StatusNotFound)
+}
@@ -0,0 +1,149 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testIdentities(t *testing.T, s *suite) {
+	email := uuid.NewString() + "@storagetest.invalid"
+
+	identity, err := s.repo.Identity.CreateIdentity(s.ctx, email, "hash-1")
+	if err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+	if identity.UserID == uuid.Nil || identity.Email != email {
+		t.Errorf("CreateIdentity = %+v", identity)
+	}
+
+	_, err = s.repo.Identity.CreateIdentity(s.ctx, email, "hash-2")
+	wantStatus(t, err, http.StatusConflict)
+
+	if err := s.repo.Identity.UpdatePasswordHash(s.ctx, identity.UserID, "hash-3"); err != nil {
+		t.Fatalf("UpdatePasswordHash: %v", err)
+	}
+	found, err := s.repo.Identity.GetIdentityByEmail(s.ctx, email)
+	if err != nil {
+		t.Fatalf("GetIdentityByEmail: %v", err)
+	}
+	if found.UserID != identity.UserID || found.PasswordHash != "hash-3" {
+		t.Errorf("GetIdentityByEmail = %+v, want password hash-3 for %s", found, identity.UserID)
+	}
+
+	// A recovery token resets the password once, and only while it is the latest
+	unknown := uuid.New()
+	err = s.repo.Identity.SetRecoveryTokenHash(s.ctx, unknown, "recovery-1")
+	wantStatus(t, err, http.StatusNotFound)
+	for _, hash := range []string{"recovery-1", "recovery-2"} {
+		if err := s.repo.Identity.SetRecoveryTokenHash(s.ctx, identity.UserID, hash); err != nil {
+			t.Fatalf("SetRecoveryTokenHash: %v", err)
+		}
+	}
+	err = s.repo.Identity.ResetPassword(s.ctx, identity.UserID, "recovery-1", "hash-4")
+	wantStatus(t, err, http.StatusNotFound)
+	if err := s.repo.Identity.ResetPassword(s.ctx, identity.UserID, "recovery-2", "hash-4"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	err = s.repo.Identity.ResetPassword(s.ctx, identity.UserID, "recovery-2", "hash-5")
+	wantStatus(t, err, http.StatusNotFound)
+	found, err = s.repo.Identity.GetIdentityByEmail(s.ctx, email)
+	if err != nil {
+		t.Fatalf("GetIdentityByEmail: %v", err)
+	}
+	if found.PasswordHash != "hash-4" || found.RecoveryTokenHash != nil {
+		t.Errorf("GetIdentityByEmail after a reset = %+v, want password hash-4 and no recovery token", found)
+	}
+
+	_, err = s.repo.Identity.GetIdentityByEmail(s.ctx, "nobody@storagetest.invalid")
+	wantStatus(t, err, http.StatusNotFound)
+
+	// A local identity is enough for its profile to be found
+	if _, err := s.repo.User.AddUser(s.ctx, identity.UserID.String(), nil, nil, email); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if _, err := s.repo.User.GetUserProfile(s.ctx, identity.UserID.String()); err != nil {
+		t.Fatalf("GetUserProfile of a local identity: %v", err)
+	}
+
+	// Rotating swaps the refresh token; the old one stops working
+	expires := time.Now().Add(time.Hour)
+	session, err := s.repo.Identity.CreateSession(s.ctx, identity.UserID, "refresh-1", expires)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	rotated, err := s.repo.Identity.RotateSession(s.ctx, "refresh-1", "refresh-2", expires.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("RotateSession: %v", err)
+	}
+	if rotated.ID != session.ID || rotated.UserID != identity.UserID || !rotated.ExpiresAt.After(session.ExpiresAt) {
+		t.Errorf("RotateSession = %+v, want session %s extended", rotated, session.ID)
+	}
+	_, err = s.repo.Identity.RotateSession(s.ctx, "refresh-1", "refresh-3", expires)
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Revoked sessions can't be refreshed, but are still found
+	if err := s.repo.Identity.RevokeSession(s.ctx, session.ID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if err := s.repo.Identity.RevokeSession(s.ctx, session.ID); err != nil {
+		t.Fatalf("RevokeSession of a revoked session: %v", err)
+	}
+	_, err = s.repo.Identity.RotateSession(s.ctx, "refresh-2", "refresh-3", expires)
+	wantStatus(t, err, http.StatusNotFound)
+	revoked, err := s.repo.Identity.GetSession(s.ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if revoked.Live(time.Now()) {
+		t.Errorf("revoked session %+v is live", revoked)
+	}
+
+	// Revoking an account's sessions can spare one of them
+	kept, err := s.repo.Identity.CreateSession(s.ctx, identity.UserID, "refresh-kept", expires)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	ended, err := s.repo.Identity.CreateSession(s.ctx, identity.UserID, "refresh-ended", expires)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	revokedCount, err := s.repo.Identity.RevokeSessions(s.ctx, identity.UserID, kept.ID)
+	if err != nil {
+		t.Fatalf("RevokeSessions: %v", err)
+	}
+	if revokedCount != 1 {
+		t.Errorf("RevokeSessions = %d, want only the one live session not spared", revokedCount)
+	}
+	for _, tt := range []struct {
+		session models.IdentitySession
+		live    bool
+	}{{*kept, true}, {*ended, false}} {
+		got, err := s.repo.Identity.GetSession(s.ctx, tt.session.ID)
+		if err != nil {
+			t.Fatalf("GetSession: %v", err)
+		}
+		if got.Live(time.Now()) != tt.live {
+			t.Errorf("session %s live = %v after RevokeSessions, want %v", got.ID, !tt.live, tt.live)
+		}
+	}
+
+	// Expired sessions can't be refreshed either
+	if _, err := s.repo.Identity.CreateSession(s.ctx, identity.UserID, "refresh-old", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	_, err = s.repo.Identity.RotateSession(s.ctx, "refresh-old", "refresh-new", expires)
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Deleting the identity takes its sessions with it
+	if err := s.repo.Identity.DeleteIdentity(s.ctx, identity.UserID); err != nil {
+		t.Fatalf("DeleteIdentity: %v", err)
+	}
+	_, err = s.repo.Identity.GetSession(s.ctx, session.ID)
+	wantStatus(t, err, http.StatusNotFound)
+	err = s.repo.Identity.DeleteIdentity(s.ctx, identity.UserID)
+	wantStatus(t, err, http.StatusNotFound)
+}
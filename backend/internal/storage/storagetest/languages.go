@@ -0,0 +1,104 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func testLanguages(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	reader := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	ocean := s.defaultOcean(t)
+
+	throw := func(content string, language models.Language) models.Bottle {
+		t.Helper()
+		bottle, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: content, TagID: &defaultTag.ID, UserID: &author.ID, Language: &language})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		if bottle.Language == nil || *bottle.Language != language {
+			t.Fatalf("CreateBottle = %+v, want it in %s", bottle, language)
+		}
+		return *bottle
+	}
+	english := throw("hello from the beach", models.LanguageEnglish)
+	throw("hola desde la playa", models.LanguageSpanish)
+	undetermined := throw("ok", models.LanguageUndetermined)
+
+	// Readers only catch bottles in their languages, and those whose language couldn't be told
+	readable := []int{english.ID, undetermined.ID}
+	for range 10 {
+		caught, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, Languages: []models.Language{models.LanguageEnglish}}, ocean)
+		if err != nil {
+			t.Fatalf("GetRandomBottle by language: %v", err)
+		}
+		if !slices.Contains(readable, caught.ID) {
+			t.Errorf("GetRandomBottle(en) = bottle %d in %v, want one of %v", caught.ID, caught.Language, readable)
+		}
+	}
+	caught, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, Languages: []models.Language{models.LanguageFrench}}, ocean)
+	if err != nil {
+		t.Fatalf("GetRandomBottle by language: %v", err)
+	}
+	if caught.ID != undetermined.ID {
+		t.Errorf("GetRandomBottle(fr) = bottle %d, want the undetermined bottle %d", caught.ID, undetermined.ID)
+	}
+
+	// The languages a reader reads are kept on their profile until replaced
+	languages := []models.Language{models.LanguageEnglish, models.LanguageGerman}
+	profile, err := s.repo.User.UpdateUserProfile(s.ctx, reader.ID, models.UpdateProfileRequest{Languages: &languages})
+	if err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	if !slices.Equal(profile.Languages, languages) {
+		t.Errorf("UpdateUserProfile languages = %v, want %v", profile.Languages, languages)
+	}
+	bio := "reads slowly"
+	profile, err = s.repo.User.UpdateUserProfile(s.ctx, reader.ID, models.UpdateProfileRequest{Bio: &bio})
+	if err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	if !slices.Equal(profile.Languages, languages) {
+		t.Errorf("UpdateUserProfile without languages = %v, want them unchanged", profile.Languages)
+	}
+	cleared := []models.Language{}
+	if _, err := s.repo.User.UpdateUserProfile(s.ctx, reader.ID, models.UpdateProfileRequest{Languages: &cleared}); err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	profile, err = s.repo.User.GetUserProfile(s.ctx, reader.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if len(profile.Languages) != 0 {
+		t.Errorf("GetUserProfile languages = %v, want them cleared", profile.Languages)
+	}
+
+	unread := s.throw(t, "thrown before languages", defaultTag, &author, models.BottleStatusAfloat)
+	undetected, err := s.repo.Bottle.GetUndetectedBottles(s.ctx, 10)
+	if err != nil {
+		t.Fatalf("GetUndetectedBottles: %v", err)
+	}
+	if len(undetected) != 1 || undetected[0].ID != unread.ID {
+		t.Errorf("GetUndetectedBottles = %+v, want bottle %d", undetected, unread.ID)
+	}
+
+	if err := s.repo.Bottle.SetBottleLanguage(s.ctx, unread.ID, models.LanguageEnglish); err != nil {
+		t.Fatalf("SetBottleLanguage: %v", err)
+	}
+	got, err := s.repo.Bottle.GetBottleByID(s.ctx, unread.ID)
+	if err != nil {
+		t.Fatalf("GetBottleByID: %v", err)
+	}
+	if got.Language == nil || *got.Language != models.LanguageEnglish {
+		t.Errorf("bottle after SetBottleLanguage = %+v, want it in English", got)
+	}
+	if undetected, err := s.repo.Bottle.GetUndetectedBottles(s.ctx, 10); err != nil || len(undetected) != 0 {
+		t.Errorf("GetUndetectedBottles = %+v, %v, want none left", undetected, err)
+	}
+
+	err = s.repo.Bottle.SetBottleLanguage(s.ctx, unread.ID+1000, models.LanguageEnglish)
+	wantStatus(t, err, http.StatusNotFound)
+}
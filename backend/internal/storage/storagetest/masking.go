@@ -0,0 +1,104 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testProfanityMasking(t *testing.T, s *suite) {
+	owner := s.addUser(t)
+	stranger := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	personal := s.personalTag(t)
+	ocean := s.defaultOcean(t)
+	cove := s.createOcean(t, owner)
+
+	masks := func(tag models.Tag, author *models.User) bool {
+		t.Helper()
+		var authorID *uuid.UUID
+		if author != nil {
+			authorID = &author.ID
+		}
+		masking, err := s.repo.Ocean.MasksProfanity(s.ctx, tag.ID, authorID)
+		if err != nil {
+			t.Fatalf("MasksProfanity: %v", err)
+		}
+		return masking
+	}
+
+	if ocean.MaskProfanity || cove.MaskProfanity {
+		t.Errorf("new oceans mask profanity: %+v, %+v", ocean, cove)
+	}
+	if masks(defaultTag, nil) || masks(personal, &owner) {
+		t.Error("MasksProfanity = true before any ocean opted in")
+	}
+
+	enabled := true
+	updated, err := s.repo.Ocean.UpdateOceanSettings(s.ctx, cove.ID, models.UpdateOceanSettingsRequest{MaskProfanity: &enabled})
+	if err != nil {
+		t.Fatalf("UpdateOceanSettings: %v", err)
+	}
+	if !updated.MaskProfanity || updated.Name == nil || *updated.Name != *cove.Name {
+		t.Errorf("UpdateOceanSettings = %+v, want the cove masking profanity", updated)
+	}
+	unchanged, err := s.repo.Ocean.UpdateOceanSettings(s.ctx, cove.ID, models.UpdateOceanSettingsRequest{})
+	if err != nil {
+		t.Fatalf("UpdateOceanSettings: %v", err)
+	}
+	if !unchanged.MaskProfanity {
+		t.Error("UpdateOceanSettings with nothing to change turned masking off")
+	}
+	_, err = s.repo.Ocean.UpdateOceanSettings(s.ctx, cove.ID+1000, models.UpdateOceanSettingsRequest{MaskProfanity: &enabled})
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Only bottles drifting into the cove are masked: its owner's personal ones
+	if !masks(personal, &owner) {
+		t.Error("MasksProfanity = false for a bottle bound for a masking ocean")
+	}
+	if masks(personal, &stranger) || masks(personal, nil) || masks(defaultTag, &owner) {
+		t.Error("MasksProfanity = true for a bottle bound elsewhere")
+	}
+
+	// A bottle that also drifts into an ocean that doesn't mask isn't masked
+	lagoon := s.createOcean(t, owner)
+	if masks(personal, &owner) {
+		t.Error("MasksProfanity = true for a bottle also bound for an ocean that doesn't mask")
+	}
+	if _, err := s.repo.Ocean.UpdateOceanSettings(s.ctx, lagoon.ID, models.UpdateOceanSettingsRequest{MaskProfanity: &enabled}); err != nil {
+		t.Fatalf("UpdateOceanSettings: %v", err)
+	}
+	if !masks(personal, &owner) {
+		t.Error("MasksProfanity = false for a bottle bound only for masking oceans")
+	}
+
+	// The original text is kept for masked bottles only
+	author := "S***or"
+	original := models.BottleOriginal{Content: "well shit", Author: &author}
+	masked, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "well s***", TagID: &personal.ID, UserID: &owner.ID, Original: &original})
+	if err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+	if masked.Content != "well s***" {
+		t.Errorf("CreateBottle content = %q, want it masked", masked.Content)
+	}
+	got, err := s.repo.Bottle.GetBottleOriginal(s.ctx, masked.ID)
+	if err != nil {
+		t.Fatalf("GetBottleOriginal: %v", err)
+	}
+	if got.Content != original.Content || got.Author == nil || *got.Author != author || got.LocationFrom != nil {
+		t.Errorf("GetBottleOriginal = %+v, want %+v", got, original)
+	}
+
+	clean := s.throw(t, "all clear", personal, &owner, models.BottleStatusAfloat)
+	_, err = s.repo.Bottle.GetBottleOriginal(s.ctx, clean.ID)
+	wantStatus(t, err, http.StatusNotFound)
+
+	if _, err := s.repo.Bottle.DeleteBottle(s.ctx, masked.ID); err != nil {
+		t.Fatalf("DeleteBottle: %v", err)
+	}
+	_, err = s.repo.Bottle.GetBottleOriginal(s.ctx, masked.ID)
+	wantStatus(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,63 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testModeration(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	reader := s.addUser(t)
+	ocean := s.defaultOcean(t)
+	pending := s.throw(t, "awaiting review", s.defaultTag(t), &author, models.BottleStatusPending)
+	afloat := s.throw(t, "already out", s.defaultTag(t), &author, models.BottleStatusAfloat)
+	personal := s.throw(t, "my own", s.personalTag(t), &author, models.BottleStatusAfloat)
+
+	status := models.BottleStatusPending
+	listed, err := s.repo.Bottle.ListBottles(s.ctx, models.ListBottlesRequest{Status: &status}, page())
+	if err != nil {
+		t.Fatalf("ListBottles: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != pending.ID {
+		t.Errorf("ListBottles(pending) = %+v, want only bottle %d", listed, pending.ID)
+	}
+
+	listed, err = s.repo.Bottle.ListBottles(s.ctx, models.ListBottlesRequest{OceanID: &ocean.ID}, page())
+	if err != nil {
+		t.Fatalf("ListBottles: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != pending.ID || listed[1].ID != afloat.ID {
+		t.Errorf("ListBottles(default ocean) = %+v, want bottles %d and %d oldest first", listed, pending.ID, afloat.ID)
+	}
+	if ids(listed, bottleID)[personal.ID] {
+		t.Error("ListBottles(default ocean) included a Personal bottle")
+	}
+
+	approved, err := s.repo.Bottle.SetBottleStatus(s.ctx, pending.ID, models.BottleStatusAfloat)
+	if err != nil {
+		t.Fatalf("SetBottleStatus: %v", err)
+	}
+	if approved.Status != models.BottleStatusAfloat {
+		t.Errorf("SetBottleStatus returned status %q, want afloat", approved.Status)
+	}
+
+	if _, err := s.repo.Bottle.SetBottleStatus(s.ctx, afloat.ID, models.BottleStatusRemoved); err != nil {
+		t.Fatalf("SetBottleStatus: %v", err)
+	}
+	if caught := s.catch(t, ocean, reader); caught.ID != pending.ID {
+		t.Errorf("caught bottle %d, want the approved bottle %d", caught.ID, pending.ID)
+	}
+
+	_, err = s.repo.Bottle.SetBottleStatus(s.ctx, personal.ID+1000, models.BottleStatusAfloat)
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Statistics kept up to date by catches need no correcting
+	changed, err := s.repo.Bottle.RecomputeStats(s.ctx)
+	if err != nil {
+		t.Fatalf("RecomputeStats: %v", err)
+	}
+	if changed != 0 {
+		t.Errorf("RecomputeStats changed %d bottles, want 0", changed)
+	}
+}
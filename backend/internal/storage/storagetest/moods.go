@@ -0,0 +1,73 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testMoods(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	ocean := s.defaultOcean(t)
+
+	hopeful, score := models.MoodHopeful, 0.6
+	read, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "brighter days ahead", TagID: &defaultTag.ID, UserID: &author.ID, Mood: &hopeful, MoodScore: &score})
+	if err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+	if read.Mood == nil || *read.Mood != hopeful || read.MoodScore == nil || *read.MoodScore != score {
+		t.Errorf("CreateBottle = %+v, want it read as hopeful", read)
+	}
+	unread := s.throw(t, "thrown before moods", defaultTag, &author, models.BottleStatusAfloat)
+	if unread.Mood != nil || unread.MoodScore != nil {
+		t.Errorf("CreateBottle without a mood = %+v", unread)
+	}
+
+	// Readers fishing for a mood only catch bottles read as having it
+	sad := models.MoodSad
+	_, err = s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, Mood: &sad}, ocean)
+	wantStatus(t, err, http.StatusNotFound)
+	for range 3 {
+		caught, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, Mood: &hopeful}, ocean)
+		if err != nil {
+			t.Fatalf("GetRandomBottle by mood: %v", err)
+		}
+		if caught.ID != read.ID {
+			t.Errorf("GetRandomBottle(hopeful) = bottle %d, want %d", caught.ID, read.ID)
+		}
+	}
+
+	listed, err := s.repo.Bottle.GetBottles(s.ctx, models.GetBottlesRequest{OceanID: ocean.ID, Mood: &hopeful})
+	if err != nil {
+		t.Fatalf("GetBottles by mood: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != read.ID {
+		t.Errorf("GetBottles(hopeful) = %+v, want bottle %d", listed, read.ID)
+	}
+
+	unclassified, err := s.repo.Bottle.GetUnclassifiedBottles(s.ctx, 10)
+	if err != nil {
+		t.Fatalf("GetUnclassifiedBottles: %v", err)
+	}
+	if len(unclassified) != 1 || unclassified[0].ID != unread.ID {
+		t.Errorf("GetUnclassifiedBottles = %+v, want bottle %d", unclassified, unread.ID)
+	}
+
+	if err := s.repo.Bottle.SetBottleMood(s.ctx, unread.ID, sad, -0.4); err != nil {
+		t.Fatalf("SetBottleMood: %v", err)
+	}
+	got, err := s.repo.Bottle.GetBottleByID(s.ctx, unread.ID)
+	if err != nil {
+		t.Fatalf("GetBottleByID: %v", err)
+	}
+	if got.Mood == nil || *got.Mood != sad || got.MoodScore == nil || *got.MoodScore != -0.4 {
+		t.Errorf("bottle after SetBottleMood = %+v, want it read as sad", got)
+	}
+	if unclassified, err := s.repo.Bottle.GetUnclassifiedBottles(s.ctx, 10); err != nil || len(unclassified) != 0 {
+		t.Errorf("GetUnclassifiedBottles = %+v, %v, want none left", unclassified, err)
+	}
+
+	err = s.repo.Bottle.SetBottleMood(s.ctx, unread.ID+1000, sad, -0.4)
+	wantStatus(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,112 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testNotifications(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	other := s.addUser(t)
+	bottle := s.throw(t, "tell me when", s.defaultTag(t), &author, models.BottleStatusAfloat)
+
+	countUnread := func() int {
+		t.Helper()
+		count, err := s.repo.Notification.CountUnread(s.ctx, author.ID)
+		if err != nil {
+			t.Fatalf("CountUnread: %v", err)
+		}
+		return count
+	}
+
+	// Catches of a bottle on the same day fold into one notification
+	for range 2 {
+		if err := s.repo.Notification.RecordCatch(s.ctx, author.ID, bottle.ID); err != nil {
+			t.Fatalf("RecordCatch: %v", err)
+		}
+	}
+	if err := s.repo.Notification.CreateNotification(s.ctx, author.ID, models.NotificationBottleReplied, &bottle.ID, nil); err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+
+	notifications, err := s.repo.Notification.GetNotifications(s.ctx, author.ID, true, page())
+	if err != nil {
+		t.Fatalf("GetNotifications: %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("GetNotifications = %+v, want a catch batch and a reply", notifications)
+	}
+	var caught models.Notification
+	for _, notification := range notifications {
+		if notification.Message == "" {
+			t.Errorf("notification %d has no message", notification.ID)
+		}
+		if notification.Type == models.NotificationBottleCaught {
+			caught = notification
+		}
+	}
+	if caught.Count != 2 {
+		t.Errorf("catch notification = %+v, want count 2", caught)
+	}
+
+	wantStatus(t, s.repo.Notification.MarkRead(s.ctx, other.ID, caught.ID), http.StatusNotFound)
+	if err := s.repo.Notification.MarkRead(s.ctx, author.ID, caught.ID); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if count := countUnread(); count != 1 {
+		t.Errorf("unread after MarkRead = %d, want 1", count)
+	}
+
+	// A catch after the batch was read starts a new one
+	if err := s.repo.Notification.RecordCatch(s.ctx, author.ID, bottle.ID); err != nil {
+		t.Fatalf("RecordCatch: %v", err)
+	}
+	if count := countUnread(); count != 2 {
+		t.Errorf("unread after another catch = %d, want 2", count)
+	}
+
+	marked, err := s.repo.Notification.MarkAllRead(s.ctx, author.ID)
+	if err != nil {
+		t.Fatalf("MarkAllRead: %v", err)
+	}
+	if marked != 2 || countUnread() != 0 {
+		t.Errorf("MarkAllRead marked %d, want 2 and nothing left unread", marked)
+	}
+
+	all, err := s.repo.Notification.GetNotifications(s.ctx, author.ID, false, page())
+	if err != nil {
+		t.Fatalf("GetNotifications: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetNotifications(all) = %d notifications, want 3", len(all))
+	}
+
+	// Opting out of a type stops it being delivered
+	err = s.repo.Notification.UpdatePreferences(s.ctx, author.ID, models.UpdateNotificationPreferencesRequest{
+		models.NotificationBottleReplied: false,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+
+	preferences, err := s.repo.Notification.GetPreferences(s.ctx, author.ID)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if len(preferences) != len(models.NotificationTypes) {
+		t.Errorf("GetPreferences = %+v, want every type", preferences)
+	}
+	for _, preference := range preferences {
+		if want := preference.Type != models.NotificationBottleReplied; preference.Enabled != want {
+			t.Errorf("preference %s enabled = %v, want %v", preference.Type, preference.Enabled, want)
+		}
+	}
+
+	if err := s.repo.Notification.CreateNotification(s.ctx, author.ID, models.NotificationBottleReplied, &bottle.ID, nil); err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	if count := countUnread(); count != 0 {
+		t.Errorf("unread after a disabled notification = %d, want 0", count)
+	}
+}
@@ -0,0 +1,75 @@
+package storagetest
+
+import (
+	"database/sql"
+	"errors"
+	"hackmit/internal/models"
+	"testing"
+)
+
+func testOceans(t *testing.T, s *suite) {
+	defaultOcean := s.defaultOcean(t)
+	if defaultOcean.Name == nil || *defaultOcean.Name != "Default" || defaultOcean.UserID != nil {
+		t.Errorf("GetDefaultOcean = %+v", defaultOcean)
+	}
+
+	owner := s.addUser(t)
+	if _, err := s.repo.Ocean.GetOceanByUser(s.ctx, owner.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetOceanByUser before creating one: got %v, want sql.ErrNoRows", err)
+	}
+
+	cove := s.createOcean(t, owner)
+	if cove.UserID == nil || *cove.UserID != owner.ID {
+		t.Errorf("CreateOcean owner = %v, want %v", cove.UserID, owner.ID)
+	}
+
+	tags, err := s.repo.Tag.GetTagsByOcean(s.ctx, cove.ID)
+	if err != nil {
+		t.Fatalf("GetTagsByOcean: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != s.personalTag(t).ID {
+		t.Errorf("new personal ocean has tags %+v, want only Personal", tags)
+	}
+
+	found, err := s.repo.Ocean.GetOceanByUser(s.ctx, owner.ID)
+	if err != nil || found.ID != cove.ID {
+		t.Errorf("GetOceanByUser = %+v, %v; want ocean %d", found, err, cove.ID)
+	}
+
+	found, err = s.repo.Ocean.GetOceanById(s.ctx, cove.ID)
+	if err != nil || found.ID != cove.ID {
+		t.Errorf("GetOceanById = %+v, %v; want ocean %d", found, err, cove.ID)
+	}
+	if _, err := s.repo.Ocean.GetOceanById(s.ctx, cove.ID+1000); err == nil {
+		t.Error("GetOceanById of an unknown ocean succeeded")
+	}
+
+	// Someone else's personal ocean is found at random, never your own
+	if _, err := s.repo.Ocean.GetRandomPersonalOcean(s.ctx, &owner.ID); err == nil {
+		t.Error("GetRandomPersonalOcean returned the caller's own ocean")
+	}
+	visitor := s.addUser(t)
+	random, err := s.repo.Ocean.GetRandomPersonalOcean(s.ctx, &visitor.ID)
+	if err != nil || random.ID != cove.ID {
+		t.Errorf("GetRandomPersonalOcean = %+v, %v; want ocean %d", random, err, cove.ID)
+	}
+
+	name := "cOvE"
+	oceans, err := s.repo.Ocean.GetOceans(s.ctx, models.GetOceansRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("GetOceans: %v", err)
+	}
+	if len(oceans) != 1 || oceans[0].ID != cove.ID {
+		t.Errorf("GetOceans by name = %+v, want only ocean %d", oceans, cove.ID)
+	}
+
+	oceans, err = s.repo.Ocean.GetOceans(s.ctx, models.GetOceansRequest{IncludeTags: []int{s.defaultTag(t).ID}})
+	if err != nil {
+		t.Fatalf("GetOceans: %v", err)
+	}
+	if got := ids(oceans, oceanID); !got[defaultOcean.ID] || got[cove.ID] {
+		t.Errorf("GetOceans by Default tag = %+v, want the default ocean only", oceans)
+	}
+}
+
+func oceanID(ocean models.Ocean) int { return ocean.ID }
@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testProfiles(t *testing.T, s *suite) {
+	user := s.addUser(t)
+	other := s.addUser(t)
+
+	displayName := "Mariner-" + user.ID.String()[:8]
+	bio := "Collects bottles along the shore"
+	profile, err := s.repo.User.UpdateUserProfile(s.ctx, user.ID, models.UpdateProfileRequest{
+		DisplayName: &displayName,
+		Bio:         &bio,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	if profile.DisplayName == nil || *profile.DisplayName != displayName || profile.Bio == nil || *profile.Bio != bio {
+		t.Errorf("UpdateUserProfile = %+v, want display name %q and bio %q", profile, displayName, bio)
+	}
+	if profile.FirstName == nil || *profile.FirstName != "Sea" {
+		t.Errorf("UpdateUserProfile changed the unset first name to %v", profile.FirstName)
+	}
+
+	// Display names are unique regardless of case
+	taken := strings.ToUpper(displayName)
+	_, err = s.repo.User.UpdateUserProfile(s.ctx, other.ID, models.UpdateProfileRequest{DisplayName: &taken})
+	wantStatus(t, err, http.StatusConflict)
+
+	// Keeping one's own display name is not a conflict, and "" clears a field
+	empty := ""
+	profile, err = s.repo.User.UpdateUserProfile(s.ctx, user.ID, models.UpdateProfileRequest{
+		DisplayName: &displayName,
+		LastName:    &empty,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUserProfile: %v", err)
+	}
+	if profile.LastName != nil || profile.DisplayName == nil {
+		t.Errorf("UpdateUserProfile = %+v, want a display name and no last name", profile)
+	}
+
+	fetched, err := s.repo.User.GetUserProfile(s.ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if fetched.Bio == nil || *fetched.Bio != bio {
+		t.Errorf("GetUserProfile bio = %v, want %q", fetched.Bio, bio)
+	}
+
+	_, err = s.repo.User.UpdateUserProfile(s.ctx, uuid.New(), models.UpdateProfileRequest{Bio: &bio})
+	wantStatus(t, err, http.StatusNotFound)
+
+	first, second := "avatars/first", "avatars/second"
+	previous, err := s.repo.User.SetUserAvatar(s.ctx, user.ID, &first)
+	if err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+	if previous != nil {
+		t.Errorf("SetUserAvatar replaced %q, want nothing", *previous)
+	}
+	previous, err = s.repo.User.SetUserAvatar(s.ctx, user.ID, &second)
+	if err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+	if previous == nil || *previous != first {
+		t.Errorf("SetUserAvatar replaced %v, want %q", previous, first)
+	}
+
+	fetched, err = s.repo.User.GetUserProfile(s.ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if fetched.AvatarKey == nil || *fetched.AvatarKey != second {
+		t.Errorf("GetUserProfile avatar = %v, want %q", fetched.AvatarKey, second)
+	}
+
+	previous, err = s.repo.User.SetUserAvatar(s.ctx, user.ID, nil)
+	if err != nil {
+		t.Fatalf("SetUserAvatar: %v", err)
+	}
+	if previous == nil || *previous != second {
+		t.Errorf("SetUserAvatar cleared %v, want %q", previous, second)
+	}
+
+	_, err = s.repo.User.SetUserAvatar(s.ctx, uuid.New(), &first)
+	wantStatus(t, err, http.StatusNotFound)
+}
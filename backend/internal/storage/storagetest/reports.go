@@ -0,0 +1,129 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testReports(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	first := s.addUser(t)
+	second := s.addUser(t)
+	moderator := s.addUser(t)
+	ocean := s.defaultOcean(t)
+	bottle := s.throw(t, "offensive words", s.defaultTag(t), &author, models.BottleStatusAfloat)
+
+	comment := "not nice"
+	req := models.CreateReportRequest{Reason: models.ReportReasonHarassment, Comment: &comment}
+
+	// Only readers who caught the bottle may report it, and only once
+	_, err := s.repo.Report.CreateReport(s.ctx, bottle.ID, first.ID, req)
+	wantStatus(t, err, http.StatusForbidden)
+
+	s.catchBottle(t, ocean, first, bottle)
+	s.catchBottle(t, ocean, second, bottle)
+
+	report, err := s.repo.Report.CreateReport(s.ctx, bottle.ID, first.ID, req)
+	if err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+	if report.BottleID != bottle.ID || report.ReporterID != first.ID || report.Reason != req.Reason ||
+		report.Comment == nil || *report.Comment != comment || report.Status != models.ReportStatusPending {
+		t.Errorf("CreateReport = %+v", report)
+	}
+
+	_, err = s.repo.Report.CreateReport(s.ctx, bottle.ID, first.ID, req)
+	wantStatus(t, err, http.StatusConflict)
+
+	if _, err := s.repo.Report.CreateReport(s.ctx, bottle.ID, second.ID, models.CreateReportRequest{Reason: models.ReportReasonSpam}); err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+
+	count, err := s.repo.Report.CountPendingReports(s.ctx, bottle.ID)
+	if err != nil {
+		t.Fatalf("CountPendingReports: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("CountPendingReports = %d, want 2", count)
+	}
+
+	pending := models.ReportStatusPending
+	reports, err := s.repo.Report.ListReports(s.ctx, models.ListReportsRequest{Status: &pending, BottleID: &bottle.ID}, page())
+	if err != nil {
+		t.Fatalf("ListReports: %v", err)
+	}
+	if len(reports) != 2 || reports[0].ID != report.ID {
+		t.Errorf("ListReports = %+v, want both reports, oldest first", reports)
+	}
+
+	reports, err = s.repo.Report.ListReports(s.ctx, models.ListReportsRequest{ReporterID: &second.ID}, page())
+	if err != nil {
+		t.Fatalf("ListReports: %v", err)
+	}
+	if len(reports) != 1 || reports[0].ReporterID != second.ID {
+		t.Errorf("ListReports by reporter = %+v, want only theirs", reports)
+	}
+
+	// Resolving settles every pending report on the bottle
+	resolved, err := s.repo.Report.ResolveReports(s.ctx, bottle.ID, models.ReportStatusStruck, &moderator.ID)
+	if err != nil {
+		t.Fatalf("ResolveReports: %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Fatalf("ResolveReports resolved %d reports, want 2", len(resolved))
+	}
+	for _, report := range resolved {
+		if report.Status != models.ReportStatusStruck || report.ResolvedAt == nil || report.ResolvedBy == nil || *report.ResolvedBy != moderator.ID {
+			t.Errorf("resolved report = %+v", report)
+		}
+	}
+
+	if count, err := s.repo.Report.CountPendingReports(s.ctx, bottle.ID); err != nil || count != 0 {
+		t.Errorf("CountPendingReports after resolving = %d, %v", count, err)
+	}
+	if resolved, err := s.repo.Report.ResolveReports(s.ctx, bottle.ID, models.ReportStatusDismissed, &moderator.ID); err != nil || len(resolved) != 0 {
+		t.Errorf("ResolveReports with nothing pending = %+v, %v", resolved, err)
+	}
+
+	fetched, err := s.repo.Report.GetReport(s.ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetReport: %v", err)
+	}
+	if fetched.Status != models.ReportStatusStruck {
+		t.Errorf("GetReport status = %s, want %s", fetched.Status, models.ReportStatusStruck)
+	}
+	_, err = s.repo.Report.GetReport(s.ctx, -1)
+	wantStatus(t, err, http.StatusNotFound)
+
+	strike, err := s.repo.Report.AddStrike(s.ctx, author.ID, &bottle.ID, &moderator.ID)
+	if err != nil {
+		t.Fatalf("AddStrike: %v", err)
+	}
+	if strike.UserID != author.ID || strike.BottleID == nil || *strike.BottleID != bottle.ID {
+		t.Errorf("AddStrike = %+v", strike)
+	}
+	_, err = s.repo.Report.AddStrike(s.ctx, uuid.New(), nil, &moderator.ID)
+	wantStatus(t, err, http.StatusNotFound)
+
+	strikes, err := s.repo.Report.GetStrikes(s.ctx, author.ID)
+	if err != nil {
+		t.Fatalf("GetStrikes: %v", err)
+	}
+	if len(strikes) != 1 || strikes[0].ID != strike.ID {
+		t.Errorf("GetStrikes = %+v, want the one strike", strikes)
+	}
+
+	// Strikes outlive the bottle, while its reports go with it
+	if _, err := s.repo.Bottle.DeleteBottle(s.ctx, bottle.ID); err != nil {
+		t.Fatalf("DeleteBottle: %v", err)
+	}
+	if strikes, err := s.repo.Report.GetStrikes(s.ctx, author.ID); err != nil || len(strikes) != 1 || strikes[0].BottleID != nil {
+		t.Errorf("GetStrikes after the bottle was deleted = %+v, %v", strikes, err)
+	}
+	if _, err := s.repo.Report.GetReport(s.ctx, report.ID); err == nil {
+		t.Error("GetReport found a report on a deleted bottle")
+	}
+}
@@ -0,0 +1,81 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testRoles(t *testing.T, s *suite) {
+	admin := s.addUser(t)
+	user := s.addUser(t)
+
+	if user.Role != models.RoleUser {
+		t.Errorf("AddUser role = %q, want %q", user.Role, models.RoleUser)
+	}
+
+	// The first admin is made by an operator, so nobody is recorded as changing it
+	first, err := s.repo.User.SetUserRole(s.ctx, admin.ID, models.RoleAdmin, nil)
+	if err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	if first.UserID != admin.ID || first.OldRole != models.RoleUser || first.NewRole != models.RoleAdmin || first.ChangedBy != nil {
+		t.Errorf("SetUserRole = %+v, want user -> admin by nobody", first)
+	}
+
+	if _, err := s.repo.User.SetUserRole(s.ctx, user.ID, models.RoleModerator, &admin.ID); err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	if _, err := s.repo.User.SetUserRole(s.ctx, user.ID, models.RoleUser, &admin.ID); err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+
+	role, err := s.repo.User.GetUserRole(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserRole: %v", err)
+	}
+	if role != models.RoleUser {
+		t.Errorf("GetUserRole = %q, want %q", role, models.RoleUser)
+	}
+	profile, err := s.repo.User.GetUserProfile(s.ctx, admin.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.Role != models.RoleAdmin {
+		t.Errorf("GetUserProfile role = %q, want %q", profile.Role, models.RoleAdmin)
+	}
+
+	changes, err := s.repo.User.GetRoleChanges(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetRoleChanges: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("GetRoleChanges returned %d changes, want 2", len(changes))
+	}
+	if changes[0].OldRole != models.RoleModerator || changes[0].NewRole != models.RoleUser ||
+		changes[1].OldRole != models.RoleUser || changes[1].NewRole != models.RoleModerator {
+		t.Errorf("GetRoleChanges = %+v, want newest first", changes)
+	}
+	if changes[0].ChangedBy == nil || *changes[0].ChangedBy != admin.ID {
+		t.Errorf("role change made by %v, want %s", changes[0].ChangedBy, admin.ID)
+	}
+
+	_, err = s.repo.User.GetUserRole(s.ctx, uuid.New())
+	wantStatus(t, err, http.StatusNotFound)
+	_, err = s.repo.User.SetUserRole(s.ctx, uuid.New(), models.RoleAdmin, &admin.ID)
+	wantStatus(t, err, http.StatusNotFound)
+
+	// The audit trail outlives the admin who made the changes
+	if _, err := s.repo.User.DeleteUser(s.ctx, admin.ID.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	changes, err = s.repo.User.GetRoleChanges(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetRoleChanges: %v", err)
+	}
+	if len(changes) != 2 || changes[0].ChangedBy != nil {
+		t.Errorf("GetRoleChanges after deleting the admin = %+v, want 2 changes by nobody", changes)
+	}
+}
@@ -0,0 +1,77 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testSearch(t *testing.T, s *suite) {
+	owner := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+
+	song := s.throw(t, "the whale sang at dawn", defaultTag, nil, models.BottleStatusAfloat)
+	lonely := s.throw(t, "a lonely whale", defaultTag, nil, models.BottleStatusAfloat)
+	patrol := s.throw(t, "dawn patrol", defaultTag, nil, models.BottleStatusAfloat)
+	secret := s.throw(t, "my whale secret", s.personalTag(t), &owner, models.BottleStatusAfloat)
+	s.throw(t, "a pending whale", defaultTag, nil, models.BottleStatusPending)
+
+	search := func(query string, viewer *models.User) map[int]bool {
+		t.Helper()
+		req := models.SearchBottlesRequest{Query: query}
+		if viewer != nil {
+			results, err := s.repo.Bottle.SearchBottles(s.ctx, req, &viewer.ID, page())
+			if err != nil {
+				t.Fatalf("SearchBottles(%q): %v", query, err)
+			}
+			return ids(results, resultID)
+		}
+		results, err := s.repo.Bottle.SearchBottles(s.ctx, req, nil, page())
+		if err != nil {
+			t.Fatalf("SearchBottles(%q): %v", query, err)
+		}
+		return ids(results, resultID)
+	}
+
+	tests := []struct {
+		query  string
+		viewer *models.User
+		want   []int
+	}{
+		{"whale", nil, []int{song.ID, lonely.ID}},
+		{"whale", &owner, []int{song.ID, lonely.ID, secret.ID}},
+		{"whale -lonely", nil, []int{song.ID}},
+		{"lonely OR patrol", nil, []int{lonely.ID, patrol.ID}},
+		{`"whale sang"`, nil, []int{song.ID}},
+		{`"sang whale"`, nil, nil},
+		{"wha*", nil, []int{song.ID, lonely.ID}},
+		{"whale dawn", nil, []int{song.ID}},
+	}
+	for _, tt := range tests {
+		got := search(tt.query, tt.viewer)
+		if len(got) != len(tt.want) {
+			t.Errorf("SearchBottles(%q) found %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for _, id := range tt.want {
+			if !got[id] {
+				t.Errorf("SearchBottles(%q) found %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+
+	results, err := s.repo.Bottle.SearchBottles(s.ctx, models.SearchBottlesRequest{Query: "lonely"}, nil, page())
+	if err != nil {
+		t.Fatalf("SearchBottles: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Highlight, "<mark>lonely</mark>") {
+		t.Errorf("SearchBottles highlight = %+v, want lonely marked", results)
+	}
+
+	_, err = s.repo.Bottle.SearchBottles(s.ctx, models.SearchBottlesRequest{Query: "!!"}, nil, page())
+	wantStatus(t, err, http.StatusBadRequest)
+}
+
+func resultID(result models.SearchResult) int { return result.ID }
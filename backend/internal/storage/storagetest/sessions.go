@@ -0,0 +1,124 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testSessions(t *testing.T, s *suite) {
+	user := s.addUser(t)
+
+	userAgent := "Mozilla/5.0"
+	session, err := s.repo.Session.CreateSession(s.ctx, models.CreateSessionRequest{
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		RememberMe:       true,
+		RefreshTokenHash: "token-1",
+		UserAgent:        &userAgent,
+	})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if session.UserID != user.ID || !session.RememberMe || session.RevokedAt != nil ||
+		session.UserAgent == nil || *session.UserAgent != userAgent || session.IPAddress != nil {
+		t.Errorf("CreateSession = %+v", session)
+	}
+
+	token, err := s.repo.Session.GetRefreshToken(s.ctx, "token-1")
+	if err != nil {
+		t.Fatalf("GetRefreshToken: %v", err)
+	}
+	if token.SessionID != session.ID || token.UsedAt != nil {
+		t.Errorf("GetRefreshToken = %+v, want unused token of %s", token, session.ID)
+	}
+	_, err = s.repo.Session.GetRefreshToken(s.ctx, "token-unknown")
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Rotating uses up the old token; it can't be rotated again
+	rotated, err := s.repo.Session.RotateRefreshToken(s.ctx, "token-1", "token-2")
+	if err != nil {
+		t.Fatalf("RotateRefreshToken: %v", err)
+	}
+	if rotated.ID != session.ID || rotated.RefreshedAt.Before(session.RefreshedAt) {
+		t.Errorf("RotateRefreshToken = %+v, want session %s refreshed", rotated, session.ID)
+	}
+	used, err := s.repo.Session.GetRefreshToken(s.ctx, "token-1")
+	if err != nil {
+		t.Fatalf("GetRefreshToken: %v", err)
+	}
+	if used.UsedAt == nil {
+		t.Error("rotated refresh token is not marked used")
+	}
+	_, err = s.repo.Session.RotateRefreshToken(s.ctx, "token-1", "token-3")
+	wantStatus(t, err, http.StatusConflict)
+
+	// Nothing of a revoked session can be rotated
+	if err := s.repo.Session.RevokeSession(s.ctx, session.ID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	_, err = s.repo.Session.RotateRefreshToken(s.ctx, "token-2", "token-3")
+	wantStatus(t, err, http.StatusConflict)
+	revoked, err := s.repo.Session.GetSession(s.ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Error("revoked session has no revocation time")
+	}
+
+	err = s.repo.Session.RevokeSession(s.ctx, uuid.New())
+	wantStatus(t, err, http.StatusNotFound)
+
+	// Listing shows the user's live sessions, most recently used first
+	older, err := s.repo.Session.CreateSession(s.ctx, models.CreateSessionRequest{ID: uuid.New(), UserID: user.ID, RefreshTokenHash: "token-older"})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	newer, err := s.repo.Session.CreateSession(s.ctx, models.CreateSessionRequest{ID: uuid.New(), UserID: user.ID, RefreshTokenHash: "token-newer"})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	stranger := s.addUser(t)
+	if _, err := s.repo.Session.CreateSession(s.ctx, models.CreateSessionRequest{ID: uuid.New(), UserID: stranger.ID, RefreshTokenHash: "token-stranger"}); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := s.repo.Session.TouchSession(s.ctx, older.ID); err != nil {
+		t.Fatalf("TouchSession: %v", err)
+	}
+
+	listed, err := s.repo.Session.ListSessions(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != older.ID || listed[1].ID != newer.ID {
+		t.Errorf("ListSessions = %+v, want [%s %s]", listed, older.ID, newer.ID)
+	}
+
+	revokedCount, err := s.repo.Session.RevokeUserSessions(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("RevokeUserSessions: %v", err)
+	}
+	if revokedCount != 2 {
+		t.Errorf("RevokeUserSessions = %d, want 2", revokedCount)
+	}
+	if listed, _ := s.repo.Session.ListSessions(s.ctx, user.ID); len(listed) != 0 {
+		t.Errorf("ListSessions after RevokeUserSessions = %+v, want none", listed)
+	}
+	if listed, _ := s.repo.Session.ListSessions(s.ctx, stranger.ID); len(listed) != 1 {
+		t.Errorf("ListSessions of another user = %+v, want their one session", listed)
+	}
+
+	// Sessions go with their user
+	if _, err := s.repo.User.DeleteUser(s.ctx, user.ID.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	_, err = s.repo.Session.GetSession(s.ctx, session.ID)
+	wantStatus(t, err, http.StatusNotFound)
+	_, err = s.repo.Session.GetRefreshToken(s.ctx, "token-2")
+	wantStatus(t, err, http.StatusNotFound)
+}
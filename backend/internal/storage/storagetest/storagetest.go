@@ -0,0 +1,203 @@
+// Package storagetest is a conformance suite for storage.Repository implementations, so that
+// the in-memory store and Postgres are held to the same behaviour.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"hackmit/internal/errs"
+	"hackmit/internal/models"
+	"hackmit/internal/storage"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Harness builds the repositories under test.
+type Harness struct {
+	// New returns a repository holding nothing but the seed data (system tags and default ocean).
+	New func(t *testing.T) *storage.Repository
+	// AddIdentity registers the auth account a profile belongs to, for backends that need one.
+	// It may be nil.
+	AddIdentity func(t *testing.T, userID uuid.UUID, email string)
+	// AddTag creates a topic tag in the repository, since nothing in it can. It may be nil, and
+	// the cases that need one are then skipped.
+	AddTag func(t *testing.T, repo *storage.Repository, name string) models.Tag
+}
+
+// Run runs the whole suite against the harness.
+func Run(t *testing.T, h Harness) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s *suite)
+	}{
+		{"Users", testUsers},
+		{"Profiles", testProfiles},
+		{"Tags", testTags},
+		{"Oceans", testOceans},
+		{"Bottles", testBottles},
+		{"RandomBottle", testRandomBottle},
+		{"PersonalOcean", testPersonalOcean},
+		{"Catches", testCatches},
+		{"RemovedCatches", testRemovedCatches},
+		{"Replies", testReplies},
+		{"Search", testSearch},
+		{"Moderation", testModeration},
+		{"Notifications", testNotifications},
+		{"Identities", testIdentities},
+		{"Sessions", testSessions},
+		{"Roles", testRoles},
+		{"AccountDeletion", testAccountDeletion},
+		{"Exports", testExports},
+		{"Blocks", testBlocks},
+		{"Reports", testReports},
+		{"Audit", testAudit},
+		{"ProfanityMasking", testProfanityMasking},
+		{"Moods", testMoods},
+		{"Languages", testLanguages},
+		{"TagModels", testTagModels},
+		{"Transactions", testTransactions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, &suite{
+				ctx:     context.Background(),
+				harness: h,
+				repo:    h.New(t),
+			})
+		})
+	}
+}
+
+type suite struct {
+	ctx     context.Context
+	harness Harness
+	repo    *storage.Repository
+}
+
+func (s *suite) addUser(t *testing.T) models.User {
+	t.Helper()
+
+	id := uuid.New()
+	email := id.String() + "@storagetest.invalid"
+	if s.harness.AddIdentity != nil {
+		s.harness.AddIdentity(t, id, email)
+	}
+
+	firstName, lastName := "Sea", "Farer"
+	user, err := s.repo.User.AddUser(s.ctx, id.String(), &firstName, &lastName, email)
+	if err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	return *user
+}
+
+func (s *suite) defaultTag(t *testing.T) models.Tag {
+	t.Helper()
+	tag, err := s.repo.Tag.GetDefaultTag(s.ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultTag: %v", err)
+	}
+	return *tag
+}
+
+func (s *suite) personalTag(t *testing.T) models.Tag {
+	t.Helper()
+	tag, err := s.repo.Tag.GetPersonalTag(s.ctx)
+	if err != nil {
+		t.Fatalf("GetPersonalTag: %v", err)
+	}
+	return *tag
+}
+
+func (s *suite) defaultOcean(t *testing.T) models.Ocean {
+	t.Helper()
+	ocean, err := s.repo.Ocean.GetDefaultOcean(s.ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultOcean: %v", err)
+	}
+	return *ocean
+}
+
+func (s *suite) createOcean(t *testing.T, user models.User) models.Ocean {
+	t.Helper()
+	name, description := "Cove", "A quiet cove"
+	ocean, err := s.repo.Ocean.CreateOcean(s.ctx, &name, &description, user.ID)
+	if err != nil {
+		t.Fatalf("CreateOcean: %v", err)
+	}
+	return *ocean
+}
+
+// throw creates a bottle. A nil author throws it anonymously.
+func (s *suite) throw(t *testing.T, content string, tag models.Tag, author *models.User, status models.BottleStatus) models.Bottle {
+	t.Helper()
+
+	req := models.CreateBottleRequest{Content: content, TagID: &tag.ID, Status: &status}
+	if author != nil {
+		req.UserID = &author.ID
+	}
+
+	bottle, err := s.repo.Bottle.CreateBottle(s.ctx, req)
+	if err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+	return *bottle
+}
+
+// catch fishes a bottle out of an ocean on behalf of a reader.
+func (s *suite) catch(t *testing.T, ocean models.Ocean, reader models.User) models.Bottle {
+	t.Helper()
+	bottle, err := s.repo.Bottle.GetRandomBottle(s.ctx, models.GetRandomBottleRequest{OceanID: ocean.ID, SeenByUserId: &reader.ID}, ocean)
+	if err != nil {
+		t.Fatalf("GetRandomBottle: %v", err)
+	}
+	return *bottle
+}
+
+// catchBottle keeps fishing for a reader until they catch the given bottle.
+func (s *suite) catchBottle(t *testing.T, ocean models.Ocean, reader models.User, bottle models.Bottle) {
+	t.Helper()
+	for s.catch(t, ocean, reader).ID != bottle.ID {
+	}
+}
+
+func (s *suite) authored(t *testing.T, author models.User) map[int]models.AuthoredBottle {
+	t.Helper()
+	bottles, err := s.repo.Bottle.GetBottlesByUser(s.ctx, author.ID, page())
+	if err != nil {
+		t.Fatalf("GetBottlesByUser: %v", err)
+	}
+	byID := map[int]models.AuthoredBottle{}
+	for _, bottle := range bottles {
+		byID[bottle.ID] = bottle
+	}
+	return byID
+}
+
+func page() models.PaginationRequest {
+	p := models.PaginationRequest{}
+	p.Normalize()
+	return p
+}
+
+// wantStatus fails unless err is an HTTP error with the given status code.
+func wantStatus(t *testing.T, err error, code int) {
+	t.Helper()
+	var httpErr errs.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != code {
+		t.Fatalf("got error %v, want HTTP %d %s", err, code, http.StatusText(code))
+	}
+}
+
+func ids[T any](items []T, id func(T) int) map[int]bool {
+	set := map[int]bool{}
+	for _, item := range items {
+		set[id(item)] = true
+	}
+	return set
+}
+
+func bottleID(b models.Bottle) int { return b.ID }
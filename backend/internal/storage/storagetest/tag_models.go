@@ -0,0 +1,84 @@
+package storagetest
+
+import (
+	"encoding/json"
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+)
+
+func testTagModels(t *testing.T, s *suite) {
+	author := s.addUser(t)
+	defaultTag := s.defaultTag(t)
+	s.throw(t, "untagged", defaultTag, &author, models.BottleStatusAfloat)
+	s.throw(t, "to myself", s.personalTag(t), &author, models.BottleStatusAfloat)
+
+	// Only the system tags are seeded, and bottles under them teach nothing about topics
+	tagged, err := s.repo.Bottle.GetTaggedBottles(s.ctx, 10)
+	if err != nil {
+		t.Fatalf("GetTaggedBottles: %v", err)
+	}
+	if len(tagged) != 0 {
+		t.Errorf("GetTaggedBottles = %+v, want no Default or Personal bottles", tagged)
+	}
+
+	if s.harness.AddTag != nil {
+		travel := s.harness.AddTag(t, s.repo, "Travel")
+		suggested, fallback := models.TagSourceSuggested, models.TagSourceDefault
+		chosen := s.throw(t, "a train across the alps", travel, &author, models.BottleStatusAfloat)
+		guessed, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "a ferry to the islands", TagID: &travel.ID, UserID: &author.ID, TagSource: &suggested})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		untagged, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{Content: "just drifting", TagID: &defaultTag.ID, UserID: &author.ID, TagSource: &fallback})
+		if err != nil {
+			t.Fatalf("CreateBottle: %v", err)
+		}
+		if chosen.TagSource != models.TagSourceWriter || guessed.TagSource != suggested || untagged.TagSource != fallback {
+			t.Errorf("CreateBottle tag sources = %q, %q, %q", chosen.TagSource, guessed.TagSource, untagged.TagSource)
+		}
+
+		// The classifier never learns from the tags it applied itself
+		tagged, err := s.repo.Bottle.GetTaggedBottles(s.ctx, 10)
+		if err != nil {
+			t.Fatalf("GetTaggedBottles: %v", err)
+		}
+		if len(tagged) != 1 || tagged[0].ID != chosen.ID {
+			t.Errorf("GetTaggedBottles = %+v, want only the bottle its writer tagged", tagged)
+		}
+	}
+
+	_, err = s.repo.Tag.GetLatestTagModel(s.ctx)
+	wantStatus(t, err, http.StatusNotFound)
+
+	var saved *models.TagModel
+	for i, examples := range []int{40, 60} {
+		saved, err = s.repo.Tag.SaveTagModel(s.ctx, models.TagModel{
+			Model:    json.RawMessage(`{"idf": {}}`),
+			Report:   json.RawMessage(`{"accuracy": 0.5}`),
+			Examples: examples,
+		})
+		if err != nil {
+			t.Fatalf("SaveTagModel %d: %v", i, err)
+		}
+		if saved.ID == 0 || saved.TrainedAt.IsZero() || saved.Examples != examples {
+			t.Errorf("SaveTagModel = %+v", saved)
+		}
+	}
+
+	latest, err := s.repo.Tag.GetLatestTagModel(s.ctx)
+	if err != nil {
+		t.Fatalf("GetLatestTagModel: %v", err)
+	}
+	if latest.ID != saved.ID || latest.Examples != 60 {
+		t.Errorf("GetLatestTagModel = %+v, want model %d", latest, saved.ID)
+	}
+	var report struct{ Accuracy float64 }
+	if err := json.Unmarshal(latest.Report, &report); err != nil || report.Accuracy != 0.5 {
+		t.Errorf("GetLatestTagModel report = %s, %v", latest.Report, err)
+	}
+	var model struct{ IDF map[string]float64 }
+	if err := json.Unmarshal(latest.Model, &model); err != nil || model.IDF == nil {
+		t.Errorf("GetLatestTagModel model = %s, %v", latest.Model, err)
+	}
+}
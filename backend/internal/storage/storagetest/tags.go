@@ -0,0 +1,54 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"testing"
+)
+
+func testTags(t *testing.T, s *suite) {
+	defaultTag := s.defaultTag(t)
+	if defaultTag.Name == nil || *defaultTag.Name != "Default" {
+		t.Errorf("GetDefaultTag = %+v", defaultTag)
+	}
+
+	personal := s.personalTag(t)
+	if personal.Name == nil || *personal.Name != "Personal" {
+		t.Errorf("GetPersonalTag = %+v", personal)
+	}
+
+	tags, err := s.repo.Tag.GetTags(s.ctx, models.GetTagsRequest{})
+	if err != nil {
+		t.Fatalf("GetTags: %v", err)
+	}
+	if got := ids(tags, tagID); got[defaultTag.ID] || !got[personal.ID] {
+		t.Errorf("GetTags without include_default = %+v, want Personal but not Default", tags)
+	}
+
+	include := true
+	tags, err = s.repo.Tag.GetTags(s.ctx, models.GetTagsRequest{IncludeDefault: &include})
+	if err != nil {
+		t.Fatalf("GetTags: %v", err)
+	}
+	if got := ids(tags, tagID); !got[defaultTag.ID] || !got[personal.ID] {
+		t.Errorf("GetTags with include_default = %+v, want Default and Personal", tags)
+	}
+
+	name := "Personal"
+	tags, err = s.repo.Tag.GetTags(s.ctx, models.GetTagsRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("GetTags: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != personal.ID {
+		t.Errorf("GetTags by name = %+v, want only Personal", tags)
+	}
+
+	tags, err = s.repo.Tag.GetTagsByOcean(s.ctx, s.defaultOcean(t).ID)
+	if err != nil {
+		t.Fatalf("GetTagsByOcean: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != defaultTag.ID {
+		t.Errorf("GetTagsByOcean(default ocean) = %+v, want only Default", tags)
+	}
+}
+
+func tagID(tag models.Tag) int { return tag.ID }
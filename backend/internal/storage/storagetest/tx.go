@@ -0,0 +1,82 @@
+package storagetest
+
+import (
+	"errors"
+	"hackmit/internal/storage"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testTransactions(t *testing.T, s *suite) {
+	errAbort := errors.New("abort")
+
+	addUser := func(repo *storage.Repository) (uuid.UUID, error) {
+		id := uuid.New()
+		email := id.String() + "@storagetest.invalid"
+		if s.harness.AddIdentity != nil {
+			s.harness.AddIdentity(t, id, email)
+		}
+		_, err := repo.User.AddUser(s.ctx, id.String(), nil, nil, email)
+		return id, err
+	}
+
+	exists := func(id uuid.UUID) bool {
+		t.Helper()
+		_, err := s.repo.User.GetUserProfile(s.ctx, id.String())
+		return err == nil
+	}
+
+	// A failed unit of work leaves nothing behind
+	var rolledBack, oceanOwner uuid.UUID
+	err := s.repo.WithTx(s.ctx, func(tx *storage.Repository) error {
+		var err error
+		if rolledBack, err = addUser(tx); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx returned %v, want the unit of work's error", err)
+	}
+	if exists(rolledBack) {
+		t.Error("user created in a rolled back unit of work still exists")
+	}
+
+	// A successful one commits everything, except what a failed inner unit of work did
+	var committed, inner uuid.UUID
+	err = s.repo.WithTx(s.ctx, func(tx *storage.Repository) error {
+		var err error
+		if committed, err = addUser(tx); err != nil {
+			return err
+		}
+		oceanOwner = committed
+		if _, err := tx.Ocean.CreateOcean(s.ctx, nil, nil, committed); err != nil {
+			return err
+		}
+
+		err = tx.WithTx(s.ctx, func(tx *storage.Repository) error {
+			var err error
+			if inner, err = addUser(tx); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Errorf("nested WithTx returned %v, want the unit of work's error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if !exists(committed) {
+		t.Error("user created in a committed unit of work is missing")
+	}
+	if exists(inner) {
+		t.Error("user created in a rolled back nested unit of work still exists")
+	}
+	if _, err := s.repo.Ocean.GetOceanByUser(s.ctx, oceanOwner); err != nil {
+		t.Errorf("ocean created in a committed unit of work is missing: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package storagetest
+
+import (
+	"hackmit/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testUsers(t *testing.T, s *suite) {
+	user := s.addUser(t)
+
+	profile, err := s.repo.User.GetUserProfile(s.ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.ID != user.ID || profile.Email != user.Email || profile.FirstName == nil || *profile.FirstName != "Sea" {
+		t.Errorf("GetUserProfile = %+v, want %+v", profile, user)
+	}
+
+	if _, err := s.repo.User.AddUser(s.ctx, uuid.NewString(), nil, nil, user.Email); err == nil {
+		t.Error("AddUser with a taken email succeeded")
+	}
+
+	if _, err := s.repo.User.GetUserProfile(s.ctx, uuid.NewString()); err == nil {
+		t.Error("GetUserProfile of an unknown user succeeded")
+	}
+
+	// Anonymizing keeps the bottles but detaches them and clears the name
+	author := "Sea Farer"
+	bottle, err := s.repo.Bottle.CreateBottle(s.ctx, models.CreateBottleRequest{
+		Content:  "signed message, d***",
+		Author:   &author,
+		TagID:    &[]int{s.defaultTag(t).ID}[0],
+		UserID:   &user.ID,
+		Original: &models.BottleOriginal{Content: "signed message, damn", Author: &author},
+	})
+	if err != nil {
+		t.Fatalf("CreateBottle: %v", err)
+	}
+
+	detached, err := s.repo.User.AnonymizeUser(s.ctx, user.ID)
+	if err != nil {
+		t.Fatalf("AnonymizeUser: %v", err)
+	}
+	if detached != 1 {
+		t.Errorf("AnonymizeUser detached %d bottles, want 1", detached)
+	}
+
+	anonymous, err := s.repo.Bottle.GetBottleByID(s.ctx, bottle.ID)
+	if err != nil {
+		t.Fatalf("GetBottleByID: %v", err)
+	}
+	if anonymous.Author != nil || anonymous.UserID != nil {
+		t.Errorf("anonymized bottle still has author %v and user %v", anonymous.Author, anonymous.UserID)
+	}
+	original, err := s.repo.Bottle.GetBottleOriginal(s.ctx, bottle.ID)
+	if err != nil {
+		t.Fatalf("GetBottleOriginal: %v", err)
+	}
+	if original.Author != nil {
+		t.Errorf("anonymized bottle's original still has author %v", *original.Author)
+	}
+
+	profile, err = s.repo.User.GetUserProfile(s.ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.FirstName != nil || profile.LastName != nil {
+		t.Errorf("anonymized profile still named %v %v", profile.FirstName, profile.LastName)
+	}
+
+	_, err = s.repo.User.AnonymizeUser(s.ctx, uuid.New())
+	wantStatus(t, err, http.StatusNotFound)
+
+	if _, err := s.repo.User.DeleteUser(s.ctx, user.ID.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := s.repo.User.GetUserProfile(s.ctx, user.ID.String()); err == nil {
+		t.Error("GetUserProfile found a deleted user")
+	}
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// EventsChannel carries ocean activity published by database triggers.
+	EventsChannel = "ocean_events"
+	// PresenceChannel carries each server instance's count of connected sailors.
+	PresenceChannel = "ocean_presence"
+)
+
+// Broker is the pub/sub transport shared by every server instance.
+type Broker interface {
+	// Listen blocks, passing every message on the channels to handle until ctx is done or the connection fails.
+	Listen(ctx context.Context, channels []string, handle func(channel string, payload string)) error
+	Notify(ctx context.Context, channel string, payload string) error
+}
+
+// PostgresBroker fans messages out with Postgres LISTEN/NOTIFY.
+type PostgresBroker struct {
+	db *pgxpool.Pool
+}
+
+func (b *PostgresBroker) Listen(ctx context.Context, channels []string, handle func(channel string, payload string)) error {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring listener connection: %w", err)
+	}
+
+	// The connection stays subscribed for its whole life, so it must never go back to the pool
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	for _, channel := range channels {
+		if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("error listening on %s: %w", channel, err)
+		}
+	}
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for notification: %w", err)
+		}
+		handle(notification.Channel, notification.Payload)
+	}
+}
+
+func (b *PostgresBroker) Notify(ctx context.Context, channel string, payload string) error {
+	if _, err := b.db.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fmt.Errorf("error notifying %s: %w", channel, err)
+	}
+	return nil
+}
+
+func NewPostgresBroker(db *pgxpool.Pool) *PostgresBroker {
+	return &PostgresBroker{
+		db,
+	}
+}
@@ -0,0 +1,310 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// subscriberBuffer is how many events a client may fall behind before it is cut loose.
+	subscriberBuffer = 64
+	// presenceInterval is how often each instance re-announces its sailor counts.
+	presenceInterval = 10 * time.Second
+	// presenceTTL is how long an instance's counts are trusted without hearing from it again.
+	presenceTTL = 3 * presenceInterval
+	// reconnectDelay is how long to wait before listening again after the broker connection drops.
+	reconnectDelay = 2 * time.Second
+)
+
+// Event is a single message pushed to the clients streaming an ocean.
+type Event struct {
+	Type    string
+	OceanID int
+	Data    []byte
+}
+
+// Subscription is one client's view of an ocean's activity.
+type Subscription struct {
+	OceanID    int
+	events     chan Event
+	overflowed bool
+}
+
+// Events is closed when the subscription ends, either because the hub shut down or
+// because the client could not keep up (see Overflowed).
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Overflowed reports whether the subscription was dropped for falling too far behind.
+func (s *Subscription) Overflowed() bool {
+	return s.overflowed
+}
+
+type instancePresence struct {
+	oceans map[int]int
+	seenAt time.Time
+}
+
+type presenceMessage struct {
+	Instance string         `json:"instance"`
+	Oceans   map[string]int `json:"oceans"`
+}
+
+type oceanEvent struct {
+	Type    string `json:"type"`
+	OceanID int    `json:"ocean_id"`
+}
+
+// Hub delivers ocean events from the broker to the clients connected to this instance and
+// keeps the cluster-wide count of sailors on each ocean.
+type Hub struct {
+	broker     Broker
+	instanceID string
+
+	mu          sync.Mutex
+	subscribers map[int]map[*Subscription]struct{}
+	instances   map[string]instancePresence
+	sailors     map[int]int
+
+	presenceChanged chan struct{}
+	cancel          context.CancelFunc
+	done            sync.WaitGroup
+}
+
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker:          broker,
+		instanceID:      uuid.NewString(),
+		subscribers:     map[int]map[*Subscription]struct{}{},
+		instances:       map[string]instancePresence{},
+		sailors:         map[int]int{},
+		presenceChanged: make(chan struct{}, 1),
+	}
+}
+
+// Start begins listening to the broker and announcing presence in the background.
+func (h *Hub) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	h.done.Add(2)
+	go h.listen(ctx)
+	go h.announcePresence(ctx)
+}
+
+// Close stops the hub and ends every subscription.
+func (h *Hub) Close() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.done.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for oceanID, subs := range h.subscribers {
+		for sub := range subs {
+			close(sub.events)
+		}
+		delete(h.subscribers, oceanID)
+	}
+}
+
+// Subscribe registers a client on an ocean. The current sailor count is queued as its first event.
+func (h *Hub) Subscribe(oceanID int) *Subscription {
+	sub := &Subscription{
+		OceanID: oceanID,
+		events:  make(chan Event, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	if h.subscribers[oceanID] == nil {
+		h.subscribers[oceanID] = map[*Subscription]struct{}{}
+	}
+	h.subscribers[oceanID][sub] = struct{}{}
+	sub.events <- presenceEvent(oceanID, h.sailors[oceanID]+1)
+	h.mu.Unlock()
+
+	h.markPresenceChanged()
+	return sub
+}
+
+// Unsubscribe removes a client. It is safe to call on a subscription the hub already dropped.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	if subs, ok := h.subscribers[sub.OceanID]; ok {
+		if _, ok := subs[sub]; ok {
+			delete(subs, sub)
+			close(sub.events)
+		}
+		if len(subs) == 0 {
+			delete(h.subscribers, sub.OceanID)
+		}
+	}
+	h.mu.Unlock()
+
+	h.markPresenceChanged()
+}
+
+func (h *Hub) listen(ctx context.Context) {
+	defer h.done.Done()
+
+	for {
+		err := h.broker.Listen(ctx, []string{EventsChannel, PresenceChannel}, h.handle)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("ocean stream lost its broker connection", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (h *Hub) handle(channel string, payload string) {
+	switch channel {
+	case EventsChannel:
+		var event oceanEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			slog.Error("malformed ocean event", "payload", payload, "error", err)
+			return
+		}
+		h.broadcast(Event{Type: event.Type, OceanID: event.OceanID, Data: []byte(payload)})
+	case PresenceChannel:
+		var message presenceMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			slog.Error("malformed presence message", "payload", payload, "error", err)
+			return
+		}
+		oceans := make(map[int]int, len(message.Oceans))
+		for key, count := range message.Oceans {
+			if oceanID, err := strconv.Atoi(key); err == nil {
+				oceans[oceanID] = count
+			}
+		}
+		h.mu.Lock()
+		h.instances[message.Instance] = instancePresence{oceans: oceans, seenAt: time.Now()}
+		h.mu.Unlock()
+		h.recountSailors()
+	}
+}
+
+// broadcast queues an event for every local subscriber of its ocean. A subscriber whose
+// buffer is full is dropped rather than allowed to hold up everyone else.
+func (h *Hub) broadcast(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deliverLocked(event)
+}
+
+func (h *Hub) deliverLocked(event Event) {
+	for sub := range h.subscribers[event.OceanID] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.overflowed = true
+			delete(h.subscribers[event.OceanID], sub)
+			close(sub.events)
+		}
+	}
+	if len(h.subscribers[event.OceanID]) == 0 {
+		delete(h.subscribers, event.OceanID)
+	}
+}
+
+// recountSailors sums every live instance's counts and pushes changed totals to local subscribers.
+func (h *Hub) recountSailors() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	totals := map[int]int{}
+	for instanceID, presence := range h.instances {
+		if time.Since(presence.seenAt) > presenceTTL {
+			delete(h.instances, instanceID)
+			continue
+		}
+		for oceanID, count := range presence.oceans {
+			totals[oceanID] += count
+		}
+	}
+
+	for oceanID := range h.sailors {
+		if _, ok := totals[oceanID]; !ok {
+			totals[oceanID] = 0
+		}
+	}
+
+	for oceanID, count := range totals {
+		if h.sailors[oceanID] == count {
+			continue
+		}
+		if count == 0 {
+			delete(h.sailors, oceanID)
+		} else {
+			h.sailors[oceanID] = count
+		}
+		h.deliverLocked(presenceEvent(oceanID, count))
+	}
+}
+
+func (h *Hub) markPresenceChanged() {
+	select {
+	case h.presenceChanged <- struct{}{}:
+	default:
+	}
+}
+
+// announcePresence publishes this instance's sailor counts whenever they change and on a
+// fixed interval, so that other instances can expire us if we disappear.
+func (h *Hub) announcePresence(ctx context.Context) {
+	defer h.done.Done()
+
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-h.presenceChanged:
+		}
+
+		h.mu.Lock()
+		message := presenceMessage{Instance: h.instanceID, Oceans: make(map[string]int, len(h.subscribers))}
+		for oceanID, subs := range h.subscribers {
+			message.Oceans[strconv.Itoa(oceanID)] = len(subs)
+		}
+		h.mu.Unlock()
+
+		payload, err := json.Marshal(message)
+		if err != nil {
+			slog.Error("failed to encode presence", "error", err)
+			continue
+		}
+		if err := h.broker.Notify(ctx, PresenceChannel, string(payload)); err != nil && ctx.Err() == nil {
+			slog.Error("failed to announce presence", "error", err)
+		}
+
+		// Expire instances that have gone quiet even when nobody else is announcing
+		h.recountSailors()
+	}
+}
+
+func presenceEvent(oceanID int, sailors int) Event {
+	data, _ := json.Marshal(map[string]any{
+		"type":     "presence",
+		"ocean_id": oceanID,
+		"sailors":  sailors,
+	})
+	return Event{Type: "presence", OceanID: oceanID, Data: data}
+}
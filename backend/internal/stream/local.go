@@ -0,0 +1,84 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// localBacklog is how many messages a listener may have queued before Notify waits for it.
+const localBacklog = 256
+
+type localMessage struct {
+	channel string
+	payload string
+}
+
+type localListener struct {
+	channels map[string]bool
+	messages chan localMessage
+	done     chan struct{}
+}
+
+// LocalBroker fans messages out within a single process, for running without Postgres.
+type LocalBroker struct {
+	mu        sync.Mutex
+	listeners map[*localListener]struct{}
+}
+
+func (b *LocalBroker) Listen(ctx context.Context, channels []string, handle func(channel string, payload string)) error {
+	listener := &localListener{
+		channels: make(map[string]bool, len(channels)),
+		messages: make(chan localMessage, localBacklog),
+		done:     make(chan struct{}),
+	}
+	for _, channel := range channels {
+		listener.channels[channel] = true
+	}
+
+	b.mu.Lock()
+	b.listeners[listener] = struct{}{}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.listeners, listener)
+		b.mu.Unlock()
+		close(listener.done)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case message := <-listener.messages:
+			handle(message.channel, message.payload)
+		}
+	}
+}
+
+func (b *LocalBroker) Notify(ctx context.Context, channel string, payload string) error {
+	b.mu.Lock()
+	var targets []*localListener
+	for listener := range b.listeners {
+		if listener.channels[channel] {
+			targets = append(targets, listener)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, listener := range targets {
+		select {
+		case listener.messages <- localMessage{channel, payload}:
+		case <-listener.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		listeners: map[*localListener]struct{}{},
+	}
+}
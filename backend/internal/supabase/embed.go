@@ -0,0 +1,8 @@
+package supabase
+
+import "embed"
+
+// Files holds the schema migrations, their rollbacks and the seed data so they ship inside the binary.
+//
+//go:embed migrations/*.sql rollbacks/*.sql seed.sql
+var Files embed.FS
@@ -0,0 +1,13 @@
+-- Bottles a reader has kept from their catches, with optional personal notes
+CREATE TABLE bookmark (
+    user_id UUID NOT NULL,
+    bottle_id INT NOT NULL,
+    note VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, bottle_id)
+);
+
+CREATE INDEX idx_bookmark_user_created ON bookmark(user_id, created_at DESC);
@@ -0,0 +1,35 @@
+-- Lifecycle state of a bottle
+ALTER TABLE bottle ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'afloat'
+    CHECK (status IN ('pending', 'afloat', 'rejected', 'removed'));
+
+CREATE INDEX idx_bottle_status ON bottle(status);
+CREATE INDEX idx_bottle_user_created ON bottle(user_id, created_at DESC);
+
+-- Running catch statistics per bottle. Kept separately from seen_bottles so that
+-- a reader throwing a bottle back does not erase the fact that it was found.
+CREATE TABLE bottle_stats (
+    bottle_id INT PRIMARY KEY,
+    catch_count INT NOT NULL DEFAULT 0,
+    first_caught_at TIMESTAMP,
+    last_caught_at TIMESTAMP,
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE
+);
+
+INSERT INTO bottle_stats (bottle_id, catch_count, first_caught_at, last_caught_at)
+SELECT bottle_id, count(*), min(seen_at), max(seen_at)
+FROM seen_bottles
+GROUP BY bottle_id;
+
+-- Replies sent back to a bottle's author by readers who caught it
+CREATE TABLE bottle_reply (
+    id SERIAL PRIMARY KEY,
+    bottle_id INT NOT NULL,
+    user_id UUID NOT NULL,
+    content VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bottle_reply_bottle_id ON bottle_reply(bottle_id, created_at);
+CREATE INDEX idx_bottle_reply_user_id ON bottle_reply(user_id);
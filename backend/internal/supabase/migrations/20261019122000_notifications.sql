@@ -0,0 +1,31 @@
+-- Notifications delivered to authors and readers
+CREATE TABLE notification (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    bottle_id INT,
+    detail VARCHAR(100),
+    count INT NOT NULL DEFAULT 1,
+    batch_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_user_created ON notification(user_id, updated_at DESC);
+CREATE INDEX idx_notification_user_unread ON notification(user_id) WHERE read_at IS NULL;
+
+-- Catches of the same bottle on the same day collapse into one unread notification
+CREATE UNIQUE INDEX idx_notification_catch_batch ON notification(user_id, bottle_id, batch_date)
+    WHERE type = 'bottle_caught' AND read_at IS NULL;
+
+-- Per-user opt-outs; a missing row means the notification type is enabled
+CREATE TABLE notification_preference (
+    user_id UUID NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, type)
+);
@@ -0,0 +1,55 @@
+-- Ocean activity is published on the ocean_events channel so that every server
+-- instance can fan it out to its streaming clients.
+
+-- A bottle becoming catchable is announced in each ocean it drifts in
+CREATE OR REPLACE FUNCTION notify_bottle_afloat() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = 'afloat' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'afloat') THEN
+        PERFORM pg_notify('ocean_events', json_build_object(
+            'type', 'bottle',
+            'ocean_id', o.id,
+            'bottle', json_build_object(
+                'id', NEW.id,
+                'content', NEW.content,
+                'author', NEW.author,
+                'tag_id', NEW.tag_id,
+                'location_from', NEW.location_from,
+                'created_at', NEW.created_at,
+                'status', NEW.status
+            )
+        )::text)
+        FROM tag_ocean t
+        JOIN ocean o ON o.id = t.ocean_id
+        WHERE t.tag_id = NEW.tag_id
+        AND (o.user_id IS NULL OR o.user_id = NEW.user_id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bottle_afloat_notify
+    AFTER INSERT OR UPDATE OF status ON bottle
+    FOR EACH ROW EXECUTE FUNCTION notify_bottle_afloat();
+
+-- Every catch updates the running count readers see
+CREATE OR REPLACE FUNCTION notify_bottle_caught() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('ocean_events', json_build_object(
+        'type', 'catch',
+        'ocean_id', o.id,
+        'bottle_id', NEW.bottle_id,
+        'catch_count', NEW.catch_count
+    )::text)
+    FROM bottle b
+    JOIN tag_ocean t ON t.tag_id = b.tag_id
+    JOIN ocean o ON o.id = t.ocean_id
+    WHERE b.id = NEW.bottle_id
+    AND b.status = 'afloat'
+    AND (o.user_id IS NULL OR o.user_id = b.user_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bottle_stats_notify
+    AFTER INSERT OR UPDATE OF catch_count ON bottle_stats
+    FOR EACH ROW EXECUTE FUNCTION notify_bottle_caught();
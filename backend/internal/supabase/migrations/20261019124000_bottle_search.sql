@@ -0,0 +1,7 @@
+-- Full-text search over what a bottle says and where it came from
+ALTER TABLE bottle ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(content, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(location_from, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_bottle_search_vector ON bottle USING GIN (search_vector);
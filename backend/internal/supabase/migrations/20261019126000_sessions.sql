@@ -0,0 +1,25 @@
+-- Sign-ins as the identity provider numbers them, tracked so that refresh tokens can be rotated
+-- and a stolen one can end the session it was taken from
+CREATE TABLE user_session (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    remember_me BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    refreshed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_session_user ON user_session(user_id);
+
+-- Every refresh token a session has been handed, by hash. Only the unused one may be exchanged;
+-- presenting a used one means it has leaked
+CREATE TABLE session_refresh_token (
+    token_hash CHAR(64) PRIMARY KEY,
+    session_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES user_session(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_refresh_token_session ON session_refresh_token(session_id);
@@ -0,0 +1,7 @@
+-- Where and when each session was last used, so users can tell their devices apart
+ALTER TABLE user_session
+    ADD COLUMN user_agent TEXT,
+    ADD COLUMN ip_address TEXT,
+    ADD COLUMN last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;
+
+CREATE INDEX idx_user_session_user_live ON user_session(user_id, last_seen_at DESC) WHERE revoked_at IS NULL;
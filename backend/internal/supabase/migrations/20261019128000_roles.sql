@@ -0,0 +1,19 @@
+-- What each user may do beyond the basics: moderators review content, admins also run the place
+ALTER TABLE "user"
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'user'
+        CONSTRAINT user_role_check CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Every change of a user's role and who made it. changed_by is empty for changes made by an
+-- operator with the admin command rather than through the API
+CREATE TABLE role_change (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    old_role TEXT NOT NULL,
+    new_role TEXT NOT NULL,
+    changed_by UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_role_change_user ON role_change(user_id, created_at DESC);
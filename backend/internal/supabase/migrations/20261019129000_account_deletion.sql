@@ -0,0 +1,11 @@
+-- Accounts their owners asked to delete, kept until delete_after so that they can change their
+-- minds. keep_bottles chooses between deleting their bottles and leaving them afloat anonymized
+CREATE TABLE account_deletion (
+    user_id UUID PRIMARY KEY,
+    keep_bottles BOOLEAN NOT NULL DEFAULT FALSE,
+    requested_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    delete_after TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_account_deletion_due ON account_deletion(delete_after);
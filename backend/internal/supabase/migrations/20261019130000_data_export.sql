@@ -0,0 +1,19 @@
+-- Requests to download one's data. A background worker builds the archive and keeps it until
+-- expires_at; a user has at most one request in progress at a time
+CREATE TABLE export_job (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT export_job_status_check CHECK (status IN ('pending', 'running', 'ready', 'failed')),
+    error TEXT,
+    archive BYTEA,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_export_job_user ON export_job(user_id, created_at DESC);
+CREATE INDEX idx_export_job_queue ON export_job(created_at) WHERE status IN ('pending', 'running');
+CREATE UNIQUE INDEX idx_export_job_active ON export_job(user_id) WHERE status IN ('pending', 'running');
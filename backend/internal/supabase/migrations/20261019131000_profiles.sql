@@ -0,0 +1,11 @@
+-- What users show of themselves: a pseudonym that signs their bottles instead of their real
+-- name, a short bio, and an avatar whose files are named by avatar_key
+ALTER TABLE "user"
+    ADD COLUMN display_name TEXT
+        CONSTRAINT user_display_name_length CHECK (char_length(display_name) BETWEEN 3 AND 30),
+    ADD COLUMN bio TEXT
+        CONSTRAINT user_bio_length CHECK (char_length(bio) <= 280),
+    ADD COLUMN avatar_key TEXT;
+
+-- Display names are told apart regardless of case
+CREATE UNIQUE INDEX idx_user_display_name ON "user"(lower(display_name));
@@ -0,0 +1,15 @@
+-- Users whose bottles and replies a user doesn't want to see. bottle_id is the bottle the
+-- author was blocked from, when they were blocked without their ID being known; it is kept
+-- after the bottle is gone so that the block can still be lifted the same way
+CREATE TABLE user_block (
+    blocker_id UUID NOT NULL,
+    blocked_id UUID NOT NULL,
+    bottle_id INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    CONSTRAINT user_block_self_check CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_block_blocked ON user_block(blocked_id);
@@ -0,0 +1,36 @@
+-- Readers' reports of bottles that slipped past moderation. Each reader reports a bottle at
+-- most once, and every pending report on a bottle is resolved by the same decision
+CREATE TABLE bottle_report (
+    id SERIAL PRIMARY KEY,
+    bottle_id INT NOT NULL,
+    reporter_id UUID NOT NULL,
+    reason VARCHAR(20) NOT NULL
+        CHECK (reason IN ('spam', 'harassment', 'hate', 'sexual', 'violence', 'other')),
+    comment VARCHAR(280),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'dismissed', 'removed', 'struck')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    resolved_by UUID,
+    UNIQUE (bottle_id, reporter_id),
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_bottle_report_pending ON bottle_report(created_at) WHERE status = 'pending';
+CREATE INDEX idx_bottle_report_reporter ON bottle_report(reporter_id, created_at DESC);
+
+-- Strikes against authors whose reported bottles were removed
+CREATE TABLE user_strike (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    bottle_id INT,
+    issued_by UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE SET NULL,
+    FOREIGN KEY (issued_by) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_user_strike_user ON user_strike(user_id, created_at DESC);
@@ -0,0 +1,28 @@
+-- Every moderation verdict, moderator decision and admin action, automated or not. actor_id is
+-- empty for decisions made by the content checker or by an operator with the admin command.
+-- Actors and targets aren't foreign keys, so that deleting them doesn't rewrite the record
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(40) NOT NULL,
+    actor_id UUID,
+    target_type VARCHAR(20) NOT NULL,
+    target_id TEXT,
+    reason TEXT,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created ON audit_log(created_at DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, created_at DESC);
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id, created_at DESC);
+
+-- Entries are never changed once written; they are only deleted when they outlive retention
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,12 @@
+-- Oceans that mask profanity take bottles with mild swearing in them, starred out, rather than
+-- turning them away
+ALTER TABLE ocean ADD COLUMN mask_profanity BOOLEAN NOT NULL DEFAULT false;
+
+-- What authors wrote before it was masked, for moderators' eyes only
+CREATE TABLE bottle_original (
+    bottle_id INT PRIMARY KEY,
+    content VARCHAR(100) NOT NULL,
+    author VARCHAR(100),
+    location_from VARCHAR(100),
+    FOREIGN KEY (bottle_id) REFERENCES bottle(id) ON DELETE CASCADE
+);
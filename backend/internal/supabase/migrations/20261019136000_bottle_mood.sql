@@ -0,0 +1,9 @@
+-- The tone the mood classifier reads in a bottle, so that readers can fish for a mood. Bottles
+-- thrown before it ran have none until they are classified with the admin command
+ALTER TABLE bottle
+    ADD COLUMN mood VARCHAR(20)
+        CHECK (mood IN ('hopeful', 'joyful', 'calm', 'sad', 'angry', 'anxious', 'neutral')),
+    ADD COLUMN mood_score DOUBLE PRECISION
+        CHECK (mood_score BETWEEN -1 AND 1);
+
+CREATE INDEX idx_bottle_mood ON bottle(mood) WHERE status = 'afloat';
@@ -0,0 +1,12 @@
+-- The language the detector reads a bottle's content as, 'und' when it can't tell. Bottles
+-- thrown before it ran have none until they are detected with the admin command
+ALTER TABLE bottle
+    ADD COLUMN language VARCHAR(8)
+        CHECK (language IN ('en', 'es', 'fr', 'de', 'it', 'pt', 'nl', 'und'));
+
+CREATE INDEX idx_bottle_language ON bottle(language) WHERE status = 'afloat';
+
+-- The languages a reader can read; bottles caught are limited to them unless it is empty
+ALTER TABLE "user"
+    ADD COLUMN languages TEXT[] NOT NULL DEFAULT '{}'
+        CONSTRAINT user_languages_known CHECK (languages <@ ARRAY['en', 'es', 'fr', 'de', 'it', 'pt', 'nl']::TEXT[]);
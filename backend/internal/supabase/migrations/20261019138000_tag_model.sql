@@ -0,0 +1,9 @@
+-- Tag classifiers trained by the admin command on the bottles writers tagged, with how each
+-- did on the bottles held out of its training. The server suggests tags with the latest
+CREATE TABLE tag_model (
+    id SERIAL PRIMARY KEY,
+    model JSONB NOT NULL,
+    report JSONB NOT NULL,
+    examples INT NOT NULL CHECK (examples > 0),
+    trained_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
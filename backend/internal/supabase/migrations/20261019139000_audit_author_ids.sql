@@ -0,0 +1,10 @@
+-- Screening verdicts no longer name the bottle's author, so that they can outlive the author's
+-- account. Strip the author from those written before, the one time the append-only log is
+-- rewritten
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_no_update;
+
+UPDATE audit_log
+SET details = details - 'author_id'
+WHERE action = 'bottle.screened' AND details ? 'author_id';
+
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_no_update;
@@ -0,0 +1,10 @@
+-- Where a bottle's tag came from: its writer, the tag classifier, or the Default fallback.
+-- Only writers' choices are learned from, so that the classifier never trains on its own guesses
+ALTER TABLE bottle
+    ADD COLUMN tag_source VARCHAR(10) NOT NULL DEFAULT 'writer'
+        CHECK (tag_source IN ('writer', 'suggested', 'default'));
+
+-- Bottles thrown before tags were suggested were only ever given Default by falling back on it
+UPDATE bottle
+SET tag_source = 'default'
+WHERE tag_id = (SELECT id FROM tag WHERE name = 'Default');
@@ -0,0 +1,4 @@
+-- The hash of the one password recovery token an account may still use. Resetting the password
+-- clears it, and asking for another token replaces it, so each token works once
+ALTER TABLE local_identity
+    ADD COLUMN recovery_token_hash CHAR(64);
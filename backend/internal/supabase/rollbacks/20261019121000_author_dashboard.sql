@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS bottle_reply;
+DROP TABLE IF EXISTS bottle_stats;
+DROP INDEX IF EXISTS idx_bottle_user_created;
+DROP INDEX IF EXISTS idx_bottle_status;
+ALTER TABLE bottle DROP COLUMN IF EXISTS status;
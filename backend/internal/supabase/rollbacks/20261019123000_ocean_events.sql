@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS bottle_stats_notify ON bottle_stats;
+DROP FUNCTION IF EXISTS notify_bottle_caught();
+DROP TRIGGER IF EXISTS bottle_afloat_notify ON bottle;
+DROP FUNCTION IF EXISTS notify_bottle_afloat();
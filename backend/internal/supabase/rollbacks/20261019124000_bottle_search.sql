@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bottle_search_vector;
+ALTER TABLE bottle DROP COLUMN IF EXISTS search_vector;
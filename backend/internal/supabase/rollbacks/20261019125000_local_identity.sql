@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS local_session;
+DROP TABLE IF EXISTS local_identity;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS session_refresh_token;
+DROP TABLE IF EXISTS user_session;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_user_session_user_live;
+
+ALTER TABLE user_session
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS role_change;
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS role;
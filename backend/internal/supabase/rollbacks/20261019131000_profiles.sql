@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_user_display_name;
+
+ALTER TABLE "user"
+    DROP COLUMN IF EXISTS avatar_key,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS display_name;
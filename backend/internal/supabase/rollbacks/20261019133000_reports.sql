@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_strike;
+
+DROP TABLE IF EXISTS bottle_report;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bottle_original;
+
+ALTER TABLE ocean DROP COLUMN IF EXISTS mask_profanity;
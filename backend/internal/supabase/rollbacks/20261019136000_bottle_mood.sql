@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_bottle_mood;
+
+ALTER TABLE bottle DROP COLUMN IF EXISTS mood_score;
+ALTER TABLE bottle DROP COLUMN IF EXISTS mood;
@@ -0,0 +1,5 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS languages;
+
+DROP INDEX IF EXISTS idx_bottle_language;
+
+ALTER TABLE bottle DROP COLUMN IF EXISTS language;
@@ -0,0 +1 @@
+-- The authors stripped from screening verdicts are gone for good; there is nothing to undo
@@ -0,0 +1 @@
+ALTER TABLE bottle DROP COLUMN IF EXISTS tag_source;
@@ -0,0 +1 @@
+ALTER TABLE local_identity DROP COLUMN IF EXISTS recovery_token_hash;
@@ -0,0 +1,20 @@
+-- System rows the application relies on. Safe to run any number of times.
+
+INSERT INTO tag (name, color)
+SELECT 'Default', '#90A4AE'
+WHERE NOT EXISTS (SELECT 1 FROM tag WHERE name = 'Default');
+
+INSERT INTO tag (name, color)
+SELECT 'Personal', '#7986CB'
+WHERE NOT EXISTS (SELECT 1 FROM tag WHERE name = 'Personal');
+
+INSERT INTO ocean (name, description)
+SELECT 'Default', 'The open ocean, where every untagged bottle drifts'
+WHERE NOT EXISTS (SELECT 1 FROM ocean WHERE name = 'Default' AND user_id IS NULL);
+
+INSERT INTO tag_ocean (tag_id, ocean_id)
+SELECT t.id, o.id
+FROM tag t, ocean o
+WHERE t.name = 'Default'
+AND o.name = 'Default' AND o.user_id IS NULL
+ON CONFLICT (tag_id, ocean_id) DO NOTHING;
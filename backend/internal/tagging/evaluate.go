@@ -0,0 +1,161 @@
+package tagging
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// Thresholds are the scores a report says how auto-applying suggestions above would fare at.
+var Thresholds = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Report is how a model trained on part of the tagged bottles does on the rest.
+type Report struct {
+	Trained int `json:"trained"`
+	Tested  int `json:"tested"`
+	// Accuracy is the share of held-out bottles whose best suggestion is their tag, and
+	// TopThree that of bottles whose tag is among the first three.
+	Accuracy  float64           `json:"accuracy"`
+	TopThree  float64           `json:"top_three"`
+	Tags      map[int]TagScores `json:"tags"`
+	AutoApply []ThresholdScores `json:"auto_apply"`
+}
+
+// TagScores are the precision and recall of the best suggestion for one tag, with support
+// being how many held-out bottles had it.
+type TagScores struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	Support   int     `json:"support"`
+}
+
+// ThresholdScores are how auto-applying the best suggestion would fare if it had to score at
+// least Threshold: on what share of bottles it would apply one, and how often rightly.
+type ThresholdScores struct {
+	Threshold float64 `json:"threshold"`
+	Coverage  float64 `json:"coverage"`
+	Precision float64 `json:"precision"`
+}
+
+// Evaluate trains a model on the examples but every holdout-th, and scores it on those.
+func Evaluate(examples []Example, holdout int) (Report, error) {
+	if holdout < 2 {
+		return Report{}, fmt.Errorf("the holdout must be at least 2, got %d", holdout)
+	}
+
+	var training, testing []Example
+	for i, example := range examples {
+		if i%holdout == holdout-1 {
+			testing = append(testing, example)
+		} else {
+			training = append(training, example)
+		}
+	}
+	if len(testing) == 0 {
+		return Report{}, fmt.Errorf("too few tagged bottles to hold any out of training")
+	}
+
+	model, err := Train(training)
+	if err != nil {
+		return Report{}, err
+	}
+
+	type counts struct{ tp, fp, fn int }
+	byTag := map[int]*counts{}
+	tag := func(id int) *counts {
+		if byTag[id] == nil {
+			byTag[id] = &counts{}
+		}
+		return byTag[id]
+	}
+	applied := make([]counts, len(Thresholds))
+	var right, topThree int
+
+	for _, example := range testing {
+		suggestions := model.Suggest(example.Text, 3)
+		if slices.ContainsFunc(suggestions, func(s Suggestion) bool { return s.TagID == example.TagID }) {
+			topThree++
+		}
+		if len(suggestions) == 0 {
+			tag(example.TagID).fn++
+			continue
+		}
+
+		best := suggestions[0]
+		correct := best.TagID == example.TagID
+		if correct {
+			right++
+			tag(example.TagID).tp++
+		} else {
+			tag(best.TagID).fp++
+			tag(example.TagID).fn++
+		}
+		for i, threshold := range Thresholds {
+			switch {
+			case best.Score < threshold:
+			case correct:
+				applied[i].tp++
+			default:
+				applied[i].fp++
+			}
+		}
+	}
+
+	r := Report{
+		Trained:  len(training),
+		Tested:   len(testing),
+		Accuracy: ratio(right, len(testing)),
+		TopThree: ratio(topThree, len(testing)),
+		Tags:     map[int]TagScores{},
+	}
+	for id, c := range byTag {
+		r.Tags[id] = TagScores{
+			Precision: ratio(c.tp, c.tp+c.fp),
+			Recall:    ratio(c.tp, c.tp+c.fn),
+			Support:   c.tp + c.fn,
+		}
+	}
+	for i, threshold := range Thresholds {
+		r.AutoApply = append(r.AutoApply, ThresholdScores{
+			Threshold: threshold,
+			Coverage:  ratio(applied[i].tp+applied[i].fp, len(testing)),
+			Precision: ratio(applied[i].tp, applied[i].tp+applied[i].fp),
+		})
+	}
+	return r, nil
+}
+
+// ratio is the share n is of total, rounded; nothing out of nothing is a perfect score.
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return math.Round(float64(n)/float64(total)*10000) / 10000
+}
+
+// Write prints the report as tables, naming tags by names where it can.
+func (r Report) Write(w io.Writer, names map[int]string) {
+	fmt.Fprintf(w, "Trained on %d bottle(s), tested on %d\n", r.Trained, r.Tested)
+	fmt.Fprintf(w, "Accuracy %.4f, tag among the first three %.4f\n\n", r.Accuracy, r.TopThree)
+
+	ids := make([]int, 0, len(r.Tags))
+	for id := range r.Tags {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	fmt.Fprintf(w, "%-30s %9s %9s %8s\n", "tag", "precision", "recall", "support")
+	for _, id := range ids {
+		name, ok := names[id]
+		if !ok {
+			name = fmt.Sprintf("#%d", id)
+		}
+		s := r.Tags[id]
+		fmt.Fprintf(w, "%-30s %9.4f %9.4f %8d\n", name, s.Precision, s.Recall, s.Support)
+	}
+
+	fmt.Fprintf(w, "\n%-30s %9s %9s\n", "auto-apply from score", "coverage", "precision")
+	for _, s := range r.AutoApply {
+		fmt.Fprintf(w, "%-30.1f %9.4f %9.4f\n", s.Threshold, s.Coverage, s.Precision)
+	}
+}
@@ -0,0 +1,206 @@
+// Package tagging suggests tags for a bottle from its content, so that fewer bottles drift
+// under the Default tag. Its model is trained offline on the bottles writers did tag.
+package tagging
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// centroidTerms is how many of its heaviest words a tag's centroid keeps, which bounds the
+// size of the model whatever the number of bottles it learnt from.
+const centroidTerms = 300
+
+// stopwords carry no hint of a topic in any of the languages bottles are mostly written in.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		the and for are but not you your yours all any can had her was one our out has have him his
+		how its may who did get got let she too use this that with from they them then than there
+		their what when where which while will would could should about into just like some more
+		most very been were also only over such here it's i'm don't can't im dont cant
+		los las una uno por para con que del pero como mas muy este esta estos estas
+		les des une pour avec que qui dans pas sur est mais tout tous cette ces
+		der die das und ein eine mit von den dem ist nicht auf für sich auch
+	`) {
+		stopwords[word] = true
+	}
+}
+
+// Example is a bottle the model learns from: its content, and the tag its writer gave it.
+type Example struct {
+	Text  string
+	TagID int
+}
+
+// Suggestion is a tag the model reads in a text, scored by how close the text is to the
+// bottles of that tag, from 0 to 1.
+type Suggestion struct {
+	TagID int     `json:"tag_id"`
+	Score float64 `json:"score"`
+}
+
+// Model is a TF-IDF nearest-centroid classifier: a text is suggested the tags whose bottles'
+// average word weights point the most the same way as its own. It is stored as JSON.
+type Model struct {
+	// IDF weighs each word the model knows by how few bottles use it.
+	IDF map[string]float64 `json:"idf"`
+	// Centroids are the unit-length average of each tag's bottles, by word.
+	Centroids map[int]map[string]float64 `json:"centroids"`
+	// Examples is how many bottles of each tag the model learnt from.
+	Examples map[int]int `json:"examples"`
+}
+
+// Train learns a model from tagged bottles.
+func Train(examples []Example) (*Model, error) {
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no tagged bottles to learn from")
+	}
+
+	documents := make([][]string, len(examples))
+	frequency := map[string]int{}
+	for i, example := range examples {
+		documents[i] = words(example.Text)
+		seen := map[string]bool{}
+		for _, word := range documents[i] {
+			if !seen[word] {
+				seen[word] = true
+				frequency[word]++
+			}
+		}
+	}
+
+	m := &Model{
+		IDF:       make(map[string]float64, len(frequency)),
+		Centroids: map[int]map[string]float64{},
+		Examples:  map[int]int{},
+	}
+	// Smoothed as if one more bottle used every word, so that no word weighs nothing
+	for word, count := range frequency {
+		m.IDF[word] = math.Log(float64(1+len(examples))/float64(1+count)) + 1
+	}
+
+	sums := map[int]map[string]float64{}
+	for i, example := range examples {
+		if sums[example.TagID] == nil {
+			sums[example.TagID] = map[string]float64{}
+		}
+		for word, weight := range m.vector(documents[i]) {
+			sums[example.TagID][word] += weight
+		}
+		m.Examples[example.TagID]++
+	}
+	for tagID, sum := range sums {
+		m.Centroids[tagID] = normalize(heaviest(sum, centroidTerms))
+	}
+
+	// Words no centroid kept can't sway a suggestion
+	for word := range m.IDF {
+		kept := false
+		for _, centroid := range m.Centroids {
+			if _, ok := centroid[word]; ok {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			delete(m.IDF, word)
+		}
+	}
+
+	return m, nil
+}
+
+// Suggest returns up to n tags for text, the closest first. Tags the text has nothing in
+// common with are not suggested.
+func (m *Model) Suggest(text string, n int) []Suggestion {
+	vector := m.vector(words(text))
+	if len(vector) == 0 {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	for tagID, centroid := range m.Centroids {
+		var score float64
+		for word, weight := range vector {
+			score += weight * centroid[word]
+		}
+		if score > 0 {
+			suggestions = append(suggestions, Suggestion{tagID, math.Round(score*1000) / 1000})
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b Suggestion) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.TagID, b.TagID))
+	})
+	return suggestions[:min(n, len(suggestions))]
+}
+
+// vector is the unit-length TF-IDF vector of a text's words, leaving out words the model
+// doesn't know.
+func (m *Model) vector(words []string) map[string]float64 {
+	vector := map[string]float64{}
+	for _, word := range words {
+		if idf, ok := m.IDF[word]; ok {
+			vector[word] += idf
+		}
+	}
+	return normalize(vector)
+}
+
+// heaviest keeps the n heaviest words of a vector.
+func heaviest(vector map[string]float64, n int) map[string]float64 {
+	if len(vector) <= n {
+		return vector
+	}
+	words := make([]string, 0, len(vector))
+	for word := range vector {
+		words = append(words, word)
+	}
+	slices.SortFunc(words, func(a, b string) int {
+		return cmp.Or(cmp.Compare(vector[b], vector[a]), strings.Compare(a, b))
+	})
+
+	kept := make(map[string]float64, n)
+	for _, word := range words[:n] {
+		kept[word] = vector[word]
+	}
+	return kept
+}
+
+func normalize(vector map[string]float64) map[string]float64 {
+	var norm float64
+	for _, weight := range vector {
+		norm += weight * weight
+	}
+	norm = math.Sqrt(norm)
+	for word := range vector {
+		vector[word] /= norm
+	}
+	return vector
+}
+
+// words splits text into the lowercase words that may hint at its topic: three letters or
+// longer, and not stopwords. Plurals are folded into their singular, as in waves and wave.
+func words(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		word = strings.Trim(word, "'")
+		if utf8.RuneCountInString(word) < 3 || stopwords[word] {
+			continue
+		}
+		if len(word) > 4 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = word[:len(word)-1]
+		}
+		words = append(words, word)
+	}
+	return words
+}
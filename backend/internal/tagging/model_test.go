@@ -0,0 +1,141 @@
+package tagging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	ocean = iota + 1
+	music
+	food
+)
+
+var examples = []Example{
+	{"The waves were huge today, surfing until the sun went down over the ocean", ocean},
+	{"Found a starfish on the beach while the tide was out", ocean},
+	{"Sailing past the lighthouse with dolphins jumping beside the boat", ocean},
+	{"Swimming in the sea at dawn, the water was cold and the waves calm", ocean},
+	{"Collecting shells along the shore as the tide comes in", ocean},
+	{"Learning guitar chords for my favourite song, my fingers hurt", music},
+	{"The concert last night was loud, the band played every song we wanted", music},
+	{"Humming a melody I can't get out of my head, maybe I'll record it", music},
+	{"Practising piano scales before the recital next week", music},
+	{"Our band finally finished writing the chorus of the new song", music},
+	{"Baked bread this morning, the kitchen smells of warm crust", food},
+	{"Grandma's soup recipe with garlic, onions and too much pepper", food},
+	{"Tried cooking pasta from scratch, the sauce was the best part", food},
+	{"Pancakes with honey and berries for breakfast on a lazy sunday", food},
+	{"The bakery on the corner sells the best cinnamon bread", food},
+}
+
+func TestSuggest(t *testing.T) {
+	model, err := Train(examples)
+	if err != nil {
+		t.Fatalf("Train: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		tag  int
+	}{
+		{"Waves crashing on the beach as the tide turns", ocean},
+		{"Can't stop playing this song on the guitar", music},
+		{"Fresh bread and soup for dinner tonight", food},
+	}
+	for _, test := range tests {
+		suggestions := model.Suggest(test.text, 3)
+		if len(suggestions) == 0 || suggestions[0].TagID != test.tag {
+			t.Errorf("Suggest(%q) = %+v, want tag %d first", test.text, suggestions, test.tag)
+			continue
+		}
+		for i, suggestion := range suggestions {
+			if suggestion.Score <= 0 || suggestion.Score > 1 {
+				t.Errorf("Suggest(%q)[%d] score = %v, want within (0, 1]", test.text, i, suggestion.Score)
+			}
+			if i > 0 && suggestion.Score > suggestions[i-1].Score {
+				t.Errorf("Suggest(%q) = %+v, want the best first", test.text, suggestions)
+			}
+		}
+	}
+
+	// Nothing the model knows, nothing to suggest
+	for _, text := range []string{"", "hi :)", "zxqv blorp"} {
+		if suggestions := model.Suggest(text, 3); len(suggestions) != 0 {
+			t.Errorf("Suggest(%q) = %+v, want none", text, suggestions)
+		}
+	}
+	if suggestions := model.Suggest("the tide and the song", 1); len(suggestions) != 1 {
+		t.Errorf("Suggest(n = 1) = %+v, want one suggestion", suggestions)
+	}
+}
+
+func TestModelJSON(t *testing.T) {
+	model, err := Train(examples)
+	if err != nil {
+		t.Fatalf("Train: %v", err)
+	}
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded Model
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	const text = "Dolphins beside the boat near the lighthouse"
+	want, got := model.Suggest(text, 3), decoded.Suggest(text, 3)
+	if len(got) != len(want) {
+		t.Fatalf("decoded Suggest = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("decoded Suggest = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestTrainWithoutExamples(t *testing.T) {
+	if _, err := Train(nil); err == nil {
+		t.Error("Train(nil) succeeded, want an error")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	report, err := Evaluate(examples, 5)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if report.Trained != 12 || report.Tested != 3 {
+		t.Errorf("Evaluate trained on %d and tested on %d, want 12 and 3", report.Trained, report.Tested)
+	}
+	if report.Accuracy < 2.0/3 || report.TopThree < report.Accuracy {
+		t.Errorf("Evaluate accuracy = %v, top three = %v", report.Accuracy, report.TopThree)
+	}
+	if len(report.AutoApply) != len(Thresholds) {
+		t.Fatalf("Evaluate auto-apply rows = %d, want one per threshold", len(report.AutoApply))
+	}
+	for i := 1; i < len(report.AutoApply); i++ {
+		if report.AutoApply[i].Coverage > report.AutoApply[i-1].Coverage {
+			t.Errorf("auto-apply coverage rises with the threshold: %+v", report.AutoApply)
+		}
+	}
+
+	var out bytes.Buffer
+	report.Write(&out, map[int]string{ocean: "Ocean", music: "Music"})
+	for _, want := range []string{"tested on 3", "Ocean", "Music"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Write = %q, want it to mention %q", out.String(), want)
+		}
+	}
+
+	if _, err := Evaluate(examples, 1); err == nil {
+		t.Error("Evaluate with a holdout of 1 succeeded, want an error")
+	}
+	if _, err := Evaluate(examples[:2], 5); err == nil {
+		t.Error("Evaluate with too few examples succeeded, want an error")
+	}
+}
@@ -0,0 +1,113 @@
+package tagging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackmit/internal/errs"
+	"hackmit/internal/storage"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSuggestions is how many tags a bottle is suggested at most.
+const maxSuggestions = 3
+
+// loaded is the model the suggester uses, and the ID it is stored under.
+type loaded struct {
+	id    int
+	model *Model
+}
+
+// Suggester suggests tags with the latest model trained, picking up retrained ones in the
+// background. Until a model has been trained, it suggests nothing.
+type Suggester struct {
+	models    storage.TagRepository
+	autoApply float64
+	refresh   time.Duration
+	current   atomic.Pointer[loaded]
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func NewSuggester(models storage.TagRepository, autoApply float64, refresh time.Duration) *Suggester {
+	return &Suggester{
+		models:    models,
+		autoApply: autoApply,
+		refresh:   refresh,
+	}
+}
+
+// Load switches to the latest model trained, if it isn't the one in use already.
+func (s *Suggester) Load(ctx context.Context) error {
+	latest, err := s.models.GetLatestTagModel(ctx)
+	if errs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current := s.current.Load(); current != nil && current.id == latest.ID {
+		return nil
+	}
+
+	var model Model
+	if err := json.Unmarshal(latest.Model, &model); err != nil {
+		return fmt.Errorf("error reading tag model %d: %w", latest.ID, err)
+	}
+	s.current.Store(&loaded{latest.ID, &model})
+	return nil
+}
+
+// Suggest returns the tags the latest model reads in text, the closest first.
+func (s *Suggester) Suggest(text string) []Suggestion {
+	current := s.current.Load()
+	if current == nil {
+		return nil
+	}
+	return current.model.Suggest(text, maxSuggestions)
+}
+
+// Confident reports whether a suggestion scores high enough to be applied without asking.
+func (s *Suggester) Confident(suggestion Suggestion) bool {
+	return suggestion.Score >= s.autoApply
+}
+
+// Start loads the latest model, then looks for a newer one in the background until Close.
+func (s *Suggester) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.done.Add(1)
+	go s.watch(ctx)
+}
+
+// Close stops looking for newer models.
+func (s *Suggester) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.done.Wait()
+}
+
+func (s *Suggester) watch(ctx context.Context) {
+	defer s.done.Done()
+
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Load(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to load the tag model", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}